@@ -0,0 +1,145 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr
+
+import (
+	"log/slog"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// DatadogFields returns the fields an error is recognized by
+// Datadog APM error tracking with, keyed by their (dotted) names:
+// "error.kind" - the error's type (see below), "error.message" - the error's message,
+// "error.stack" - the error's stack trace frames, one per line, omitted if there are none.
+// The error's type is its code (see [CodeOf]), if set, or its kind (see [KindOf]), if set,
+// or the Go type of its root cause, otherwise ("error" for a root cause created with [New] / [Errorf]).
+// Returns nil for a nil error.
+//
+// Example:
+//
+//	logrus.WithFields(xerr.DatadogFields(err)).Error("could not process order")
+func DatadogFields(err error) map[string]any {
+	return attrsMap("error.", DatadogAttr(err))
+}
+
+// DatadogAttr returns an "error" group attribute holding the "kind", "message"
+// and "stack" attributes, see [DatadogFields].
+// Returns an empty attribute (which [slog.Handler]s ignore) for a nil error.
+//
+// Example:
+//
+//	slog.Error("could not process order", xerr.DatadogAttr(err))
+func DatadogAttr(err error) slog.Attr {
+	if err == nil {
+		return slog.Attr{}
+	}
+
+	attrs := make([]slog.Attr, 2, 3)
+	attrs[0] = slog.String("kind", errorType(err))
+	attrs[1] = slog.String("message", err.Error())
+	if stack := stackString(Frames(err)); stack != "" {
+		attrs = append(attrs, slog.String("stack", stack))
+	}
+
+	return slog.Attr{Key: "error", Value: slog.GroupValue(attrs...)}
+}
+
+// ECSFields returns the error fields defined by Elastic Common Schema,
+// keyed by their (dotted) names:
+// "error.type" - the error's type (see [DatadogFields]), "error.message" - the error's message,
+// "error.stack_trace" - the error's stack trace frames, one per line,
+// "error.code" - the error's code (see [CodeOf]), "error.id" - the error's ID (see [ID]).
+// Fields with no value are omitted.
+// Returns nil for a nil error.
+//
+// Example:
+//
+//	logrus.WithFields(xerr.ECSFields(err)).Error("could not process order")
+func ECSFields(err error) map[string]any {
+	return attrsMap("error.", ECSAttr(err))
+}
+
+// ECSAttr returns an "error" group attribute holding the "type", "message",
+// "stack_trace", "code" and "id" attributes, see [ECSFields].
+// Returns an empty attribute (which [slog.Handler]s ignore) for a nil error.
+//
+// Example:
+//
+//	slog.Error("could not process order", xerr.ECSAttr(err))
+func ECSAttr(err error) slog.Attr {
+	if err == nil {
+		return slog.Attr{}
+	}
+
+	attrs := make([]slog.Attr, 2, 5)
+	attrs[0] = slog.String("type", errorType(err))
+	attrs[1] = slog.String("message", err.Error())
+	if stack := stackString(Frames(err)); stack != "" {
+		attrs = append(attrs, slog.String("stack_trace", stack))
+	}
+	if code := CodeOf(err); code != "" {
+		attrs = append(attrs, slog.String("code", string(code)))
+	}
+	if id := ID(err); id != "" {
+		attrs = append(attrs, slog.String("id", id))
+	}
+
+	return slog.Attr{Key: "error", Value: slog.GroupValue(attrs...)}
+}
+
+// attrsMap returns the attributes of a group attribute keyed by their prefixed names.
+// Returns nil for an empty attribute.
+func attrsMap(prefix string, attr slog.Attr) map[string]any {
+	if attr.Equal(slog.Attr{}) {
+		return nil
+	}
+
+	attrs := attr.Value.Group()
+	result := make(map[string]any, len(attrs))
+	for _, a := range attrs {
+		result[prefix+a.Key] = a.Value.Any()
+	}
+
+	return result
+}
+
+// errorType returns the type of an error: its code, if set, or its kind, if set,
+// or the Go type of its root cause, otherwise.
+func errorType(err error) string {
+	if code := CodeOf(err); code != "" {
+		return string(code)
+	}
+	if kind := KindOf(err); kind != KindOther {
+		return kind.String()
+	}
+
+	root := RootCause(err)
+	if _, ok := root.(*stackError); ok { // created with New / Errorf, an unexported type.
+		return "error"
+	}
+
+	return reflect.TypeOf(root).String()
+}
+
+// stackString returns the given frames, one per line,
+// in the "<function> <file>:<line>" format.
+func stackString(frames []Frame) string {
+	var sb strings.Builder
+	for idx, f := range frames {
+		if idx > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(f.Function)
+		sb.WriteString(" ")
+		sb.WriteString(f.File)
+		sb.WriteString(":")
+		sb.WriteString(strconv.Itoa(f.Line))
+	}
+
+	return sb.String()
+}
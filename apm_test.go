@@ -0,0 +1,142 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr_test
+
+import (
+	"errors"
+	"io/fs"
+	"log/slog"
+	"regexp"
+	"testing"
+
+	"github.com/actforgood/xerr"
+)
+
+func TestDatadogFields(t *testing.T) {
+	t.Parallel()
+
+	t.Run("error with stack", testDatadogFieldsErrorWithStack)
+	t.Run("error type", testDatadogFieldsErrorType)
+	t.Run("nil error", testDatadogFieldsNilError)
+}
+
+func testDatadogFieldsErrorWithStack(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	err := xerr.Wrap(fs.ErrNotExist, "could not open config")
+
+	// act
+	fields := xerr.DatadogFields(err)
+
+	// assert
+	assertEqual(t, 3, len(fields))
+	assertEqual(t, "*errors.errorString", fields["error.kind"])
+	assertEqual(t, "could not open config: file does not exist", fields["error.message"])
+	stack, _ := fields["error.stack"].(string)
+	matched, _ := regexp.MatchString(
+		"^github\\.com/actforgood/xerr_test\\.testDatadogFieldsErrorWithStack .+apm_test\\.go:\\d+\n",
+		stack,
+	)
+	if !assertTrue(t, matched) {
+		t.Log("stack", stack)
+	}
+}
+
+func testDatadogFieldsErrorType(t *testing.T) {
+	t.Parallel()
+
+	tests := [...]struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{
+			name:     "code",
+			err:      xerr.WithKind(xerr.WithCode(xerr.New("not found"), "USER_NOT_FOUND"), xerr.KindNotFound),
+			expected: "USER_NOT_FOUND",
+		},
+		{
+			name:     "kind",
+			err:      xerr.NotFound("user %d", 7),
+			expected: "not_found",
+		},
+		{
+			name:     "root cause created by this package",
+			err:      xerr.Wrap(xerr.New("not found"), "could not load user"),
+			expected: "error",
+		},
+		{
+			name:     "foreign root cause",
+			err:      xerr.Wrap(&fs.PathError{Op: "open", Path: "config.yml", Err: fs.ErrNotExist}, "could not load config"),
+			expected: "*errors.errorString",
+		},
+	}
+
+	for _, testData := range tests {
+		test := testData // capture range variable
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			// act
+			fields := xerr.DatadogFields(test.err)
+
+			// assert
+			assertEqual(t, test.expected, fields["error.kind"])
+		})
+	}
+}
+
+func testDatadogFieldsNilError(t *testing.T) {
+	t.Parallel()
+
+	// act
+	fields := xerr.DatadogFields(nil)
+
+	// assert
+	assertNil(t, fields)
+}
+
+func TestDatadogAttr(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	err := errors.New("std error")
+
+	// act
+	attr := xerr.DatadogAttr(err)
+	nilAttr := xerr.DatadogAttr(nil)
+
+	// assert
+	assertTrue(t, attr.Equal(slog.Group("error",
+		slog.String("kind", "*errors.errorString"),
+		slog.String("message", "std error"),
+	)))
+	assertTrue(t, nilAttr.Equal(slog.Attr{}))
+}
+
+func TestECSFields(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	err, _ := xerr.FromJSON([]byte(`{"message":"not found","severity":"error","code":"USER_NOT_FOUND",` +
+		`"id":"AB12CD","stack":[{"function":"main.main","file":"/app/main.go","line":7}]}`))
+
+	// act
+	fields := xerr.ECSFields(err)
+	stdFields := xerr.ECSFields(errors.New("std error"))
+	nilFields := xerr.ECSFields(nil)
+
+	// assert
+	assertEqual(t, 5, len(fields))
+	assertEqual(t, "USER_NOT_FOUND", fields["error.type"])
+	assertEqual(t, "not found", fields["error.message"])
+	assertEqual(t, "USER_NOT_FOUND", fields["error.code"])
+	assertEqual(t, "AB12CD", fields["error.id"])
+	assertEqual(t, "main.main /app/main.go:7", fields["error.stack_trace"])
+	assertEqual(t, map[string]any{"error.type": "*errors.errorString", "error.message": "std error"}, stdFields)
+	assertNil(t, nilFields)
+}
@@ -0,0 +1,97 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr
+
+import "time"
+
+// Append merges errs into dst, in the spirit of hashicorp/go-multierror's Append,
+// without the need of managing a *MultiError explicitly:
+//   - nil errors are skipped;
+//   - errs which are [MultiError]s are flattened, their errors get appended;
+//   - if dst is a non-nil *MultiError, errors are added to it, and it is returned;
+//   - otherwise, if there is a single error, it is returned as it is, or,
+//     if there are more errors, a new [MultiError] holding all of them is returned.
+//
+// Returns nil if dst is nil and there is no error to append, so the result
+// can be used directly in return statements.
+//
+// Example:
+//
+//	var err error
+//	for _, item := range items {
+//		err = xerr.Append(err, process(item))
+//	}
+//
+//	return err
+func Append(dst error, errs ...error) error {
+	if mErr, ok := dst.(*MultiError); ok && mErr != nil {
+		for _, err := range errs {
+			mErr = appendFlattened(mErr, err)
+		}
+
+		return mErr
+	}
+
+	var mErr *MultiError
+	if dst != nil {
+		if _, ok := dst.(*MultiError); !ok { // skip typed nil *MultiError.
+			mErr = mErr.Add(dst)
+		}
+	}
+	for _, err := range errs {
+		mErr = appendFlattened(mErr, err)
+	}
+
+	return mErr.ErrOrNil()
+}
+
+// appendFlattened adds err to mErr. If err is a [MultiError],
+// its errors are added, recursively flattened.
+func appendFlattened(mErr *MultiError, err error) *MultiError {
+	if errMErr, ok := err.(*MultiError); ok {
+		for _, e := range errMErr.Errors() {
+			mErr = appendFlattened(mErr, e)
+		}
+
+		return mErr
+	}
+
+	return mErr.Add(err)
+}
+
+// Join returns an error combining the given errors, as a stack trace aware
+// replacement for [errors.Join]:
+//   - nil errors are dropped;
+//   - if there is no error, nil is returned;
+//   - if there is a single error, it is returned annotated with the stack trace
+//     at the point Join was called (see [Wrap]);
+//   - otherwise, a [MultiError] holding all of them is returned, annotated with
+//     the stack trace at the point Join was called.
+//
+// The message of the returned error is the same as the one of the single error,
+// respectively of the MultiError, and errors.Is / errors.As reach all joined errors.
+//
+// Example:
+//
+//	return xerr.Join(rows.Err(), rows.Close())
+func Join(errs ...error) error {
+	var mErr *MultiError
+	for _, err := range errs {
+		mErr = mErr.Add(err)
+	}
+	err := mErr.ErrOrNil()
+	if err == nil {
+		return nil
+	}
+
+	wErr := &stackError{
+		origErr:   err,
+		createdAt: time.Now(),
+	}
+	wErr.stackPCs, wErr.stackBuf = wrapCallStack(err, 0, maxStackFrames)
+
+	return created(wErr)
+}
@@ -0,0 +1,184 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/actforgood/xerr"
+)
+
+func TestAppend(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		err1    = errors.New("err 1")
+		err2    = errors.New("err 2")
+		err3    = errors.New("err 3")
+		nilMErr *xerr.MultiError
+		newMErr = func(errs ...error) *xerr.MultiError { return xerr.NewMultiError().Add(errs...) }
+		subject = xerr.Append
+		tests   = [...]struct {
+			name         string
+			dst          error
+			errs         []error
+			expectedErrs []error
+		}{
+			{
+				name:         "nil destination, no errors",
+				dst:          nil,
+				errs:         []error{nil, nil},
+				expectedErrs: nil,
+			},
+			{
+				name:         "typed nil destination, no errors",
+				dst:          nilMErr,
+				errs:         nil,
+				expectedErrs: nil,
+			},
+			{
+				name:         "nil destination, single error",
+				dst:          nil,
+				errs:         []error{nil, err1},
+				expectedErrs: []error{err1},
+			},
+			{
+				name:         "plain destination, no errors",
+				dst:          err1,
+				errs:         []error{nil},
+				expectedErrs: []error{err1},
+			},
+			{
+				name:         "plain destination, more errors",
+				dst:          err1,
+				errs:         []error{err2, nil, err3},
+				expectedErrs: []error{err1, err2, err3},
+			},
+			{
+				name:         "nil destination, nested multi errors get flattened",
+				dst:          nil,
+				errs:         []error{newMErr(err1, newMErr(err2)), nilMErr, err3},
+				expectedErrs: []error{err1, err2, err3},
+			},
+			{
+				name:         "nil destination, multi error with single error",
+				dst:          nil,
+				errs:         []error{newMErr(err1)},
+				expectedErrs: []error{err1},
+			},
+		}
+	)
+
+	for _, testData := range tests {
+		test := testData // capture range variable
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			// act
+			result := subject(test.dst, test.errs...)
+
+			// assert
+			switch len(test.expectedErrs) {
+			case 0:
+				assertNil(t, result)
+				assertTrue(t, result == nil) // not a typed nil.
+			case 1:
+				assertEqual(t, test.expectedErrs[0], result)
+			default:
+				var mErr *xerr.MultiError
+				if assertTrue(t, errors.As(result, &mErr)) {
+					assertEqual(t, test.expectedErrs, mErr.Errors())
+				}
+			}
+		})
+	}
+}
+
+func TestAppend_multiErrorDestination(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		err1 = errors.New("err 1")
+		err2 = errors.New("err 2")
+		err3 = errors.New("err 3")
+		dst  = xerr.NewMultiError()
+	)
+
+	// act
+	result := xerr.Append(dst, err1)
+	result = xerr.Append(result, nil, xerr.NewMultiError().Add(err2, err3))
+
+	// assert
+	assertTrue(t, result == error(dst))
+	assertEqual(t, []error{err1, err2, err3}, dst.Errors())
+}
+
+func TestJoin(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no errors", testJoinNoErrors)
+	t.Run("single error", testJoinSingleError)
+	t.Run("multiple errors", testJoinMultipleErrors)
+}
+
+func testJoinNoErrors(t *testing.T) {
+	t.Parallel()
+
+	// act & assert
+	assertNil(t, xerr.Join())
+	assertNil(t, xerr.Join(nil, nil))
+}
+
+func testJoinSingleError(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	err1 := errors.New("err 1")
+
+	// act
+	result := xerr.Join(nil, err1, nil)
+
+	// assert
+	if assertNotNil(t, result) {
+		assertEqual(t, "err 1", result.Error())
+		assertTrue(t, errors.Is(result, err1))
+		frames := xerr.Frames(result)
+		if assertTrue(t, len(frames) > 0) {
+			assertEqual(t, "github.com/actforgood/xerr_test.testJoinSingleError", frames[0].Function)
+		}
+	}
+}
+
+func testJoinMultipleErrors(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		err1 = errors.New("err 1")
+		err2 = xerr.New("err 2")
+	)
+
+	// act
+	result := xerr.Join(err1, nil, err2)
+
+	// assert
+	if assertNotNil(t, result) {
+		assertEqual(t, "err 1\nerr 2", result.Error())
+		assertTrue(t, errors.Is(result, err1))
+		assertTrue(t, errors.Is(result, err2))
+		var mErr *xerr.MultiError
+		if assertTrue(t, errors.As(result, &mErr)) {
+			assertEqual(t, []error{err1, err2}, mErr.Errors())
+		}
+		frames := xerr.Frames(result)
+		if assertTrue(t, len(frames) > 0) {
+			assertEqual(t, "github.com/actforgood/xerr_test.testJoinMultipleErrors", frames[0].Function)
+		}
+	}
+}
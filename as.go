@@ -0,0 +1,64 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr
+
+import "errors"
+
+// As finds the first error in err's tree that matches type T,
+// and if one is found, returns it and true, like [errors.As] does,
+// without the need of declaring a target variable.
+//
+// Example:
+//
+//	if pathErr, ok := xerr.As[*fs.PathError](err); ok {
+//		log.Println("failed path:", pathErr.Path)
+//	}
+func As[T error](err error) (T, bool) {
+	var target T
+	if errors.As(err, &target) {
+		return target, true
+	}
+
+	return target, false
+}
+
+// AllAs returns all the errors in err's tree that match type T,
+// in a depth-first order, walking also the errors aggregated in a
+// [MultiError] / joined with [errors.Join].
+// An error matches type T if its concrete value is assignable to T,
+// or if it has a method As(any) bool such that As(target) returns true,
+// like for [errors.As] (aggregates' As method is not called, as their errors
+// are walked individually).
+// Returns nil if there is no such error.
+//
+// Example:
+//
+//	for _, violation := range xerr.AllAs[*xerr.FieldViolation](err) {
+//		log.Println("invalid field:", violation.Field)
+//	}
+func AllAs[T error](err error) []T {
+	var result []T
+	walkChain(err, func(e error) bool {
+		if target, ok := e.(T); ok {
+			result = append(result, target)
+
+			return true
+		}
+		if _, isAggregate := e.(interface{ Unwrap() []error }); isAggregate {
+			return true // its As(), if any, matches one of the aggregated errors, which get walked.
+		}
+		if x, ok := e.(interface{ As(any) bool }); ok {
+			var target T
+			if x.As(&target) {
+				result = append(result, target)
+			}
+		}
+
+		return true
+	})
+
+	return result
+}
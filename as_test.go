@@ -0,0 +1,81 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr_test
+
+import (
+	"errors"
+	"io/fs"
+	"testing"
+
+	"github.com/actforgood/xerr"
+)
+
+func TestAs(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	pathErr := &fs.PathError{Op: "open", Path: "/etc/app.yml", Err: fs.ErrNotExist}
+	err := xerr.Wrap(xerr.WithCode(pathErr, "E001"), "reading config")
+
+	// act
+	result, found := xerr.As[*fs.PathError](err)
+	resultNotFound, notFound := xerr.As[*xerr.FieldViolation](err)
+	_, nilFound := xerr.As[*fs.PathError](nil)
+
+	// assert
+	assertTrue(t, found)
+	assertEqual(t, pathErr, result)
+	assertFalse(t, notFound)
+	assertNil(t, resultNotFound)
+	assertFalse(t, nilFound)
+}
+
+func TestAllAs(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		fv1 = &xerr.FieldViolation{Field: "user.email", Description: "must be valid"}
+		fv2 = &xerr.FieldViolation{Field: "user.age", Description: "must be at least 18"}
+		fv3 = &xerr.FieldViolation{Field: "user.name", Description: "is required"}
+		fv4 = &xerr.FieldViolation{Field: "user.id", Description: "is required"}
+		err = xerr.Wrap(
+			xerr.NewMultiError().
+				Add(fv1).
+				Add(xerr.Wrap(fv2, "age")).
+				Add(errors.Join(fv3, errors.New("other"))).
+				Add(asErr{target: fv4}),
+			"invalid user",
+		)
+	)
+
+	// act
+	result := xerr.AllAs[*xerr.FieldViolation](err)
+
+	// assert
+	assertEqual(t, []*xerr.FieldViolation{fv1, fv2, fv3, fv4}, result)
+	assertNil(t, xerr.AllAs[*fs.PathError](err))
+	assertNil(t, xerr.AllAs[*xerr.FieldViolation](nil))
+}
+
+// asErr is an error which can be converted to a *[xerr.FieldViolation].
+type asErr struct {
+	target *xerr.FieldViolation
+}
+
+func (err asErr) Error() string {
+	return "as error"
+}
+
+func (err asErr) As(target any) bool {
+	if fv, ok := target.(**xerr.FieldViolation); ok {
+		*fv = err.target
+
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,141 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"sync"
+	"time"
+)
+
+const (
+	// autoLogDedupWindow is the period an error fingerprint is not logged again for,
+	// once it was logged, see [SetAutoLog].
+	autoLogDedupWindow = time.Minute
+	// autoLogMaxFingerprints is the maximum number of remembered fingerprints,
+	// above which the expired ones are forgotten, or the oldest one, if none expired.
+	autoLogMaxFingerprints = 4096
+)
+
+// autoLog is the globally configured auto logger, see [SetAutoLog].
+// If nil, created errors are not logged.
+var autoLog *autoLogger
+
+// SetAutoLog configures a logger every created error with stack trace
+// ([New], [Errorf], [Wrap], [Wrapf], and the other constructors) is logged with, at creation,
+// with its stack trace. It can be used to catch errors which are swallowed later on,
+// for example, during an incident response.
+// Only the first error with stack trace of a chain is logged, wrapping it
+// again does not log it again.
+// Errors are logged at error level, regardless of their severity, as it is usually
+// attached after creation (see [WithSeverity]), so it is not known yet when they are logged.
+// Logging is deduplicated by error fingerprint (see [Fingerprint]): an error
+// with the same fingerprint as an already logged one is not logged again for a minute,
+// and rate limited to the given limit of logged errors per second, with a burst
+// of the same size (but at least 1). The limit has the same meaning as
+// golang.org/x/time/rate.Limit, pass math.Inf(1) for no rate limit.
+// Pass a nil logger to stop logging errors (default).
+// You will call it usually somewhere in the bootstrap process of your
+// application. For example:
+//
+//	// myapp/bootstrap.go
+//	func init() {
+//		xerr.SetAutoLog(slog.Default(), 10)
+//	}
+func SetAutoLog(logger *slog.Logger, limit float64) {
+	if logger == nil {
+		autoLog = nil
+
+		return
+	}
+
+	autoLog = &autoLogger{
+		logger:     logger,
+		limit:      limit,
+		burst:      max(1, limit),
+		tokens:     max(1, limit),
+		lastRefill: time.Now(),
+		loggedAt:   make(map[string]time.Time),
+	}
+}
+
+// logCreated logs given, just created, error, if an auto logger is configured.
+func logCreated(err error) {
+	if l := autoLog; l != nil {
+		l.log(err)
+	}
+}
+
+// autoLogger logs created errors, see [SetAutoLog].
+type autoLogger struct {
+	logger *slog.Logger
+	limit  float64 // tokens refilled per second.
+	burst  float64 // maximum number of tokens.
+
+	mu         sync.Mutex
+	tokens     float64
+	lastRefill time.Time
+	loggedAt   map[string]time.Time // last time each fingerprint was logged.
+}
+
+// log logs given error, if it was not logged recently, and the rate limit allows it.
+func (l *autoLogger) log(err error) {
+	if !l.allow(Fingerprint(err), time.Now()) {
+		return
+	}
+
+	l.logger.LogAttrs(context.Background(), slog.LevelError, "xerr: error created", slog.Any("err", err))
+}
+
+// allow decides whether an error with given fingerprint can be logged at given time.
+func (l *autoLogger) allow(fingerprint string, now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if loggedAt, found := l.loggedAt[fingerprint]; found && now.Sub(loggedAt) < autoLogDedupWindow {
+		return false
+	}
+
+	if !math.IsInf(l.limit, 1) {
+		l.tokens = min(l.burst, l.tokens+now.Sub(l.lastRefill).Seconds()*l.limit)
+		l.lastRefill = now
+		if l.tokens < 1 {
+			return false
+		}
+		l.tokens--
+	}
+
+	if len(l.loggedAt) >= autoLogMaxFingerprints {
+		l.forget(now)
+	}
+	l.loggedAt[fingerprint] = now
+
+	return true
+}
+
+// forget drops the expired fingerprints, or the oldest one, if none expired,
+// in order to make room for a new one.
+func (l *autoLogger) forget(now time.Time) {
+	var (
+		oldestFp string
+		oldestAt time.Time
+	)
+	for fp, loggedAt := range l.loggedAt {
+		if now.Sub(loggedAt) >= autoLogDedupWindow {
+			delete(l.loggedAt, fp)
+
+			continue
+		}
+		if oldestFp == "" || loggedAt.Before(oldestAt) {
+			oldestFp, oldestAt = fp, loggedAt
+		}
+	}
+	if len(l.loggedAt) >= autoLogMaxFingerprints {
+		delete(l.loggedAt, oldestFp)
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/actforgood/xerr"
+)
+
+func TestSetAutoLog(t *testing.T) {
+	// test is not parallel as it changes global configuration.
+
+	t.Run("logs created errors, deduplicated", testSetAutoLogDeduplicated)
+	t.Run("wraps are not logged again", testSetAutoLogWraps)
+	t.Run("severity is not known at creation", testSetAutoLogSeverity)
+	t.Run("oldest fingerprint is forgotten, if too many", testSetAutoLogMaxFingerprints)
+	t.Run("rate limit", testSetAutoLogRateLimit)
+	t.Run("disabled", testSetAutoLogDisabled)
+}
+
+func testSetAutoLogDeduplicated(t *testing.T) {
+	// arrange
+	var buf bytes.Buffer
+	xerr.SetAutoLog(slog.New(slog.NewJSONHandler(&buf, nil)), math.Inf(1))
+	defer xerr.SetAutoLog(nil, 0)
+
+	// act
+	for i := 0; i < 3; i++ {
+		_ = xerr.New("not found")
+	}
+	_ = xerr.WrapOpt(errors.New("timeout"), "could not call API", xerr.WithOnError(func(error) {}))
+
+	// assert
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if assertEqual(t, 2, len(lines)) {
+		var entry map[string]any
+		assertNil(t, json.Unmarshal([]byte(lines[0]), &entry))
+		assertEqual(t, "ERROR", entry["level"])
+		assertEqual(t, "xerr: error created", entry["msg"])
+		errEntry, _ := entry["err"].(map[string]any)
+		if assertNotNil(t, errEntry) {
+			assertEqual(t, "not found", errEntry["msg"])
+			stack, _ := errEntry["stack"].([]any)
+			assertTrue(t, len(stack) > 0)
+		}
+		assertTrue(t, strings.Contains(lines[1], `"msg":"could not call API: timeout"`))
+	}
+}
+
+func testSetAutoLogWraps(t *testing.T) {
+	// arrange
+	var buf bytes.Buffer
+	xerr.SetAutoLog(slog.New(slog.NewJSONHandler(&buf, nil)), math.Inf(1))
+	defer xerr.SetAutoLog(nil, 0)
+
+	// act
+	err := xerr.New("not found")
+	err = xerr.Wrap(err, "could not get user")
+	_ = xerr.Wrapf(xerr.WithCode(err, "NOT_FOUND"), "could not handle request %d", 1)
+
+	// assert
+	assertEqual(t, 1, strings.Count(buf.String(), "\n"))
+	assertTrue(t, strings.Contains(buf.String(), `"msg":"not found"`))
+}
+
+func testSetAutoLogSeverity(t *testing.T) {
+	// arrange
+	var buf bytes.Buffer
+	xerr.SetAutoLog(slog.New(slog.NewJSONHandler(&buf, nil)), math.Inf(1))
+	defer xerr.SetAutoLog(nil, 0)
+
+	// act
+	_ = xerr.WithSeverity(xerr.New("cache miss"), xerr.SeverityDebug)
+
+	// assert
+	assertEqual(t, 1, strings.Count(buf.String(), "\n"))
+	assertTrue(t, strings.Contains(buf.String(), `"level":"ERROR"`))
+}
+
+func testSetAutoLogMaxFingerprints(t *testing.T) {
+	// arrange
+	const maxFingerprints = 4096
+	var buf bytes.Buffer
+	xerr.SetAutoLog(slog.New(slog.NewJSONHandler(&buf, nil)), math.Inf(1))
+	defer xerr.SetAutoLog(nil, 0)
+	xerr.SetFingerprinter(func(err error, _ []xerr.Frame) string {
+		return err.Error()
+	})
+	defer xerr.SetFingerprinter(nil) // restore original global state
+
+	// act
+	for i := 0; i <= maxFingerprints; i++ {
+		_ = xerr.Errorf("error %d", i)
+	}
+	_ = xerr.Errorf("error %d", 0)               // the oldest one, forgotten.
+	_ = xerr.Errorf("error %d", maxFingerprints) // the newest one, remembered.
+
+	// assert
+	assertEqual(t, maxFingerprints+2, strings.Count(buf.String(), "\n"))
+}
+
+func testSetAutoLogRateLimit(t *testing.T) {
+	// arrange
+	var buf bytes.Buffer
+	xerr.SetAutoLog(slog.New(slog.NewJSONHandler(&buf, nil)), 0.001)
+	defer xerr.SetAutoLog(nil, 0)
+
+	// act
+	_ = xerr.New("not found")
+	_ = xerr.New("invalid input")
+
+	// assert
+	assertEqual(t, 1, strings.Count(buf.String(), "\n"))
+	assertTrue(t, strings.Contains(buf.String(), `"msg":"not found"`))
+}
+
+func testSetAutoLogDisabled(t *testing.T) {
+	// arrange
+	var buf bytes.Buffer
+	xerr.SetAutoLog(slog.New(slog.NewJSONHandler(&buf, nil)), math.Inf(1))
+	xerr.SetAutoLog(nil, math.Inf(1))
+
+	// act
+	_ = xerr.New("not found")
+
+	// assert
+	assertEqual(t, "", buf.String())
+}
@@ -0,0 +1,123 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr
+
+import (
+	"io"
+	"os"
+	"runtime/debug"
+	"strconv"
+	"sync"
+)
+
+// BuildInfo holds information about the binary and the process
+// an error was created in, see [WithBuildInfo].
+type BuildInfo struct {
+	// Version is the main module's version, like "v1.2.3" or "(devel)".
+	Version string `json:"version,omitempty"`
+	// Revision is the VCS revision the binary was built from.
+	// It has a "-dirty" suffix if the working tree had local modifications.
+	Revision string `json:"revision,omitempty"`
+	// Hostname is the name of the host the process runs on.
+	Hostname string `json:"hostname,omitempty"`
+	// PID is the process ID.
+	PID int `json:"pid,omitempty"`
+}
+
+// buildInfoKey is the annotation key under which a decoded error's build info is stored.
+type buildInfoKey struct{}
+
+// currentBuildInfo returns the current binary / process build info.
+// It is computed only once.
+var currentBuildInfo = sync.OnceValue(func() *BuildInfo {
+	info := &BuildInfo{PID: os.Getpid()}
+	info.Hostname, _ = os.Hostname()
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		info.Version = bi.Main.Version
+		var modified bool
+		for _, setting := range bi.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				info.Revision = setting.Value
+			case "vcs.modified":
+				modified = setting.Value == "true"
+			}
+		}
+		if modified && info.Revision != "" {
+			info.Revision += "-dirty"
+		}
+	}
+
+	return info
+})
+
+// WithBuildInfo configures the error to embed the main module's version,
+// the VCS revision (see [debug.ReadBuildInfo]), the hostname and the PID
+// of the process it was created in.
+// They are written in the extended (%+v) format and in the JSON form of the error
+// (see [Serializer]), making error reports from deployed binaries traceable
+// to the exact build. They can be retrieved with [BuildInfoOf].
+//
+// Example:
+//
+//	var errs = xerr.NewFactory(xerr.WithBuildInfo())
+func WithBuildInfo() Option {
+	return func(opts *options) {
+		opts.buildInfo = true
+	}
+}
+
+// BuildInfoOf returns the build info embedded into an error,
+// the outermost one found in its chain. See [WithBuildInfo].
+// The second returned value is false if no build info was found.
+func BuildInfoOf(err error) (BuildInfo, bool) {
+	var info *BuildInfo
+	walkChain(err, func(e error) bool {
+		switch x := e.(type) {
+		case *stackError:
+			info = x.build
+		case *valueError:
+			if x.key == (buildInfoKey{}) {
+				info, _ = x.val.(*BuildInfo)
+			}
+		}
+
+		return info == nil
+	})
+	if info == nil {
+		return BuildInfo{}, false
+	}
+
+	return *info, true
+}
+
+// writeBuildInfo writes given build info on a new line, if not nil, like:
+//
+//	build: version=v1.2.3 revision=4bf92f35 hostname=api-7d9f pid=42
+func writeBuildInfo(w io.Writer, info *BuildInfo) {
+	if info == nil {
+		return
+	}
+
+	var pid string
+	if info.PID > 0 {
+		pid = strconv.Itoa(info.PID)
+	}
+	_, _ = io.WriteString(w, "\nbuild:")
+	for _, attr := range [...]struct{ key, val string }{
+		{"version", info.Version},
+		{"revision", info.Revision},
+		{"hostname", info.Hostname},
+		{"pid", pid},
+	} {
+		if attr.val != "" {
+			_, _ = io.WriteString(w, " ")
+			_, _ = io.WriteString(w, attr.key)
+			_, _ = io.WriteString(w, "=")
+			_, _ = io.WriteString(w, attr.val)
+		}
+	}
+}
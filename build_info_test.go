@@ -0,0 +1,61 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/actforgood/xerr"
+)
+
+func TestWithBuildInfo(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		factory     = xerr.NewFactory(xerr.WithBuildInfo())
+		hostname, _ = os.Hostname()
+		pid         = os.Getpid()
+	)
+
+	// act
+	err := xerr.Wrap(factory.New("not found"), "could not load user")
+	info, found := xerr.BuildInfoOf(err)
+
+	// assert
+	if assertTrue(t, found) {
+		assertEqual(t, hostname, info.Hostname)
+		assertEqual(t, pid, info.PID)
+	}
+	errMsgWithStack := fmt.Sprintf("%+v", factory.New("not found"))
+	assertTrue(t, strings.HasPrefix(errMsgWithStack, "not found\nbuild: "))
+	assertTrue(t, strings.Contains(errMsgWithStack, " pid="+strconv.Itoa(pid)+"\n"))
+	assertEqual(t, "could not load user: not found", fmt.Sprintf("%v", err))
+
+	// act & assert - JSON round trip
+	data, jsonErr := xerr.NewSerializer().Marshal(err)
+	assertNil(t, jsonErr)
+	assertTrue(t, strings.Contains(string(data), `"build":{`))
+	assertTrue(t, strings.Contains(string(data), `"pid":`+strconv.Itoa(pid)))
+	decodedErr, jsonErr := xerr.NewSerializer().Unmarshal(data)
+	assertNil(t, jsonErr)
+	decodedInfo, found := xerr.BuildInfoOf(decodedErr)
+	assertTrue(t, found)
+	assertEqual(t, info, decodedInfo)
+
+	// act & assert - no build info
+	_, found = xerr.BuildInfoOf(xerr.New("not found"))
+	assertFalse(t, found)
+	_, found = xerr.BuildInfoOf(errors.New("std error"))
+	assertFalse(t, found)
+	_, found = xerr.BuildInfoOf(nil)
+	assertFalse(t, found)
+}
@@ -0,0 +1,203 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// DefaultMaxChainDepth is the default maximum number of wrap layers
+// followed when traversing an error's chain, see [SetMaxChainDepth].
+const DefaultMaxChainDepth = 1024
+
+// maxChainDepth is the maximum number of wrap layers followed when traversing
+// an error's chain, see [SetMaxChainDepth].
+var maxChainDepth = DefaultMaxChainDepth
+
+// SetMaxChainDepth configures the maximum number of wrap layers this package
+// follows when traversing an error's chain (looking up its stack trace, fields, code,
+// formatting it, etc.), on each unwrap path. Layers beyond it are ignored.
+// It guards against pathological chains, like runaway wrapping loops, or
+// third party errors which (indirectly) wrap themselves, which would otherwise
+// be traversed endlessly.
+// A value lower than 1 restores the default, [DefaultMaxChainDepth].
+// You will call it usually somewhere in the bootstrap process of your
+// application. For example:
+//
+//	// myapp/bootstrap.go
+//	func init() {
+//		xerr.SetMaxChainDepth(64)
+//	}
+func SetMaxChainDepth(depth int) {
+	if depth < 1 {
+		depth = DefaultMaxChainDepth
+	}
+	maxChainDepth = depth
+}
+
+// Depth returns the number of wrap layers of an error, the length
+// of its longest unwrap path. A [MultiError] counts as a layer above
+// its stored errors.
+// Returns 0 for a nil or a non wrapping error.
+// It can be used to detect pathological chains (runaway wrapping loops).
+// The result is capped at the configured maximum chain depth, see [SetMaxChainDepth].
+func Depth(err error) int {
+	var pathBuf [chainPathBufSize]any
+
+	return depthLimit(err, maxChainDepth, pathBuf[:0])
+}
+
+// depthLimit returns the depth of an error, see [Depth], capped at limit.
+func depthLimit(err error, limit int, path chainPath) int {
+	if err == nil || limit <= 0 {
+		return 0
+	}
+
+	errs := unwrapAll(err)
+	if _, isMulti := err.(interface{ Unwrap() []error }); isMulti {
+		var ok bool
+		if path, ok = path.enter(err, errs); !ok { // it wraps itself, its depth is unbounded.
+			return limit
+		}
+	}
+
+	depth := 0
+	for _, e := range errs {
+		if d := depthLimit(e, limit-1, path) + 1; d > depth {
+			depth = d
+		}
+	}
+
+	return depth
+}
+
+// SizeHint returns the approximate size, in bytes, of an error's
+// serialized form: its message, stack trace and fields.
+// For a [MultiError], the sizes of the stored errors are added up.
+// It can be used to truncate or alert before logging huge errors.
+func SizeHint(err error) int {
+	if err == nil {
+		return 0
+	}
+
+	if mErr, ok := err.(*MultiError); ok { // its message is made of the stored errors' messages.
+		size := 0
+		for _, e := range mErr.Errors() {
+			size += SizeHint(e)
+		}
+
+		return size
+	}
+
+	size := len(err.Error())
+
+	const frameOverhead = 8 // separators and line number, approximately.
+	for _, f := range Frames(err) {
+		size += len(f.Function) + len(f.File) + frameOverhead
+	}
+	for key, val := range Fields(err) {
+		size += len(key) + len(fmt.Sprint(val))
+	}
+
+	return size
+}
+
+// RootCause returns the innermost error of an error's chain, that is
+// the error found by unwrapping it until an error which wraps nothing is reached.
+// For an error wrapping multiple errors (like a [MultiError], or one returned
+// by [errors.Join]), its first wrapped error is followed.
+// Returns the error itself if it does not wrap anything, and nil for a nil error.
+// At most the configured maximum chain depth of errors is followed, see [SetMaxChainDepth].
+//
+// Example:
+//
+//	if errors.Is(xerr.RootCause(err), io.EOF) { ... }
+func RootCause(err error) error {
+	for layer := 0; err != nil && layer < maxChainDepth; layer++ {
+		errs := unwrapAll(err)
+		if len(errs) == 0 || errs[0] == nil {
+			break
+		}
+		err = errs[0]
+	}
+
+	return err
+}
+
+// ChainMessages returns the messages of an error and of the errors it wraps,
+// in the order they are visited: depth first, outermost first.
+// Both single and multi unwrap errors (like [MultiError]) are followed.
+// Returns nil for a nil error.
+// At most the configured maximum chain depth of errors is followed on each
+// unwrap path, see [SetMaxChainDepth].
+//
+// Example:
+//
+//	err := xerr.Wrap(fmt.Errorf("open config: %w", fs.ErrNotExist), "bootstrap failed")
+//	xerr.ChainMessages(err)
+//	// [bootstrap failed: open config: file does not exist, open config: file does not exist, file does not exist]
+func ChainMessages(err error) []string {
+	var msgs []string
+	walkChain(err, func(e error) bool {
+		msgs = append(msgs, e.Error())
+
+		return true
+	})
+
+	return msgs
+}
+
+// chainPathBufSize is the number of nested multi unwrap errors
+// a [chainPath] holds without allocating.
+const chainPathBufSize = 4
+
+// chainPath holds the multi unwrap errors (like [MultiError]) on the unwrap path
+// being traversed, in order to detect errors which (indirectly) wrap themselves,
+// which would otherwise be traversed an exponential number of times.
+type chainPath []any
+
+// enter returns the path extended with given multi unwrap error, wrapping errs.
+// The second returned value is false if the error is already on the path,
+// that is, it wraps itself.
+func (path chainPath) enter(err error, errs []error) (chainPath, bool) {
+	key := chainPathKey(err, errs)
+	for _, k := range path {
+		if k == key {
+			return path, false
+		}
+	}
+
+	return append(path, key), true
+}
+
+// chainPathKey returns the identity of a multi unwrap error: the error itself,
+// if comparable, otherwise the storage of the errors it wraps, as an error
+// held by value can wrap itself only through it.
+func chainPathKey(err error, errs []error) any {
+	if reflect.TypeOf(err).Comparable() {
+		return err
+	}
+	if len(errs) > 0 {
+		return &errs[0]
+	}
+
+	return nil
+}
+
+// unwrapAll returns the errors directly wrapped by err.
+func unwrapAll(err error) []error {
+	switch x := err.(type) {
+	case interface{ Unwrap() []error }:
+		return x.Unwrap()
+	case interface{ Unwrap() error }:
+		if e := x.Unwrap(); e != nil {
+			return []error{e}
+		}
+	}
+
+	return nil
+}
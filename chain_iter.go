@@ -0,0 +1,30 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+//go:build go1.23
+
+package xerr
+
+import "iter"
+
+// Chain returns an iterator over an error and the errors it wraps, depth first,
+// outermost first, see also [ChainMessages].
+// Both single and multi unwrap errors (like [MultiError]) are followed.
+// A nil error yields nothing.
+// At most the configured maximum chain depth of errors is followed on each
+// unwrap path, see [SetMaxChainDepth].
+//
+// Example:
+//
+//	for e := range xerr.Chain(err) {
+//		if pathErr, ok := e.(*fs.PathError); ok {
+//			log.Println("failing path:", pathErr.Path)
+//		}
+//	}
+func Chain(err error) iter.Seq[error] {
+	return func(yield func(error) bool) {
+		walkChain(err, yield)
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+//go:build go1.23
+
+package xerr_test
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/actforgood/xerr"
+)
+
+func TestChain(t *testing.T) {
+	t.Parallel()
+
+	t.Run("all errors are iterated", testChainIteratesAll)
+	t.Run("break stops iteration", testChainBreak)
+	t.Run("nil error", testChainNilErr)
+}
+
+func testChainIteratesAll(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		stdErr   = errors.New("some standard error")
+		wrapErr  = xerr.Wrap(io.EOF, "wrap")
+		multiErr = xerr.NewMultiError().Add(stdErr, wrapErr)
+		subject  = xerr.Chain(multiErr)
+		errs     []error
+	)
+
+	// act
+	for err := range subject {
+		errs = append(errs, err)
+	}
+
+	// assert
+	assertEqual(t, []error{multiErr, stdErr, wrapErr, io.EOF}, errs)
+}
+
+func testChainBreak(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		stdErr   = errors.New("some standard error")
+		wrapErr  = xerr.Wrap(io.EOF, "wrap")
+		multiErr = xerr.NewMultiError().Add(stdErr, wrapErr)
+		subject  = xerr.Chain(multiErr)
+		errs     []error
+	)
+
+	// act
+	for err := range subject {
+		errs = append(errs, err)
+		if err == stdErr {
+			break
+		}
+	}
+
+	// assert
+	assertEqual(t, []error{multiErr, stdErr}, errs)
+}
+
+func testChainNilErr(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	subject := xerr.Chain(nil)
+	iterations := 0
+
+	// act
+	for range subject {
+		iterations++
+	}
+
+	// assert
+	assertEqual(t, 0, iterations)
+}
@@ -0,0 +1,300 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/actforgood/xerr"
+)
+
+func TestDepth(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		subject = xerr.Depth
+		stdErr  = errors.New("some standard error")
+		tests   = [...]struct {
+			name     string
+			inputErr error
+			expected int
+		}{
+			{
+				name:     "nil error",
+				inputErr: nil,
+				expected: 0,
+			},
+			{
+				name:     "non wrapping error",
+				inputErr: stdErr,
+				expected: 0,
+			},
+			{
+				name:     "stack error",
+				inputErr: xerr.New("some error with stack"),
+				expected: 0,
+			},
+			{
+				name:     "2 wraps",
+				inputErr: xerr.Wrap(fmt.Errorf("wrap: %w", stdErr), "wrap"),
+				expected: 2,
+			},
+			{
+				name: "MultiError, longest path",
+				inputErr: xerr.NewMultiError().Add(
+					stdErr,
+					xerr.Wrap(xerr.Wrap(stdErr, "1st wrap"), "2nd wrap"),
+				),
+				expected: 3,
+			},
+			{
+				name:     "joined errors",
+				inputErr: errors.Join(stdErr, xerr.Wrap(stdErr, "wrap")),
+				expected: 2,
+			},
+		}
+	)
+
+	for _, testData := range tests {
+		test := testData // capture range variable
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			// act
+			result := subject(test.inputErr)
+
+			// assert
+			assertEqual(t, test.expected, result)
+		})
+	}
+}
+
+// cyclicErr is an error which wraps itself.
+type cyclicErr struct{}
+
+func (cErr *cyclicErr) Error() string { return "cyclic error" }
+
+func (cErr *cyclicErr) Unwrap() error { return cErr }
+
+// cyclicMultiErr is a multi unwrap error which wraps itself, twice.
+type cyclicMultiErr struct{}
+
+func (cErr *cyclicMultiErr) Error() string { return "cyclic multi error" }
+
+func (cErr *cyclicMultiErr) Unwrap() []error { return []error{cErr, io.EOF, cErr} }
+
+// cyclicValueMultiErr is a multi unwrap error, held by value (not comparable),
+// which wraps itself through its errors' storage.
+type cyclicValueMultiErr struct {
+	errs []error
+}
+
+func (cErr cyclicValueMultiErr) Error() string { return "cyclic value multi error" }
+
+func (cErr cyclicValueMultiErr) Unwrap() []error { return cErr.errs }
+
+func TestSetMaxChainDepth(t *testing.T) {
+	// test is not parallel as it changes global configuration.
+	defer xerr.SetMaxChainDepth(0)
+
+	t.Run("cyclic error is traversed up to max depth", testSetMaxChainDepthCyclicErr)
+	t.Run("layers beyond max depth are ignored", testSetMaxChainDepthIgnoredLayers)
+	t.Run("cyclic multi unwrap errors are not followed again", testSetMaxChainDepthCyclicMultiErr)
+}
+
+func testSetMaxChainDepthCyclicErr(t *testing.T) {
+	// arrange
+	var (
+		cyclicErr = new(cyclicErr)
+		subject   = xerr.WithCode(xerr.Wrap(cyclicErr, "wrapped"), "CYCLE")
+	)
+
+	// act & assert
+	assertEqual(t, xerr.DefaultMaxChainDepth, xerr.Depth(cyclicErr))
+	assertEqual(t, xerr.Code("CYCLE"), xerr.CodeOf(subject))
+	assertEqual(t, "", xerr.ID(subject))
+	assertEqual(t, 0, len(xerr.Fields(subject)))
+	assertEqual(t, 0, len(xerr.Ops(subject)))
+	assertTrue(t, len(xerr.Frames(subject)) > 0)
+	assertTrue(t, strings.HasPrefix(fmt.Sprintf("%+v", subject), "wrapped: cyclic error\n"))
+	_, err := xerr.NewSerializer().Marshal(subject)
+	assertNil(t, err)
+
+	xerr.SetMaxChainDepth(8)
+	assertEqual(t, 8, xerr.Depth(cyclicErr))
+}
+
+func testSetMaxChainDepthIgnoredLayers(t *testing.T) {
+	// arrange
+	subject := xerr.Wrap(xerr.Wrap(xerr.WithCode(io.EOF, "EOF"), "1st wrap"), "2nd wrap")
+	xerr.SetMaxChainDepth(2)
+
+	// act & assert
+	assertEqual(t, 2, xerr.Depth(subject))
+	assertEqual(t, xerr.Code(""), xerr.CodeOf(subject))
+
+	xerr.SetMaxChainDepth(-1)
+	assertEqual(t, 3, xerr.Depth(subject))
+	assertEqual(t, xerr.Code("EOF"), xerr.CodeOf(subject))
+}
+
+func testSetMaxChainDepthCyclicMultiErr(t *testing.T) {
+	// arrange
+	var (
+		cyclicErr      = new(cyclicMultiErr)
+		cyclicValueErr = cyclicValueMultiErr{errs: make([]error, 2)}
+	)
+	cyclicValueErr.errs[0] = io.ErrUnexpectedEOF
+	cyclicValueErr.errs[1] = cyclicValueErr
+	subject := xerr.NewMultiError().Add(
+		xerr.WithCode(xerr.Wrap(cyclicErr, "wrapped"), "CYCLE"),
+		cyclicValueErr,
+	)
+
+	// act & assert
+	assertEqual(t, xerr.DefaultMaxChainDepth, xerr.Depth(cyclicErr))
+	assertEqual(t, xerr.DefaultMaxChainDepth, xerr.Depth(cyclicValueErr))
+	assertEqual(t, xerr.Code("CYCLE"), xerr.CodeOf(subject))
+	assertEqual(t, 0, len(xerr.Fields(subject)))
+	assertEqual(
+		t,
+		[]string{"cyclic multi error", "cyclic multi error", "EOF", "cyclic multi error"},
+		xerr.ChainMessages(cyclicErr),
+	)
+	assertEqual(
+		t,
+		[]string{"cyclic value multi error", "unexpected EOF", "cyclic value multi error"},
+		xerr.ChainMessages(cyclicValueErr),
+	)
+}
+
+func TestSizeHint(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		subject   = xerr.SizeHint
+		stdErr    = errors.New("some standard error")
+		stackErr  = xerr.New("some error with stack")
+		ctx       = context.WithValue(context.Background(), ctxTestKey("tenant"), "acme")
+		fieldsErr = xerr.WithSeverity(xerr.WrapCtx(ctx, stdErr, "wrap"), xerr.SeverityWarn)
+		multiErr  = xerr.NewMultiError().Add(stdErr, stackErr)
+	)
+
+	// act & assert
+	assertEqual(t, 0, subject(nil))
+	assertEqual(t, len(stdErr.Error()), subject(stdErr))
+	stackErrSize := subject(stackErr)
+	assertTrue(t, stackErrSize > len(stackErr.Error())+len("xerr_test.TestSizeHint"))
+	assertTrue(t, subject(fieldsErr) > len(fieldsErr.Error())+len("tenant")+len("acme"))
+	assertEqual(
+		t,
+		subject(stdErr)+stackErrSize,
+		subject(multiErr),
+	)
+	assertTrue(t, subject(xerr.Wrap(stdErr, strings.Repeat("x", 1000))) > 1000)
+}
+
+func TestRootCause(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		subject = xerr.RootCause
+		stdErr  = errors.New("some standard error")
+		tests   = [...]struct {
+			name     string
+			inputErr error
+			expected error
+		}{
+			{
+				name:     "nil error",
+				inputErr: nil,
+				expected: nil,
+			},
+			{
+				name:     "non wrapping error",
+				inputErr: stdErr,
+				expected: stdErr,
+			},
+			{
+				name:     "wrapped error",
+				inputErr: xerr.Wrap(fmt.Errorf("wrap: %w", stdErr), "wrap"),
+				expected: stdErr,
+			},
+			{
+				name: "MultiError, first error is followed",
+				inputErr: xerr.Wrap(xerr.NewMultiError().Add(
+					xerr.WithCode(stdErr, "CODE"),
+					io.EOF,
+				), "wrap"),
+				expected: stdErr,
+			},
+			{
+				name:     "joined errors, first error is followed",
+				inputErr: errors.Join(io.EOF, stdErr),
+				expected: io.EOF,
+			},
+			{
+				name:     "empty MultiError",
+				inputErr: xerr.NewMultiError(),
+				expected: xerr.NewMultiError(),
+			},
+		}
+	)
+
+	for _, testData := range tests {
+		test := testData // capture range variable
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			// act
+			result := subject(test.inputErr)
+
+			// assert
+			assertEqual(t, test.expected, result)
+		})
+	}
+}
+
+func TestChainMessages(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		subject = xerr.ChainMessages
+		stdErr  = errors.New("some standard error")
+	)
+
+	// act & assert
+	assertNil(t, subject(nil))
+	assertEqual(t, []string{"some standard error"}, subject(stdErr))
+	assertEqual(
+		t,
+		[]string{
+			"wrap: fmt wrap: some standard error",
+			"fmt wrap: some standard error",
+			"some standard error",
+		},
+		subject(xerr.Wrap(fmt.Errorf("fmt wrap: %w", stdErr), "wrap")),
+	)
+	assertEqual(
+		t,
+		[]string{
+			"some standard error\nwrap: EOF",
+			"some standard error",
+			"wrap: EOF",
+			"EOF",
+		},
+		subject(xerr.NewMultiError().Add(stdErr, xerr.Wrap(io.EOF, "wrap"))),
+	)
+}
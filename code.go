@@ -0,0 +1,47 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr
+
+import "time"
+
+// Code is a stable, machine-readable error identifier,
+// like "ORD-0042" or "user_not_found".
+type Code string
+
+// codeKey is the annotation key under which an error's code is stored.
+type codeKey struct{}
+
+// WithCode returns an error annotating err with given code.
+// If err is nil, WithCode returns nil.
+func WithCode(err error, code Code) error {
+	if err == nil {
+		return nil
+	}
+
+	return withValue(err, codeKey{}, code)
+}
+
+// CodeOf returns the code of an error, the outermost one found in its chain.
+// Returns empty code if the error does not have one.
+func CodeOf(err error) Code {
+	if code, found := lookupValue(err, codeKey{}); found {
+		return code.(Code)
+	}
+
+	return ""
+}
+
+// NewCode returns an error with the supplied code and message.
+// NewCode also records the stack trace at the point it was called.
+func NewCode(code Code, msg string) error {
+	err := &stackError{
+		msg:       msg,
+		stackPCs:  getCallStack(maxStackFrames),
+		createdAt: time.Now(),
+	}
+
+	return created(withValue(err, codeKey{}, code))
+}
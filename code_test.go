@@ -0,0 +1,95 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr_test
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/actforgood/xerr"
+)
+
+func TestCodeOf(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		subject = xerr.CodeOf
+		stdErr  = errors.New("some standard error")
+		tests   = [...]struct {
+			name     string
+			inputErr error
+			expected xerr.Code
+		}{
+			{
+				name:     "nil error",
+				inputErr: nil,
+				expected: "",
+			},
+			{
+				name:     "error without code",
+				inputErr: stdErr,
+				expected: "",
+			},
+			{
+				name:     "error with code",
+				inputErr: xerr.WithCode(stdErr, "E001"),
+				expected: "E001",
+			},
+			{
+				name:     "wrapped error with code",
+				inputErr: fmt.Errorf("wrap: %w", xerr.Wrap(xerr.WithCode(stdErr, "E002"), "wrap")),
+				expected: "E002",
+			},
+			{
+				name:     "error with multiple codes, expect outermost",
+				inputErr: xerr.WithCode(xerr.Wrap(xerr.NewCode("E003", "inner"), "wrap"), "E004"),
+				expected: "E004",
+			},
+		}
+	)
+
+	for _, testData := range tests {
+		test := testData // capture range variable
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			// act
+			result := subject(test.inputErr)
+
+			// assert
+			assertEqual(t, test.expected, result)
+		})
+	}
+
+	assertNil(t, xerr.WithCode(nil, "E001"))
+}
+
+func TestNewCode(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		subject  = xerr.NewCode
+		stackReg = `^user not found\ngithub\.com/actforgood/xerr_test\.TestNewCode\n\t.+code_test\.go:\d+\n`
+	)
+
+	// act
+	resultErr := subject("user_not_found", "user not found")
+
+	// assert
+	if assertNotNil(t, resultErr) {
+		assertEqual(t, "user not found", resultErr.Error())
+		assertEqual(t, xerr.Code("user_not_found"), xerr.CodeOf(resultErr))
+		errMsgWithStack := fmt.Sprintf("%+v", resultErr)
+		matched, _ := regexp.MatchString(stackReg, errMsgWithStack)
+		if !assertTrue(t, matched) {
+			t.Log("regex", stackReg, "errMsgWithStack", errMsgWithStack)
+		}
+	}
+}
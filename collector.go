@@ -0,0 +1,91 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr
+
+import "context"
+
+// Collect receives errors from errCh until it is closed or ctx is done,
+// and returns them collected into a [MultiError], in the order they were received.
+// nil errors are skipped. Returns nil if no error was received.
+// It allows fan-out workers to stream their errors to a single aggregator,
+// without sharing a MultiError among them.
+//
+// Example:
+//
+//	errCh := make(chan error)
+//	go func() {
+//		defer close(errCh)
+//		// start workers sending their errors on errCh, wait for them...
+//	}()
+//	mErr := xerr.Collect(ctx, errCh)
+func Collect(ctx context.Context, errCh <-chan error) *MultiError {
+	var mErr *MultiError
+	for {
+		select {
+		case <-ctx.Done():
+			return mErr
+		case err, ok := <-errCh:
+			if !ok {
+				return mErr
+			}
+			mErr = mErr.Add(err)
+		}
+	}
+}
+
+// Collector aggregates the errors sent on its channel (see [Collector.C])
+// into a [MultiError], so that fan-out workers do not need to share
+// a mutex guarded MultiError.
+//
+// Example:
+//
+//	collector := xerr.NewCollector(workersNo)
+//	var wg sync.WaitGroup
+//	for _, item := range items {
+//		wg.Add(1)
+//		go func(item Item) {
+//			defer wg.Done()
+//			collector.C() <- process(item)
+//		}(item)
+//	}
+//	wg.Wait()
+//	mErr := collector.Wait()
+type Collector struct {
+	errCh chan error
+	done  chan struct{}
+	mErr  *MultiError
+}
+
+// NewCollector instantiates a new Collector, and starts aggregating errors.
+// bufferSize is the capacity of the Collector's channel.
+func NewCollector(bufferSize int) *Collector {
+	c := &Collector{
+		errCh: make(chan error, max(bufferSize, 0)),
+		done:  make(chan struct{}),
+	}
+	go func() {
+		defer close(c.done)
+		c.mErr = Collect(context.Background(), c.errCh)
+	}()
+
+	return c
+}
+
+// C returns the channel errors should be sent on.
+// nil errors are skipped.
+func (c *Collector) C() chan<- error {
+	return c.errCh
+}
+
+// Wait stops the aggregation, and returns the collected errors,
+// or nil if there was no error.
+// It must be called once, after all senders are done, as the Collector's channel gets closed.
+func (c *Collector) Wait() *MultiError {
+	close(c.errCh)
+	<-c.done
+
+	return c.mErr
+}
@@ -0,0 +1,121 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/actforgood/xerr"
+)
+
+func TestCollect(t *testing.T) {
+	t.Parallel()
+
+	t.Run("channel is closed", testCollectChannelClosed)
+	t.Run("context is done", testCollectContextDone)
+	t.Run("no errors", testCollectNoErrors)
+}
+
+func testCollectChannelClosed(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	errCh := make(chan error)
+	go func() {
+		defer close(errCh)
+		errCh <- io.EOF
+		errCh <- nil
+		errCh <- io.ErrUnexpectedEOF
+	}()
+
+	// act
+	result := xerr.Collect(context.Background(), errCh)
+
+	// assert
+	assertEqual(t, []error{io.EOF, io.ErrUnexpectedEOF}, result.Errors())
+}
+
+func testCollectContextDone(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		errCh       = make(chan error) // note: it never gets closed.
+		ctx, cancel = context.WithCancel(context.Background())
+	)
+	go func() {
+		errCh <- io.EOF // returns after the error was received.
+		cancel()
+	}()
+
+	// act
+	result := xerr.Collect(ctx, errCh)
+
+	// assert
+	assertEqual(t, []error{io.EOF}, result.Errors())
+}
+
+func testCollectNoErrors(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	errCh := make(chan error)
+	close(errCh)
+
+	// act
+	result := xerr.Collect(context.Background(), errCh)
+
+	// assert
+	assertNil(t, result)
+	assertNil(t, result.ErrOrNil())
+}
+
+func TestCollector(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		subject      = xerr.NewCollector(4)
+		goroutinesNo = 100
+		wg           sync.WaitGroup
+	)
+
+	// act
+	for i := 0; i < goroutinesNo; i++ {
+		wg.Add(1)
+		go func(workerNo int) {
+			defer wg.Done()
+			if workerNo%2 == 0 {
+				subject.C() <- errors.New("err from worker " + strconv.Itoa(workerNo))
+			} else {
+				subject.C() <- nil
+			}
+		}(i)
+	}
+	wg.Wait()
+	result := subject.Wait()
+
+	// assert
+	assertEqual(t, goroutinesNo/2, len(result.Errors()))
+}
+
+func TestCollector_noErrors(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	subject := xerr.NewCollector(-1)
+
+	// act
+	result := subject.Wait()
+
+	// assert
+	assertNil(t, result)
+}
@@ -0,0 +1,26 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr
+
+// Const is an error which can be declared as a constant,
+// so that sentinel errors can't be (accidentally) reassigned:
+//
+//	const ErrNotFound = xerr.Const("not found")
+//
+// It can be wrapped (see [Wrap]) and checked with [errors.Is] like any other error:
+//
+//	err := xerr.Wrap(ErrNotFound, "get user")
+//	errors.Is(err, ErrNotFound) // true
+//
+// Note: Const errors with the same message are equal, so prefer
+// package qualified messages, like "users: not found".
+type Const string
+
+// Error returns the error's message.
+// Implements std error interface.
+func (c Const) Error() string {
+	return string(c)
+}
@@ -0,0 +1,39 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/actforgood/xerr"
+)
+
+const (
+	errConstNotFound = xerr.Const("not found")
+	errConstConflict = xerr.Const("conflict")
+)
+
+func TestConst(t *testing.T) {
+	t.Parallel()
+
+	// act
+	wrapErr := xerr.Wrap(errConstNotFound, "get user")
+	stdWrapErr := fmt.Errorf("get user: %w", errConstNotFound)
+
+	// assert
+	assertEqual(t, "not found", errConstNotFound.Error())
+	assertEqual(t, "get user: not found", wrapErr.Error())
+	assertTrue(t, errors.Is(wrapErr, errConstNotFound))
+	assertTrue(t, errors.Is(stdWrapErr, errConstNotFound))
+	assertFalse(t, errors.Is(wrapErr, errConstConflict))
+	assertTrue(t, len(xerr.Frames(wrapErr)) > 0)
+	var target xerr.Const
+	if assertTrue(t, errors.As(wrapErr, &target)) {
+		assertEqual(t, errConstNotFound, target)
+	}
+}
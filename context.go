@@ -0,0 +1,214 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var contextExtractors []ContextExtractor
+
+// ContextExtractor is an alias for a function that extracts request-scoped
+// values (request ID, tenant, user, etc.) from a context.
+// Returned values are attached as fields to errors created with [NewCtx] / [WrapCtx].
+// See also [SetContextExtractor].
+type ContextExtractor func(ctx context.Context) map[string]any
+
+// RegisterContextExtractor adds a function this package uses in order
+// to extract fields from a context, when an error is created with
+// [NewCtx] / [WrapCtx]. Multiple extractors can be registered, their
+// outcomes get merged (a later registered extractor overwrites a key
+// returned also by a previous one).
+// You will call it usually somewhere in the bootstrap process of your
+// application. For example:
+//
+//	// myapp/bootstrap.go
+//	func init() {
+//		xerr.RegisterContextExtractor(func(ctx context.Context) map[string]any {
+//			if reqID, ok := ctx.Value(requestIDKey{}).(string); ok {
+//				return map[string]any{"request_id": reqID}
+//			}
+//
+//			return nil
+//		})
+//	}
+func RegisterContextExtractor(fn ContextExtractor) {
+	if fn != nil {
+		contextExtractors = append(contextExtractors, fn)
+	}
+}
+
+// SetContextExtractor adds a function this package uses in order to extract fields
+// from a context, when an error is created with [NewCtx] / [WrapCtx], like
+// [RegisterContextExtractor] does, for an extractor returning a list of fields.
+// The extractors registered so far are kept.
+// A nil function is ignored.
+// You will call it usually somewhere in the bootstrap process of your
+// application. For example:
+//
+//	// myapp/bootstrap.go
+//	func init() {
+//		xerr.SetContextExtractor(func(ctx context.Context) []xerr.Field {
+//			return []xerr.Field{
+//				xerr.F("trace_id", trace.SpanContextFromContext(ctx).TraceID().String()),
+//				xerr.F("tenant", tenantFromContext(ctx)),
+//			}
+//		})
+//	}
+func SetContextExtractor(fn func(ctx context.Context) []Field) {
+	if fn != nil {
+		RegisterContextExtractor(fieldsContextExtractor(fn))
+	}
+}
+
+// fieldsContextExtractor adapts a context extractor returning a list of fields
+// to a [ContextExtractor].
+func fieldsContextExtractor(fn func(ctx context.Context) []Field) ContextExtractor {
+	return func(ctx context.Context) map[string]any {
+		fields := fn(ctx)
+		if len(fields) == 0 {
+			return nil
+		}
+		fieldsMap := make(map[string]any, len(fields))
+		for _, field := range fields {
+			fieldsMap[field.Key] = field.Value
+		}
+
+		return fieldsMap
+	}
+}
+
+// NewCtx returns an error with the supplied message, like [New] does,
+// with fields extracted from given context attached.
+// See [RegisterContextExtractor], [Fields].
+func NewCtx(ctx context.Context, msg string) error {
+	err := &stackError{
+		msg:       msg,
+		stackPCs:  getCallStack(maxStackFrames),
+		createdAt: time.Now(),
+	}
+
+	return created(withFields(err, contextFields(ctx)))
+}
+
+// WrapCtx returns an error annotating err with a stack trace
+// and the supplied message, like [Wrap] does, with fields extracted
+// from given context attached.
+// If err is nil, WrapCtx returns nil.
+// See [RegisterContextExtractor], [Fields].
+func WrapCtx(ctx context.Context, err error, msg string) error {
+	if err == nil {
+		return nil
+	}
+
+	wErr := &stackError{
+		origErr:   err,
+		msg:       msg,
+		createdAt: time.Now(),
+	}
+	wErr.stackPCs, wErr.stackBuf = wrapCallStack(err, 0, maxStackFrames)
+
+	return created(withFields(wErr, contextFields(ctx)))
+}
+
+// contextFields returns the merged fields from all registered extractors.
+func contextFields(ctx context.Context) map[string]any {
+	if ctx == nil || len(contextExtractors) == 0 {
+		return nil
+	}
+
+	var fields map[string]any
+	for _, extract := range contextExtractors {
+		for key, val := range extract(ctx) {
+			if fields == nil {
+				fields = make(map[string]any)
+			}
+			fields[key] = val
+		}
+	}
+
+	return fields
+}
+
+// ctxErrKey is the annotation key under which the error of a done context
+// is stored, see [WrapContext].
+type ctxErrKey struct{}
+
+// WrapContext returns an error annotating err with a stack trace
+// and the supplied message, like [Wrap] does, with given context's state
+// at wrap point attached as fields (see [Fields]):
+//
+//	ctx_done       whether ctx was done.
+//	ctx_cause      the cause ctx was done with (see [context.Cause]), if ctx was done.
+//	ctx_deadline   ctx's deadline, if it has one.
+//	ctx_remaining  the time remaining until ctx's deadline, if it has one (negative if exceeded).
+//
+// If ctx was done, [IsCanceled] / [IsDeadline] report accordingly on the returned error,
+// even if err does not wrap ctx's error.
+// If err is nil, WrapContext returns nil.
+// A nil ctx is treated as [context.Background], like [NewCtx] / [WrapCtx] do.
+//
+// Example:
+//
+//	if err := repo.Save(ctx, user); err != nil {
+//		return xerr.WrapContext(ctx, err, "could not save user")
+//	}
+func WrapContext(ctx context.Context, err error, msg string) error {
+	if err == nil {
+		return nil
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	wErr := &stackError{
+		origErr:   err,
+		msg:       msg,
+		createdAt: time.Now(),
+	}
+	wErr.stackPCs, wErr.stackBuf = wrapCallStack(err, 0, maxStackFrames)
+
+	var result error = wErr
+	ctxErr := ctx.Err()
+	fields := []Field{F("ctx_done", ctxErr != nil)}
+	if ctxErr != nil {
+		result = withValue(result, ctxErrKey{}, ctxErr)
+		fields = append(fields, F("ctx_cause", context.Cause(ctx).Error()))
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		fields = append(fields, F("ctx_deadline", deadline), F("ctx_remaining", deadline.Sub(wErr.createdAt)))
+	}
+
+	return created(WithFields(result, fields...))
+}
+
+// IsCanceled returns true if err is (or wraps) [context.Canceled],
+// or if it was wrapped with [WrapContext] after its context was canceled.
+// It tells that the caller gave up, as opposed to a failure.
+func IsCanceled(err error) bool {
+	return isContextErr(err, context.Canceled)
+}
+
+// IsDeadline returns true if err is (or wraps) [context.DeadlineExceeded],
+// or if it was wrapped with [WrapContext] after its context's deadline was exceeded.
+func IsDeadline(err error) bool {
+	return isContextErr(err, context.DeadlineExceeded)
+}
+
+// isContextErr returns true if err is (or wraps) given context error,
+// or if it was annotated with it by [WrapContext].
+func isContextErr(err, ctxErr error) bool {
+	if errors.Is(err, ctxErr) {
+		return true
+	}
+	if annotatedErr, found := lookupValue(err, ctxErrKey{}); found {
+		return annotatedErr.(error) == ctxErr
+	}
+
+	return false
+}
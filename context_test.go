@@ -0,0 +1,279 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/actforgood/xerr"
+)
+
+type ctxTestKey string
+
+func init() {
+	// Note: extractors are registered globally, each of them
+	// returns something only if its own key is found in the context.
+	xerr.RegisterContextExtractor(func(ctx context.Context) map[string]any {
+		if reqID, ok := ctx.Value(ctxTestKey("request_id")).(string); ok {
+			return map[string]any{"request_id": reqID}
+		}
+
+		return nil
+	})
+	xerr.RegisterContextExtractor(func(ctx context.Context) map[string]any {
+		if tenant, ok := ctx.Value(ctxTestKey("tenant")).(string); ok {
+			return map[string]any{"tenant": tenant}
+		}
+
+		return nil
+	})
+	xerr.RegisterContextExtractor(func(ctx context.Context) map[string]any {
+		if raw := ctx.Value(ctxTestKey("raw")); raw != nil {
+			return map[string]any{"raw": raw}
+		}
+
+		return nil
+	})
+	xerr.RegisterContextExtractor(nil) // should be ignored
+}
+
+func TestNewCtx(t *testing.T) {
+	t.Run("with context values", testNewCtxWithContextValues)
+	t.Run("without context values", testNewCtxWithoutContextValues)
+}
+
+func testNewCtxWithContextValues(t *testing.T) {
+	// arrange
+	var (
+		subject = xerr.NewCtx
+		ctx     = context.WithValue(
+			context.WithValue(context.Background(), ctxTestKey("request_id"), "req-123"),
+			ctxTestKey("tenant"), "acme",
+		)
+		stackReg = `something went bad\ngithub\.com/actforgood/xerr_test\.testNewCtxWithContextValues\n\t.+context_test\.go:\d+`
+	)
+
+	// act
+	resultErr := subject(ctx, "something went bad")
+
+	// assert
+	if assertNotNil(t, resultErr) {
+		assertEqual(t, "something went bad", resultErr.Error())
+		assertEqual(t, "something went bad", fmt.Sprintf("%v", resultErr))
+		errMsgWithStack := fmt.Sprintf("%+v", resultErr)
+		matched, _ := regexp.MatchString(stackReg, errMsgWithStack)
+		if !assertTrue(t, matched) {
+			t.Log("regex", stackReg, "errMsgWithStack", errMsgWithStack)
+		}
+		assertEqual(
+			t,
+			map[string]any{"request_id": "req-123", "tenant": "acme"},
+			xerr.Fields(resultErr),
+		)
+	}
+}
+
+func testNewCtxWithoutContextValues(t *testing.T) {
+	// arrange
+	subject := xerr.NewCtx
+
+	// act
+	resultErr := subject(context.Background(), "something went bad")
+
+	// assert
+	if assertNotNil(t, resultErr) {
+		assertEqual(t, "something went bad", resultErr.Error())
+		assertNil(t, xerr.Fields(resultErr))
+	}
+}
+
+func TestWrapCtx(t *testing.T) {
+	t.Run("with context values", testWrapCtxWithContextValues)
+	t.Run("with nil error", testWrapCtxWithNilError)
+}
+
+func testWrapCtxWithContextValues(t *testing.T) {
+	// arrange
+	var (
+		subject = xerr.WrapCtx
+		origErr = errors.New("some standard error")
+		ctx     = context.WithValue(context.Background(), ctxTestKey("request_id"), "req-456")
+	)
+
+	// act
+	resultErr := subject(ctx, origErr, "something went bad")
+
+	// assert
+	if assertNotNil(t, resultErr) {
+		assertEqual(t, "something went bad: some standard error", resultErr.Error())
+		assertTrue(t, errors.Is(resultErr, origErr))
+		assertEqual(t, map[string]any{"request_id": "req-456"}, xerr.Fields(resultErr))
+	}
+}
+
+func testWrapCtxWithNilError(t *testing.T) {
+	// arrange
+	var (
+		subject = xerr.WrapCtx
+		ctx     = context.WithValue(context.Background(), ctxTestKey("request_id"), "req-789")
+	)
+
+	// act
+	resultErr := subject(ctx, nil, "something went bad")
+
+	// assert
+	assertNil(t, resultErr)
+}
+
+func TestSetContextExtractor(t *testing.T) {
+	// test is not parallel as it changes global configuration.
+
+	// arrange
+	xerr.SetContextExtractor(func(ctx context.Context) []xerr.Field {
+		if traceID, ok := ctx.Value(ctxTestKey("trace_id")).(string); ok {
+			return []xerr.Field{xerr.F("trace_id", traceID), xerr.F("tenant", "acme")}
+		}
+
+		return nil
+	})
+	xerr.SetContextExtractor(nil) // should be ignored
+	ctx := context.WithValue(context.Background(), ctxTestKey("trace_id"), "4bf92f35")
+	ctx = context.WithValue(ctx, ctxTestKey("request_id"), "req-123") // previous extractors are kept
+
+	// act
+	err := xerr.WrapCtx(ctx, errors.New("connection reset"), "could not save user")
+	errNoFields := xerr.NewCtx(context.Background(), "no fields")
+
+	// assert
+	assertEqual(
+		t,
+		map[string]any{"trace_id": "4bf92f35", "tenant": "acme", "request_id": "req-123"},
+		xerr.Fields(err),
+	)
+	assertNil(t, xerr.Fields(errNoFields))
+}
+
+func TestWrapContext(t *testing.T) {
+	t.Parallel()
+
+	t.Run("context not done", testWrapContextNotDone)
+	t.Run("context canceled", testWrapContextCanceled)
+	t.Run("context deadline exceeded", testWrapContextDeadlineExceeded)
+	t.Run("nil error", testWrapContextNilError)
+	t.Run("nil context", testWrapContextNilContext)
+}
+
+func testWrapContextNotDone(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		origErr     = errors.New("connection reset")
+		deadline    = time.Now().Add(time.Hour)
+		ctx, cancel = context.WithDeadline(context.Background(), deadline)
+	)
+	defer cancel()
+
+	// act
+	err := xerr.WrapContext(ctx, origErr, "could not save user")
+
+	// assert
+	assertEqual(t, "could not save user: connection reset", err.Error())
+	assertTrue(t, errors.Is(err, origErr))
+	assertFalse(t, xerr.IsCanceled(err))
+	assertFalse(t, xerr.IsDeadline(err))
+	fields := xerr.Fields(err)
+	assertEqual(t, false, fields["ctx_done"])
+	assertEqual(t, deadline, fields["ctx_deadline"])
+	remaining, _ := fields["ctx_remaining"].(time.Duration)
+	assertTrue(t, remaining > 59*time.Minute && remaining <= time.Hour)
+	_, found := fields["ctx_cause"]
+	assertFalse(t, found)
+}
+
+func testWrapContextCanceled(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		origErr     = errors.New("connection reset")
+		ctx, cancel = context.WithCancelCause(context.Background())
+	)
+	cancel(errors.New("client disconnected"))
+
+	// act
+	err := xerr.WrapContext(ctx, origErr, "could not save user")
+
+	// assert
+	assertTrue(t, xerr.IsCanceled(err))
+	assertFalse(t, xerr.IsDeadline(err))
+	assertFalse(t, errors.Is(err, context.Canceled))
+	assertEqual(t, map[string]any{"ctx_done": true, "ctx_cause": "client disconnected"}, xerr.Fields(err))
+}
+
+func testWrapContextDeadlineExceeded(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+
+	// act
+	err := xerr.WrapContext(ctx, ctx.Err(), "could not save user")
+
+	// assert
+	assertTrue(t, xerr.IsDeadline(err))
+	assertFalse(t, xerr.IsCanceled(err))
+	fields := xerr.Fields(err)
+	assertEqual(t, true, fields["ctx_done"])
+	assertEqual(t, "context deadline exceeded", fields["ctx_cause"])
+	remaining, _ := fields["ctx_remaining"].(time.Duration)
+	assertTrue(t, remaining < 0)
+}
+
+func testWrapContextNilError(t *testing.T) {
+	t.Parallel()
+
+	// act & assert
+	assertNil(t, xerr.WrapContext(context.Background(), nil, "msg"))
+}
+
+func testWrapContextNilContext(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		origErr = errors.New("connection reset")
+		nilCtx  context.Context
+	)
+
+	// act
+	err := xerr.WrapContext(nilCtx, origErr, "could not save user")
+
+	// assert
+	assertEqual(t, "could not save user: connection reset", err.Error())
+	assertTrue(t, errors.Is(err, origErr))
+	assertFalse(t, xerr.IsCanceled(err))
+	assertFalse(t, xerr.IsDeadline(err))
+	assertEqual(t, map[string]any{"ctx_done": false}, xerr.Fields(err))
+}
+
+func TestIsCanceled_IsDeadline(t *testing.T) {
+	t.Parallel()
+
+	// act & assert
+	assertTrue(t, xerr.IsCanceled(fmt.Errorf("request: %w", context.Canceled)))
+	assertFalse(t, xerr.IsCanceled(context.DeadlineExceeded))
+	assertTrue(t, xerr.IsDeadline(xerr.Wrap(context.DeadlineExceeded, "request")))
+	assertFalse(t, xerr.IsDeadline(errors.New("timeout")))
+	assertFalse(t, xerr.IsCanceled(nil))
+	assertFalse(t, xerr.IsDeadline(nil))
+}
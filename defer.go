@@ -0,0 +1,129 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr
+
+import (
+	"io"
+	"time"
+)
+
+// CloseAndAppend closes given closer and, if Close fails, merges its error,
+// annotated with a stack trace and the supplied message, into the error
+// errp points to. It is meant to be used in a defer statement, upon a named
+// return error:
+//
+//	func readFile(path string) (err error) {
+//		f, err := os.Open(path)
+//		if err != nil {
+//			return err
+//		}
+//		defer xerr.CloseAndAppend(&err, f, "could not close file")
+//
+//		// ... read the file.
+//	}
+//
+// If the error errp points to is nil, it becomes the Close error.
+// Otherwise, both errors are collected into a [MultiError] (if the error is
+// already a *MultiError, the Close error gets added to it).
+// A panic occurred inside Close is recovered and treated as a Close error.
+// A nil closer is ignored.
+func CloseAndAppend(errp *error, closer io.Closer, msg string) {
+	if closer == nil {
+		return
+	}
+
+	closeErr := safeClose(closer)
+	if closeErr == nil || errp == nil {
+		return
+	}
+
+	wErr := &stackError{
+		origErr:   closeErr,
+		msg:       msg,
+		createdAt: time.Now(),
+	}
+	wErr.stackPCs, wErr.stackBuf = wrapCallStack(closeErr, 0, maxStackFrames)
+
+	*errp = Append(*errp, created(wErr))
+}
+
+// DeferCapture calls fn and, if it fails, merges its error, annotated with
+// a stack trace, into the error errp points to. It is meant to be used in
+// a defer statement, upon a named return error, for Close / rollback like calls:
+//
+//	func saveUser(db *sql.DB, user User) (err error) {
+//		tx, err := db.Begin()
+//		if err != nil {
+//			return err
+//		}
+//		defer xerr.DeferCapture(&err, tx.Rollback)
+//
+//		// ...
+//	}
+//
+// The errors are merged like [AppendInto] does.
+// A panic occurred inside fn is recovered and treated as an fn error.
+// A nil fn is ignored.
+func DeferCapture(errp *error, fn func() error) {
+	if fn == nil {
+		return
+	}
+
+	fnErr := safeCall(fn)
+	if fnErr == nil || errp == nil {
+		return
+	}
+
+	wErr := &stackError{
+		origErr:   fnErr,
+		createdAt: time.Now(),
+	}
+	wErr.stackPCs, wErr.stackBuf = wrapCallStack(fnErr, 0, maxStackFrames)
+
+	*errp = Append(*errp, created(wErr))
+}
+
+// AppendInto merges newErr into the error errp points to, and reports
+// whether newErr was not nil.
+// If the error errp points to is nil, it becomes newErr.
+// Otherwise, both errors are collected into a [MultiError] (if the error is
+// already a *MultiError, newErr gets added to it), see [Append].
+// A nil errp is ignored.
+//
+// Example:
+//
+//	for _, item := range items {
+//		if xerr.AppendInto(&err, process(item)) {
+//			failed++
+//		}
+//	}
+func AppendInto(errp *error, newErr error) bool {
+	if newErr == nil {
+		return false
+	}
+	if errp != nil {
+		*errp = Append(*errp, newErr)
+	}
+
+	return true
+}
+
+// safeClose calls closer's Close, converting an eventual panic into an error, see [safeCall].
+func safeClose(closer io.Closer) error {
+	return safeCall(func() error { return closer.Close() })
+}
+
+// safeCall calls fn, converting an eventual panic into an error
+// with the panic's stack trace, see [Recover].
+func safeCall(fn func() error) (err error) {
+	defer func() {
+		if panicErr := Recover(recover()); panicErr != nil {
+			err = panicErr
+		}
+	}()
+
+	return fn()
+}
@@ -0,0 +1,376 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/actforgood/xerr"
+)
+
+// closerMock is a mock for io.Closer.
+type closerMock struct {
+	err        error
+	panicValue any
+	callsCnt   int
+}
+
+func (c *closerMock) Close() error {
+	c.callsCnt++
+	if c.panicValue != nil {
+		panic(c.panicValue)
+	}
+
+	return c.err
+}
+
+func TestCloseAndAppend(t *testing.T) {
+	t.Parallel()
+
+	t.Run("close succeeds, nil error", testCloseAndAppendSuccessNilErr)
+	t.Run("close succeeds, error is kept", testCloseAndAppendSuccessWithErr)
+	t.Run("close fails, nil error", testCloseAndAppendFailNilErr)
+	t.Run("close fails, error becomes MultiError", testCloseAndAppendFailWithErr)
+	t.Run("close fails, MultiError error", testCloseAndAppendFailWithMultiErr)
+	t.Run("close panics", testCloseAndAppendPanic)
+	t.Run("nil closer", testCloseAndAppendNilCloser)
+}
+
+func testCloseAndAppendSuccessNilErr(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	closer := new(closerMock)
+
+	// act
+	err := func() (err error) {
+		defer xerr.CloseAndAppend(&err, closer, "close")
+
+		return nil
+	}()
+
+	// assert
+	assertNil(t, err)
+	assertEqual(t, 1, closer.callsCnt)
+}
+
+func testCloseAndAppendSuccessWithErr(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	closer := new(closerMock)
+
+	// act
+	err := func() (err error) {
+		defer xerr.CloseAndAppend(&err, closer, "close")
+
+		return io.ErrUnexpectedEOF
+	}()
+
+	// assert
+	assertEqual(t, io.ErrUnexpectedEOF, err)
+	assertEqual(t, 1, closer.callsCnt)
+}
+
+func testCloseAndAppendFailNilErr(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		closer   = &closerMock{err: io.ErrClosedPipe}
+		stackReg = `could not close: io: read/write on closed pipe\n` +
+			`github\.com/actforgood/xerr_test\.testCloseAndAppendFailNilErr\.func1\n\t.+defer_test\.go:\d+`
+	)
+
+	// act
+	err := func() (err error) {
+		defer xerr.CloseAndAppend(&err, closer, "could not close")
+
+		return nil
+	}()
+
+	// assert
+	if assertNotNil(t, err) {
+		assertEqual(t, "could not close: io: read/write on closed pipe", err.Error())
+		assertTrue(t, errors.Is(err, io.ErrClosedPipe))
+		errMsgWithStack := fmt.Sprintf("%+v", err)
+		matched, _ := regexp.MatchString(stackReg, errMsgWithStack)
+		if !assertTrue(t, matched) {
+			t.Log("regex", stackReg, "errMsgWithStack", errMsgWithStack)
+		}
+	}
+	assertEqual(t, 1, closer.callsCnt)
+}
+
+func testCloseAndAppendFailWithErr(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	closer := &closerMock{err: io.ErrClosedPipe}
+
+	// act
+	err := func() (err error) {
+		defer xerr.CloseAndAppend(&err, closer, "could not close")
+
+		return io.ErrUnexpectedEOF
+	}()
+
+	// assert
+	var mErr *xerr.MultiError
+	if assertTrue(t, errors.As(err, &mErr)) {
+		errs := mErr.Errors()
+		if assertEqual(t, 2, len(errs)) {
+			assertEqual(t, io.ErrUnexpectedEOF, errs[0])
+			assertTrue(t, errors.Is(errs[1], io.ErrClosedPipe))
+		}
+	}
+}
+
+func testCloseAndAppendFailWithMultiErr(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		closer   = &closerMock{err: io.ErrClosedPipe}
+		multiErr = xerr.NewMultiError().Add(io.ErrUnexpectedEOF, io.ErrShortWrite)
+	)
+
+	// act
+	err := func() (err error) {
+		defer xerr.CloseAndAppend(&err, closer, "could not close")
+
+		return multiErr
+	}()
+
+	// assert
+	assertEqual(t, multiErr, err)
+	errs := multiErr.Errors()
+	if assertEqual(t, 3, len(errs)) {
+		assertTrue(t, errors.Is(errs[2], io.ErrClosedPipe))
+	}
+}
+
+func testCloseAndAppendPanic(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	closer := &closerMock{panicValue: "boom"}
+
+	// act
+	err := func() (err error) {
+		defer xerr.CloseAndAppend(&err, closer, "could not close")
+
+		return nil
+	}()
+
+	// assert
+	if assertNotNil(t, err) {
+		assertEqual(t, "could not close: panic: boom", err.Error())
+		assertPanicSiteFrame(t, err)
+	}
+	assertEqual(t, 1, closer.callsCnt)
+}
+
+func testCloseAndAppendNilCloser(t *testing.T) {
+	t.Parallel()
+
+	// act
+	err := func() (err error) {
+		defer xerr.CloseAndAppend(&err, nil, "could not close")
+
+		return nil
+	}()
+
+	// assert
+	assertNil(t, err)
+}
+
+func TestDeferCapture(t *testing.T) {
+	t.Parallel()
+
+	t.Run("fn succeeds, error is kept", testDeferCaptureSuccess)
+	t.Run("fn fails, nil error", testDeferCaptureFailNilErr)
+	t.Run("fn fails, error becomes MultiError", testDeferCaptureFailWithErr)
+	t.Run("fn panics", testDeferCapturePanic)
+	t.Run("fn panics with an error", testDeferCapturePanicWithError)
+	t.Run("nil fn", testDeferCaptureNilFn)
+}
+
+func testDeferCaptureSuccess(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	closer := new(closerMock)
+
+	// act
+	err := func() (err error) {
+		defer xerr.DeferCapture(&err, closer.Close)
+
+		return io.ErrUnexpectedEOF
+	}()
+
+	// assert
+	assertEqual(t, io.ErrUnexpectedEOF, err)
+	assertEqual(t, 1, closer.callsCnt)
+}
+
+func testDeferCaptureFailNilErr(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		closer   = &closerMock{err: io.ErrClosedPipe}
+		stackReg = `^io: read/write on closed pipe\n` +
+			`github\.com/actforgood/xerr_test\.testDeferCaptureFailNilErr\.func1\n\t.+defer_test\.go:\d+`
+	)
+
+	// act
+	err := func() (err error) {
+		defer xerr.DeferCapture(&err, closer.Close)
+
+		return nil
+	}()
+
+	// assert
+	if assertNotNil(t, err) {
+		assertEqual(t, "io: read/write on closed pipe", err.Error())
+		assertTrue(t, errors.Is(err, io.ErrClosedPipe))
+		errMsgWithStack := fmt.Sprintf("%+v", err)
+		matched, _ := regexp.MatchString(stackReg, errMsgWithStack)
+		if !assertTrue(t, matched) {
+			t.Log("regex", stackReg, "errMsgWithStack", errMsgWithStack)
+		}
+	}
+	assertEqual(t, 1, closer.callsCnt)
+}
+
+func testDeferCaptureFailWithErr(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	rollback := func() error { return io.ErrClosedPipe }
+
+	// act
+	err := func() (err error) {
+		defer xerr.DeferCapture(&err, rollback)
+
+		return io.ErrUnexpectedEOF
+	}()
+
+	// assert
+	var mErr *xerr.MultiError
+	if assertTrue(t, errors.As(err, &mErr)) {
+		errs := mErr.Errors()
+		if assertEqual(t, 2, len(errs)) {
+			assertEqual(t, io.ErrUnexpectedEOF, errs[0])
+			assertTrue(t, errors.Is(errs[1], io.ErrClosedPipe))
+		}
+	}
+}
+
+func testDeferCapturePanic(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	closer := &closerMock{panicValue: "boom"}
+
+	// act
+	err := func() (err error) {
+		defer xerr.DeferCapture(&err, closer.Close)
+
+		return nil
+	}()
+
+	// assert
+	if assertNotNil(t, err) {
+		assertEqual(t, "panic: boom", err.Error())
+		assertPanicSiteFrame(t, err)
+	}
+}
+
+func testDeferCapturePanicWithError(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	closer := &closerMock{panicValue: io.ErrClosedPipe}
+
+	// act
+	err := func() (err error) {
+		defer xerr.DeferCapture(&err, closer.Close)
+
+		return nil
+	}()
+
+	// assert
+	if assertNotNil(t, err) {
+		assertTrue(t, errors.Is(err, io.ErrClosedPipe))
+		assertPanicSiteFrame(t, err)
+	}
+}
+
+// assertPanicSiteFrame checks that the error's stack trace goes through the panic site.
+func assertPanicSiteFrame(t *testing.T, err error) {
+	t.Helper()
+
+	var found bool
+	for _, f := range xerr.Frames(err) {
+		if strings.HasSuffix(f.Function, ".(*closerMock).Close") {
+			found = true
+
+			break
+		}
+	}
+	assertTrue(t, found)
+}
+
+func testDeferCaptureNilFn(t *testing.T) {
+	t.Parallel()
+
+	// act
+	err := func() (err error) {
+		defer xerr.DeferCapture(&err, nil)
+
+		return io.ErrUnexpectedEOF
+	}()
+
+	// assert
+	assertEqual(t, io.ErrUnexpectedEOF, err)
+}
+
+func TestAppendInto(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		err      error
+		err1     = errors.New("err 1")
+		err2     = errors.New("err 2")
+		nilErrp  *error
+		appended []bool
+	)
+
+	// act
+	appended = append(
+		appended,
+		xerr.AppendInto(&err, nil),
+		xerr.AppendInto(&err, err1),
+		xerr.AppendInto(&err, nil),
+		xerr.AppendInto(&err, err2),
+		xerr.AppendInto(nilErrp, err2),
+	)
+
+	// assert
+	assertEqual(t, []bool{false, true, false, true, true}, appended)
+	var mErr *xerr.MultiError
+	if assertTrue(t, errors.As(err, &mErr)) {
+		assertEqual(t, []error{err1, err2}, mErr.Errors())
+	}
+}
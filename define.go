@@ -0,0 +1,86 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr
+
+import (
+	"fmt"
+	"time"
+)
+
+// Definition is an application error template, having a code and a message format,
+// instantiated with [Definition.New] / [Definition.Wrap].
+// Errors instantiated from a definition are identified by it with [errors.Is].
+//
+// Example:
+//
+//	var ErrOrderNotShippable = xerr.Define("ORD-0042", "order %s cannot be shipped: %s")
+//
+//	err := ErrOrderNotShippable.New(orderID, "address is missing")
+//	errors.Is(err, ErrOrderNotShippable) // true
+//	xerr.CodeOf(err)                     // "ORD-0042"
+type Definition struct {
+	code   Code
+	format string
+}
+
+// Define returns a new [Definition] with given code and message format
+// (see [fmt.Sprintf] for format specifiers).
+func Define(code Code, format string) *Definition {
+	return &Definition{
+		code:   code,
+		format: format,
+	}
+}
+
+// Code returns the definition's code.
+func (def *Definition) Code() Code {
+	return def.code
+}
+
+// Error returns the definition's message format.
+// Implements std error interface, so that a definition can be used
+// as target of [errors.Is].
+func (def *Definition) Error() string {
+	return def.format
+}
+
+// New returns an error with the definition's code and message formatted
+// with given arguments.
+// New also records the stack trace at the point it was called.
+func (def *Definition) New(args ...any) error {
+	return created(def.instance(&stackError{
+		msg:       fmt.Sprintf(def.format, args...),
+		stackPCs:  getCallStackSkip(0, maxStackFrames),
+		createdAt: time.Now(),
+	}))
+}
+
+// Wrap returns an error annotating cause with the definition's code and
+// message formatted with given arguments.
+// Wrap also records the stack trace at the point it was called, the same way [Wrap] does.
+// If cause is nil, Wrap returns nil.
+func (def *Definition) Wrap(cause error, args ...any) error {
+	if cause == nil {
+		return nil
+	}
+
+	wErr := &stackError{
+		origErr:   cause,
+		msg:       fmt.Sprintf(def.format, args...),
+		createdAt: time.Now(),
+	}
+	wErr.stackPCs, wErr.stackBuf = wrapCallStack(cause, 0, maxStackFrames)
+
+	return created(def.instance(wErr))
+}
+
+// instance annotates given error with the definition's code and identity.
+func (def *Definition) instance(err error) error {
+	return &markedError{
+		err:      withValue(err, codeKey{}, def.code),
+		sentinel: def,
+	}
+}
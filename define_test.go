@@ -0,0 +1,68 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr_test
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/actforgood/xerr"
+)
+
+var (
+	errDefOrderNotShippable = xerr.Define("ORD-0042", "order %s cannot be shipped: %s")
+	errDefOrderNotFound     = xerr.Define("ORD-0001", "order %s not found")
+)
+
+func TestDefinition_New(t *testing.T) {
+	t.Parallel()
+
+	// act
+	result := errDefOrderNotShippable.New("o-1", "address is missing")
+
+	// assert
+	assertEqual(t, "order o-1 cannot be shipped: address is missing", result.Error())
+	assertEqual(t, xerr.Code("ORD-0042"), xerr.CodeOf(result))
+	assertEqual(t, xerr.Code("ORD-0042"), errDefOrderNotShippable.Code())
+	assertTrue(t, errors.Is(result, errDefOrderNotShippable))
+	assertTrue(t, errors.Is(xerr.Wrap(result, "ship"), errDefOrderNotShippable))
+	assertFalse(t, errors.Is(result, errDefOrderNotFound))
+	frames := xerr.Frames(result)
+	if assertTrue(t, len(frames) > 0) {
+		assertEqual(t, "github.com/actforgood/xerr_test.TestDefinition_New", frames[0].Function)
+	}
+}
+
+func TestDefinition_Wrap(t *testing.T) {
+	t.Parallel()
+
+	// act
+	result := errDefOrderNotFound.Wrap(io.EOF, "o-1")
+	nilResult := errDefOrderNotFound.Wrap(nil, "o-1")
+
+	// assert
+	assertEqual(t, "order o-1 not found: EOF", result.Error())
+	assertEqual(t, xerr.Code("ORD-0001"), xerr.CodeOf(result))
+	assertTrue(t, errors.Is(result, errDefOrderNotFound))
+	assertTrue(t, errors.Is(result, io.EOF))
+	assertFalse(t, errors.Is(result, errDefOrderNotShippable))
+	frames := xerr.Frames(result)
+	if assertTrue(t, len(frames) > 0) {
+		assertEqual(t, "github.com/actforgood/xerr_test.TestDefinition_Wrap", frames[0].Function)
+	}
+	assertNil(t, nilResult)
+}
+
+func TestDefinition_Error(t *testing.T) {
+	t.Parallel()
+
+	// act
+	result := errDefOrderNotFound.Error()
+
+	// assert
+	assertEqual(t, "order %s not found", result)
+}
@@ -0,0 +1,137 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr
+
+import (
+	"encoding/gob"
+	"errors"
+)
+
+func init() {
+	// register the error types, so that they can be gob encoded as error interface values.
+	gob.Register(new(stackError))
+	gob.Register(new(valueError))
+	gob.Register(new(MultiError))
+}
+
+// MarshalText implements [encoding.TextMarshaler].
+// The text form of the error is its JSON form, see [Serializer.Marshal].
+func (err *stackError) MarshalText() ([]byte, error) {
+	return defaultSerializer.Marshal(err)
+}
+
+// UnmarshalText implements [encoding.TextUnmarshaler].
+// It decodes the JSON form of an error, see [Serializer.Unmarshal].
+// The decoded error has the same message, severity, code, fields and ID.
+func (err *stackError) UnmarshalText(data []byte) error {
+	decoded, decodeErr := defaultSerializer.Unmarshal(data)
+	if decodeErr != nil {
+		return decodeErr
+	}
+	if decoded == nil {
+		return errors.New("xerr: cannot decode null into an error")
+	}
+
+	err.origErr = decoded
+	err.msg = ""
+	err.stackPCs, err.stackBuf = nil, nil
+	err.errMsg.Store(nil)
+
+	return nil
+}
+
+// GobEncode implements [gob.GobEncoder], the error is encoded in its text form.
+func (err *stackError) GobEncode() ([]byte, error) {
+	return err.MarshalText()
+}
+
+// GobDecode implements [gob.GobDecoder], see [stackError.UnmarshalText].
+func (err *stackError) GobDecode(data []byte) error {
+	return err.UnmarshalText(data)
+}
+
+// MarshalText implements [encoding.TextMarshaler].
+// The text form of the error is its JSON form, see [Serializer.Marshal].
+func (err *valueError) MarshalText() ([]byte, error) {
+	return defaultSerializer.Marshal(err)
+}
+
+// UnmarshalText implements [encoding.TextUnmarshaler].
+// It decodes the JSON form of an error, see [Serializer.Unmarshal].
+// The decoded error has the same message, severity, code, fields and ID.
+func (err *valueError) UnmarshalText(data []byte) error {
+	decoded, decodeErr := defaultSerializer.Unmarshal(data)
+	if decodeErr != nil {
+		return decodeErr
+	}
+	if decoded == nil {
+		return errors.New("xerr: cannot decode null into an error")
+	}
+
+	err.err = decoded
+	err.key, err.val = decodedKey{}, nil
+
+	return nil
+}
+
+// GobEncode implements [gob.GobEncoder], the error is encoded in its text form.
+func (err *valueError) GobEncode() ([]byte, error) {
+	return err.MarshalText()
+}
+
+// GobDecode implements [gob.GobDecoder], see [valueError.UnmarshalText].
+func (err *valueError) GobDecode(data []byte) error {
+	return err.UnmarshalText(data)
+}
+
+// MarshalText implements [encoding.TextMarshaler].
+// The text form of the multi-error is its JSON form, see [Serializer.Marshal],
+// JSON null if it does not store any error.
+func (mErr *MultiError) MarshalText() ([]byte, error) {
+	if mErr.Len() == 0 {
+		return defaultSerializer.Marshal(nil)
+	}
+
+	return defaultSerializer.Marshal(mErr)
+}
+
+// UnmarshalText implements [encoding.TextUnmarshaler].
+// It decodes the JSON form of a multi-error, see [Serializer.Unmarshal],
+// replacing the stored errors with the decoded ones.
+func (mErr *MultiError) UnmarshalText(data []byte) error {
+	decoded, decodeErr := defaultSerializer.Unmarshal(data)
+	if decodeErr != nil {
+		return decodeErr
+	}
+
+	mErr.Reset()
+	var decodedMErr *MultiError
+	switch {
+	case decoded == nil:
+	case errors.As(decoded, &decodedMErr):
+		for _, err := range decodedMErr.Errors() {
+			_ = mErr.Add(err)
+		}
+	default:
+		_ = mErr.Add(decoded)
+	}
+
+	return nil
+}
+
+// GobEncode implements [gob.GobEncoder], the multi-error is encoded in its text form.
+func (mErr *MultiError) GobEncode() ([]byte, error) {
+	return mErr.MarshalText()
+}
+
+// GobDecode implements [gob.GobDecoder], see [MultiError.UnmarshalText].
+func (mErr *MultiError) GobDecode(data []byte) error {
+	return mErr.UnmarshalText(data)
+}
+
+// decodedKey is the annotation key of a decoded value error,
+// which does not hold an annotation itself.
+type decodedKey struct{}
@@ -0,0 +1,123 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr_test
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/gob"
+	"errors"
+	"testing"
+
+	"github.com/actforgood/xerr"
+)
+
+// jobResult is a struct holding an error, like one sent over net/rpc or persisted by a job queue.
+type jobResult struct {
+	JobID int
+	Err   error
+}
+
+func TestGob(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	mErr := xerr.NewMultiError()
+	_ = mErr.Add(xerr.NewCode("E001", "first"))
+	_ = mErr.Add(xerr.WithFields(errors.New("second"), xerr.F("attempt", "3")))
+	tests := [...]struct {
+		name     string
+		inputErr error
+		check    func(t *testing.T, decoded error)
+	}{
+		{
+			name:     "stack error",
+			inputErr: xerr.Wrap(xerr.New("not found"), "could not load user"),
+			check: func(t *testing.T, decoded error) {
+				t.Helper()
+				assertEqual(t, "could not load user: not found", decoded.Error())
+			},
+		},
+		{
+			name:     "annotated error",
+			inputErr: xerr.WithFields(xerr.NewCode("NOT_FOUND", "user not found"), xerr.F("user_id", "42")),
+			check: func(t *testing.T, decoded error) {
+				t.Helper()
+				assertEqual(t, "user not found", decoded.Error())
+				assertEqual(t, xerr.Code("NOT_FOUND"), xerr.CodeOf(decoded))
+				assertEqual(t, map[string]any{"user_id": "42"}, xerr.Fields(decoded))
+			},
+		},
+		{
+			name:     "multi error",
+			inputErr: mErr,
+			check: func(t *testing.T, decoded error) {
+				t.Helper()
+				var decodedMErr *xerr.MultiError
+				if assertTrue(t, errors.As(decoded, &decodedMErr)) {
+					errs := decodedMErr.Errors()
+					if assertEqual(t, 2, len(errs)) {
+						assertEqual(t, xerr.Code("E001"), xerr.CodeOf(errs[0]))
+						assertEqual(t, map[string]any{"attempt": "3"}, xerr.Fields(errs[1]))
+					}
+				}
+			},
+		},
+	}
+
+	for _, testData := range tests {
+		test := testData // capture range variable
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			// arrange
+			var (
+				buf    bytes.Buffer
+				result jobResult
+			)
+
+			// act
+			encodeErr := gob.NewEncoder(&buf).Encode(jobResult{JobID: 1, Err: test.inputErr})
+			decodeErr := gob.NewDecoder(&buf).Decode(&result)
+
+			// assert
+			assertNil(t, encodeErr)
+			assertNil(t, decodeErr)
+			assertEqual(t, 1, result.JobID)
+			if assertNotNil(t, result.Err) {
+				test.check(t, result.Err)
+			}
+		})
+	}
+}
+
+func TestMarshalText(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		inputErr = xerr.WithFields(xerr.NewCode("NOT_FOUND", "user not found"), xerr.F("user_id", "42"))
+		mErr     = xerr.NewMultiError()
+	)
+
+	// act
+	text, err := inputErr.(encoding.TextMarshaler).MarshalText()
+	emptyText, emptyErr := mErr.MarshalText()
+	unmarshalErr := mErr.UnmarshalText(text)
+
+	// assert
+	assertNil(t, err)
+	assertNil(t, emptyErr)
+	assertEqual(t, "null", string(emptyText))
+	assertNil(t, unmarshalErr)
+	if errs := mErr.Errors(); assertEqual(t, 1, len(errs)) {
+		assertEqual(t, "user not found", errs[0].Error())
+		assertEqual(t, xerr.Code("NOT_FOUND"), xerr.CodeOf(errs[0]))
+	}
+	assertNotNil(t, mErr.UnmarshalText([]byte("not json")))
+	assertNil(t, mErr.UnmarshalText([]byte("null")))
+	assertEqual(t, 0, mErr.Len())
+}
@@ -0,0 +1,109 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"encoding/binary"
+	"sync/atomic"
+	"time"
+)
+
+// errorIDGenerator is the globally configured error ID generator.
+// If nil, errors are not stamped with an ID.
+var errorIDGenerator func() string
+
+// errorIDKey is the annotation key under which a decoded error's ID is stored.
+type errorIDKey struct{}
+
+// SetErrorIDGenerator configures the function this package uses in order to stamp
+// each created error with a unique ID, which can be retrieved with [ID].
+// The ID is written in the extended (%+v) format, in the JSON form of the error
+// (see [Serializer]), and in HTTP problem responses, so that a user reported
+// "error id ab12cd" can be correlated with server logs.
+// An error wrapping another error having an ID inherits its ID.
+// By default, errors are not stamped. Pass nil to stop stamping errors.
+// You will call it usually somewhere in the bootstrap process of your
+// application. For example:
+//
+//	// myapp/bootstrap.go
+//	func init() {
+//		xerr.SetErrorIDGenerator(xerr.NewErrorID)
+//	}
+func SetErrorIDGenerator(fn func() string) {
+	errorIDGenerator = fn
+}
+
+// ID returns the unique ID an error was stamped with, see [SetErrorIDGenerator].
+// Returns empty string if the error does not have an ID.
+func ID(err error) string {
+	var id string
+	walkChain(err, func(e error) bool {
+		switch x := e.(type) {
+		case *stackError:
+			id = x.id
+		case *valueError:
+			if x.key == (errorIDKey{}) {
+				id, _ = x.val.(string)
+			}
+		}
+
+		return id == ""
+	})
+
+	return id
+}
+
+// stampID stamps the outermost stack error of given, just created, error, with an ID,
+// if an error ID generator is configured.
+func stampID(err error) {
+	if errorIDGenerator == nil {
+		return
+	}
+
+	walkChain(err, func(e error) bool {
+		sErr, ok := e.(*stackError)
+		if !ok {
+			return true
+		}
+		if sErr.id == "" {
+			if sErr.id = ID(sErr.origErr); sErr.id == "" {
+				sErr.id = errorIDGenerator()
+			}
+		}
+
+		return false
+	})
+}
+
+var (
+	// errorIDEncoding is the encoding of error IDs: lowercase base32, sortable, without padding.
+	errorIDEncoding = base32.NewEncoding("0123456789abcdefghijklmnopqrstuv").WithPadding(base32.NoPadding)
+	// errorIDProcess is a random value, unique to this process.
+	errorIDProcess = func() [5]byte {
+		var b [5]byte
+		_, _ = rand.Read(b[:])
+
+		return b
+	}()
+	// errorIDCounter is incremented for every ID.
+	errorIDCounter atomic.Uint32
+)
+
+// NewErrorID returns a short (20 characters), globally unique, roughly time sortable ID,
+// in the spirit of xid: 4 bytes of unix time (seconds), 5 bytes unique to the process,
+// and a 3 bytes counter, base32 encoded.
+// It can be used as error ID generator, see [SetErrorIDGenerator].
+func NewErrorID() string {
+	var b [12]byte
+	binary.BigEndian.PutUint32(b[:4], uint32(time.Now().Unix()))
+	copy(b[4:9], errorIDProcess[:])
+	counter := errorIDCounter.Add(1)
+	b[9], b[10], b[11] = byte(counter>>16), byte(counter>>8), byte(counter)
+
+	return errorIDEncoding.EncodeToString(b[:])
+}
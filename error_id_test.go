@@ -0,0 +1,76 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr_test
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/actforgood/xerr"
+)
+
+func TestSetErrorIDGenerator(t *testing.T) {
+	// test is not parallel as it changes global configuration.
+
+	// arrange
+	var generated int
+	xerr.SetErrorIDGenerator(func() string {
+		generated++
+
+		return fmt.Sprintf("id%d", generated)
+	})
+	defer xerr.SetErrorIDGenerator(nil)
+
+	// act
+	err1 := xerr.New("not found")
+	err2 := xerr.Wrap(err1, "could not load user")
+	err3 := xerr.WrapOpt(errors.New("timeout"), "could not call API", xerr.WithOnError(func(error) {}))
+	err4 := xerr.WithCode(xerr.New("invalid"), "E_INVALID")
+
+	// assert
+	assertEqual(t, "id1", xerr.ID(err1))
+	assertEqual(t, "id1", xerr.ID(err2)) // inherited
+	assertEqual(t, "id2", xerr.ID(err3))
+	assertEqual(t, "id3", xerr.ID(err4))
+	assertEqual(t, "", xerr.ID(errors.New("std error")))
+	assertEqual(t, "", xerr.ID(nil))
+	assertTrue(t, strings.HasPrefix(fmt.Sprintf("%+v", err2), "could not load user: not found (error id: id1)\n"))
+	assertEqual(t, "could not load user: not found", fmt.Sprintf("%v", err2))
+
+	// act & assert - JSON round trip
+	data, err := xerr.NewSerializer().Marshal(err2)
+	assertNil(t, err)
+	assertTrue(t, strings.Contains(string(data), `"id":"id1"`))
+	decodedErr, err := xerr.NewSerializer().Unmarshal(data)
+	assertNil(t, err)
+	assertEqual(t, "id1", xerr.ID(decodedErr))
+
+	// act & assert - stamping is stopped
+	xerr.SetErrorIDGenerator(nil)
+	assertEqual(t, "", xerr.ID(xerr.New("not found")))
+}
+
+func TestNewErrorID(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		idsNo = 1000
+		ids   = make(map[string]struct{}, idsNo)
+		regex = regexp.MustCompile(`^[0-9a-v]{20}$`)
+	)
+
+	// act & assert
+	for i := 0; i < idsNo; i++ {
+		id := xerr.NewErrorID()
+		assertTrue(t, regex.MatchString(id))
+		ids[id] = struct{}{}
+	}
+	assertEqual(t, idsNo, len(ids))
+}
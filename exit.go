@@ -0,0 +1,110 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr
+
+import (
+	"io"
+	"os"
+)
+
+// fatalVerbose tells whether [Fatal] prints the error's stack trace.
+var fatalVerbose bool
+
+// exitCodeKey is the annotation key under which an error's exit code is stored.
+type exitCodeKey struct{}
+
+// WithExitCode returns an error annotating err with the process exit code
+// which should be used if the error is fatal.
+// If err is nil, WithExitCode returns nil.
+func WithExitCode(err error, code int) error {
+	if err == nil {
+		return nil
+	}
+
+	return withValue(err, exitCodeKey{}, code)
+}
+
+// ExitCode returns the process exit code mapped to an error,
+// the outermost one found in its chain.
+// It returns 0 for a nil error, and 1 for an error without an exit code attached.
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	if code, found := lookupValue(err, exitCodeKey{}); found {
+		return code.(int)
+	}
+
+	return 1
+}
+
+// FatalIf does nothing if err is nil. Otherwise, it reports the error
+// to the given reporters, flushes the asynchronous ones, and terminates
+// the program with the error's exit code (see [ExitCode]).
+// If no reporter is provided, the error, with its stack trace,
+// is written to standard error.
+//
+// Example:
+//
+//	func main() {
+//		err := run()
+//		xerr.FatalIf(err)
+//	}
+func FatalIf(err error, reporters ...Reporter) {
+	if err == nil {
+		return
+	}
+
+	if len(reporters) == 0 {
+		reporters = []Reporter{NewWriterReporter(os.Stderr)}
+	}
+	report(err, reporters)
+
+	os.Exit(ExitCode(err))
+}
+
+// SetFatalVerbose configures whether [Fatal] prints, besides the error's message,
+// its stack trace. Defaults to false.
+// You will call it usually after parsing your CLI's flags. For example:
+//
+//	// myapp/main.go
+//	func main() {
+//		verbose := flag.Bool("v", false, "verbose output")
+//		flag.Parse()
+//		xerr.SetFatalVerbose(*verbose)
+//		...
+//	}
+func SetFatalVerbose(verbose bool) {
+	fatalVerbose = verbose
+}
+
+// Fatal does nothing if err is nil. Otherwise, it prints the error to standard error
+// and terminates the program with the error's exit code (see [ExitCode]).
+// If verbose output is enabled (see [SetFatalVerbose]), the error is printed
+// with its stack trace, in the human readable form [Pretty] returns,
+// otherwise only its message is printed.
+// It is meant to be the single path from an error to the process exit, in CLI tools.
+//
+// Example:
+//
+//	func main() {
+//		if err := run(); err != nil {
+//			xerr.Fatal(xerr.WithExitCode(err, 2))
+//		}
+//	}
+func Fatal(err error) {
+	if err == nil {
+		return
+	}
+
+	msg := err.Error()
+	if fatalVerbose {
+		msg = Pretty(err)
+	}
+	_, _ = io.WriteString(os.Stderr, msg+"\n")
+
+	os.Exit(ExitCode(err))
+}
@@ -0,0 +1,239 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/actforgood/xerr"
+)
+
+func TestExitCode(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		subject = xerr.ExitCode
+		stdErr  = errors.New("some standard error")
+		tests   = [...]struct {
+			name     string
+			inputErr error
+			expected int
+		}{
+			{
+				name:     "nil error",
+				inputErr: nil,
+				expected: 0,
+			},
+			{
+				name:     "error without exit code",
+				inputErr: stdErr,
+				expected: 1,
+			},
+			{
+				name:     "error with exit code",
+				inputErr: xerr.WithExitCode(stdErr, 3),
+				expected: 3,
+			},
+			{
+				name:     "wrapped error with exit code",
+				inputErr: fmt.Errorf("wrap: %w", xerr.Wrap(xerr.WithExitCode(stdErr, 4), "wrap")),
+				expected: 4,
+			},
+			{
+				name:     "error with multiple exit codes, expect outermost",
+				inputErr: xerr.WithExitCode(xerr.WithExitCode(stdErr, 4), 5),
+				expected: 5,
+			},
+		}
+	)
+
+	for _, testData := range tests {
+		test := testData // capture range variable
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			// act
+			result := subject(test.inputErr)
+
+			// assert
+			assertEqual(t, test.expected, result)
+		})
+	}
+
+	assertNil(t, xerr.WithExitCode(nil, 2))
+}
+
+// flusherMock is a Reporter & Flusher mock.
+type flusherMock struct {
+	reported []string
+}
+
+func (m *flusherMock) Report(err error) {
+	m.reported = append(m.reported, "report: "+err.Error())
+}
+
+func (m *flusherMock) Flush() {
+	m.reported = append(m.reported, "flush")
+	// output what was reported, so that the parent process can check it.
+	fmt.Fprintln(os.Stdout, strings.Join(m.reported, ","))
+}
+
+func TestFatalIf(t *testing.T) {
+	t.Parallel()
+
+	// Note: FatalIf terminates the process, it's tested in a subprocess.
+	if mode := os.Getenv("XERR_TEST_FATAL_IF"); mode != "" {
+		switch mode {
+		case "nil":
+			xerr.FatalIf(nil)
+			os.Exit(0)
+		case "default":
+			xerr.FatalIf(xerr.WithExitCode(xerr.New("something went bad"), 3))
+		case "reporter":
+			xerr.FatalIf(xerr.WithExitCode(errors.New("something went bad"), 4), new(flusherMock))
+		}
+
+		return
+	}
+
+	tests := [...]struct {
+		name             string
+		mode             string
+		expectedExitCode int
+		expectedStdout   string
+		expectedStderr   string
+	}{
+		{
+			name:             "nil error, no exit",
+			mode:             "nil",
+			expectedExitCode: 0,
+		},
+		{
+			name:             "default reporter",
+			mode:             "default",
+			expectedExitCode: 3,
+			expectedStderr:   "something went bad\ngithub.com/actforgood/xerr_test.TestFatalIf",
+		},
+		{
+			name:             "custom reporter, flushed",
+			mode:             "reporter",
+			expectedExitCode: 4,
+			expectedStdout:   "report: something went bad,flush",
+		},
+	}
+
+	for _, testData := range tests {
+		test := testData // capture range variable
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			// arrange
+			var stdout, stderr strings.Builder
+			cmd := exec.Command(os.Args[0], "-test.run=^TestFatalIf$")
+			cmd.Env = append(os.Environ(), "XERR_TEST_FATAL_IF="+test.mode)
+			cmd.Stdout = &stdout
+			cmd.Stderr = &stderr
+
+			// act
+			err := cmd.Run()
+
+			// assert
+			exitCode := 0
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) {
+				exitCode = exitErr.ExitCode()
+			}
+			assertEqual(t, test.expectedExitCode, exitCode)
+			assertTrue(t, strings.HasPrefix(stdout.String(), test.expectedStdout))
+			if !assertTrue(t, strings.HasPrefix(stderr.String(), test.expectedStderr)) {
+				t.Log("stderr", stderr.String())
+			}
+		})
+	}
+}
+
+func TestFatal(t *testing.T) {
+	t.Parallel()
+
+	// Note: Fatal terminates the process, it's tested in a subprocess.
+	if mode := os.Getenv("XERR_TEST_FATAL"); mode != "" {
+		switch mode {
+		case "nil":
+			xerr.Fatal(nil)
+			os.Exit(0)
+		case "quiet":
+			xerr.Fatal(xerr.WithExitCode(xerr.New("something went bad"), 3))
+		case "verbose":
+			xerr.SetFatalVerbose(true)
+			xerr.Fatal(xerr.Wrap(errors.New("something went bad"), "could not run"))
+		}
+
+		return
+	}
+
+	tests := [...]struct {
+		name             string
+		mode             string
+		expectedExitCode int
+		expectedStderr   string
+	}{
+		{
+			name:             "nil error, no exit",
+			mode:             "nil",
+			expectedExitCode: 0,
+			expectedStderr:   "",
+		},
+		{
+			name:             "message only",
+			mode:             "quiet",
+			expectedExitCode: 3,
+			expectedStderr:   "something went bad\n",
+		},
+		{
+			name:             "verbose, with stack trace",
+			mode:             "verbose",
+			expectedExitCode: 1,
+			expectedStderr:   "could not run: something went bad\ngithub.com/actforgood/xerr_test.TestFatal\n\t",
+		},
+	}
+
+	for _, testData := range tests {
+		test := testData // capture range variable
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			// arrange
+			var stderr strings.Builder
+			cmd := exec.Command(os.Args[0], "-test.run=^TestFatal$")
+			cmd.Env = append(os.Environ(), "XERR_TEST_FATAL="+test.mode)
+			cmd.Stderr = &stderr
+
+			// act
+			err := cmd.Run()
+
+			// assert
+			exitCode := 0
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) {
+				exitCode = exitErr.ExitCode()
+			}
+			assertEqual(t, test.expectedExitCode, exitCode)
+			if test.mode == "verbose" {
+				if !assertTrue(t, strings.HasPrefix(stderr.String(), test.expectedStderr)) {
+					t.Log("stderr", stderr.String())
+				}
+			} else {
+				assertEqual(t, test.expectedStderr, stderr.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr
+
+import (
+	"bufio"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// explainContextLines is the number of source lines shown by [Explain]
+// before and after each frame's line.
+const explainContextLines = 2
+
+// Explain returns a verbose, human readable report of an error:
+// its message followed by its stack trace frames, each frame having the
+// surrounding source code lines (±2) included, when the source files are available.
+// Configured [SkipFrame], [FrameFnNameProcessor] and [FrameFileProcessor] are honored.
+// It is meant for CLI tools and local development, as reading source files is slow.
+// Returns empty string for a nil error.
+//
+// Example of output:
+//
+//	something went bad
+//	main.main
+//		/Users/bogdan/work/go/xerr/_example/main.go:15
+//		    13 | func main() {
+//		    14 | 	// ...
+//		>   15 | 	err := xerr.New("something went bad")
+//		    16 | 	fmt.Printf("%+v", err)
+//		    17 | }
+func Explain(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	var (
+		frames   = Frames(err)
+		files    = make([]string, len(frames))
+		maxLines = make(map[string]int) // the number of lines needed from each file.
+	)
+	for idx, f := range frames {
+		_, files[idx], _ = getFrame(f.PC - 1) // the file path, as it is, with no processor applied.
+		maxLines[files[idx]] = max(maxLines[files[idx]], f.Line+explainContextLines)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(err.Error())
+	sources := make(map[string][]string, len(maxLines))
+	for idx, f := range frames {
+		writeFrame(&sb, f.Function, f.File, f.Line)
+		lines, found := sources[files[idx]]
+		if !found {
+			lines = readSourceLines(files[idx], maxLines[files[idx]])
+			sources[files[idx]] = lines
+		}
+		writeSourceSnippet(&sb, lines, f.Line)
+	}
+
+	return sb.String()
+}
+
+// readSourceLines returns the first maxLines lines of given source file.
+// Returns nil if the file cannot be read.
+func readSourceLines(file string, maxLines int) []string {
+	fh, err := os.Open(file)
+	if err != nil {
+		return nil
+	}
+	defer fh.Close()
+
+	lines := make([]string, 0, maxLines)
+	scanner := bufio.NewScanner(fh)
+	for len(lines) < maxLines && scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+
+	return lines
+}
+
+// writeSourceSnippet writes the source lines surrounding given line (1 based index),
+// the line itself being marked with ">".
+func writeSourceSnippet(sb *strings.Builder, lines []string, line int) {
+	if line < 1 || line > len(lines) {
+		return
+	}
+	from := max(line-explainContextLines, 1)
+	to := min(line+explainContextLines, len(lines))
+	for idx := from; idx <= to; idx++ {
+		sb.WriteString("\n\t")
+		if idx == line {
+			sb.WriteString(">")
+		} else {
+			sb.WriteString(" ")
+		}
+		lineNo := strconv.Itoa(idx)
+		sb.WriteString(strings.Repeat(" ", max(5-len(lineNo), 1)))
+		sb.WriteString(lineNo)
+		sb.WriteString(" |")
+		if lines[idx-1] != "" {
+			sb.WriteString(" ")
+			sb.WriteString(lines[idx-1])
+		}
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr_test
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/actforgood/xerr"
+)
+
+// explainTestErr returns an error created on a line preceding its caller's line.
+func explainTestErr() error {
+	return xerr.New("something went bad") // the helper's marked line.
+}
+
+func TestExplain(t *testing.T) {
+	t.Parallel()
+
+	t.Run("error with stack", testExplainErrorWithStack)
+	t.Run("frames from the same file", testExplainFramesFromSameFile)
+	t.Run("error without stack", testExplainErrorWithoutStack)
+	t.Run("nil error", testExplainNilError)
+}
+
+func testExplainErrorWithStack(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	err := xerr.NewOpt("something went bad", xerr.WithFrameFileProcessor(xerr.BaseNameOnly)) // the marked line.
+	regex := `^something went bad\n` +
+		`github\.com/actforgood/xerr_test\.testExplainErrorWithStack\n` +
+		`\texplain_test\.go:(\d+)\n` +
+		`\t    \d+ \|\n` +
+		`\t    \d+ \| \t// arrange\n` +
+		`\t>   \d+ \| \terr := xerr\.NewOpt\("something went bad".+// the marked line\.\n` +
+		`\t    \d+ \| \tregex := .+\n` +
+		`\t    \d+ \| \t\t.+\n` +
+		`testing\.tRunner\n`
+
+	// act
+	result := xerr.Explain(err)
+
+	// assert
+	matched, _ := regexp.MatchString(regex, result)
+	if !assertTrue(t, matched) {
+		t.Log("regex", regex, "result", result)
+	}
+}
+
+func testExplainFramesFromSameFile(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	err := explainTestErr() // the caller's marked line.
+
+	// act
+	result := xerr.Explain(err)
+
+	// assert
+	helperMatched, _ := regexp.MatchString(`\n\t>\s+\d+ \| \treturn xerr\.New.+// the helper's marked line\.\n`, result)
+	callerMatched, _ := regexp.MatchString(`\n\t>\s+\d+ \| \terr := explainTestErr\(\) // the caller's marked line\.\n`, result)
+	if !assertTrue(t, helperMatched) || !assertTrue(t, callerMatched) {
+		t.Log("result", result)
+	}
+}
+
+func testExplainErrorWithoutStack(t *testing.T) {
+	t.Parallel()
+
+	// act
+	result := xerr.Explain(errors.New("some error"))
+
+	// assert
+	assertEqual(t, "some error", result)
+}
+
+func testExplainNilError(t *testing.T) {
+	t.Parallel()
+
+	// act
+	result := xerr.Explain(nil)
+
+	// assert
+	assertEqual(t, "", result)
+	assertFalse(t, strings.Contains(result, "\n"))
+}
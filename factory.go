@@ -0,0 +1,92 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr
+
+import "fmt"
+
+// Factory creates errors with its own stack configuration and default fields,
+// independent of the global configuration (see [SetSkipFrame], [SetFrameFnNameProcessor]).
+// It is meant to be used by libraries, which should not alter process-wide state
+// shared with the host application.
+// A Factory is safe for concurrent use.
+//
+// Example:
+//
+//	var errs = xerr.NewFactory(
+//		xerr.WithSkipFrame(xerr.SkipFrameGoRootSrcPath(xerr.AllowFrame)),
+//		xerr.WithDepth(16),
+//	).With(xerr.F("component", "mylib"))
+//
+//	func Do() error {
+//		return errs.New("something went bad")
+//	}
+type Factory struct {
+	opts   options
+	fields []Field
+}
+
+// NewFactory instantiates a new Factory, creating errors configured with given options.
+func NewFactory(opts ...Option) *Factory {
+	return &Factory{opts: newOptions(opts)}
+}
+
+// With returns a new Factory, with the same configuration,
+// whose errors have additionally the given default fields attached.
+func (f *Factory) With(fields ...Field) *Factory {
+	allFields := make([]Field, 0, len(f.fields)+len(fields))
+	allFields = append(allFields, f.fields...)
+	allFields = append(allFields, fields...)
+
+	return &Factory{
+		opts:   f.opts,
+		fields: allFields,
+	}
+}
+
+// New returns an error with the supplied message.
+// It behaves like [New], with Factory's configuration.
+func (f *Factory) New(msg string) error {
+	return f.opts.created(f.withFields(newStackError(nil, msg, f.opts)))
+}
+
+// Errorf formats according to a format specifier and returns the string
+// as a value that satisfies error.
+// It behaves like [Errorf], with Factory's configuration.
+func (f *Factory) Errorf(format string, args ...any) error {
+	return f.opts.created(f.withFields(newStackError(nil, fmt.Sprintf(format, args...), f.opts)))
+}
+
+// Wrap returns an error annotating err with the supplied message.
+// It behaves like [Wrap], with Factory's configuration.
+// If err is nil, Wrap returns nil.
+func (f *Factory) Wrap(err error, msg string) error {
+	if err == nil {
+		return nil
+	}
+
+	return f.opts.created(f.withFields(newStackError(err, msg, f.opts)))
+}
+
+// Wrapf returns an error annotating err with the message formatted according to a
+// format specifier.
+// It behaves like [Wrapf], with Factory's configuration.
+// If err is nil, Wrapf returns nil.
+func (f *Factory) Wrapf(err error, format string, args ...any) error {
+	if err == nil {
+		return nil
+	}
+
+	return f.opts.created(f.withFields(newStackError(err, fmt.Sprintf(format, args...), f.opts)))
+}
+
+// withFields attaches Factory's default fields to given error.
+func (f *Factory) withFields(err error) error {
+	if len(f.fields) == 0 {
+		return err
+	}
+
+	return WithFields(err, f.fields...)
+}
@@ -0,0 +1,110 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/actforgood/xerr"
+)
+
+func TestFactory(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		subject = xerr.NewFactory(
+			xerr.WithDepth(2),
+			xerr.WithFrameFnNameProcessor(xerr.OnlyFunctionName),
+		).With(xerr.F("component", "mylib"))
+		origErr = errors.New("some error")
+		tests   = [...]struct {
+			name           string
+			createErr      func() error
+			expectedMsg    string
+			expectedFnName string
+		}{
+			{
+				name:           "New",
+				createErr:      func() error { return subject.New("something went bad") },
+				expectedMsg:    "something went bad",
+				expectedFnName: "TestFactory.func1",
+			},
+			{
+				name:           "Errorf",
+				createErr:      func() error { return subject.Errorf("something went bad %d", 100) },
+				expectedMsg:    "something went bad 100",
+				expectedFnName: "TestFactory.func2",
+			},
+			{
+				name:           "Wrap",
+				createErr:      func() error { return subject.Wrap(origErr, "wrap") },
+				expectedMsg:    "wrap: some error",
+				expectedFnName: "TestFactory.func3",
+			},
+			{
+				name:           "Wrapf",
+				createErr:      func() error { return subject.Wrapf(origErr, "wrap %d", 100) },
+				expectedMsg:    "wrap 100: some error",
+				expectedFnName: "TestFactory.func4",
+			},
+		}
+	)
+
+	for _, testData := range tests {
+		test := testData // capture range variable
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			// act
+			result := test.createErr()
+
+			// assert
+			assertEqual(t, test.expectedMsg, result.Error())
+			assertEqual(t, map[string]any{"component": "mylib"}, xerr.Fields(result))
+			frames := xerr.Frames(result)
+			if assertEqual(t, 2, len(frames)) {
+				assertEqual(t, test.expectedFnName, frames[0].Function)
+			}
+			errMsgWithStack := fmt.Sprintf("%+v", result)
+			assertTrue(t, strings.HasPrefix(errMsgWithStack, test.expectedMsg+"\n"+test.expectedFnName+"\n"))
+		})
+	}
+}
+
+func TestFactory_nilError(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	subject := xerr.NewFactory()
+
+	// act & assert
+	assertNil(t, subject.Wrap(nil, "wrap"))
+	assertNil(t, subject.Wrapf(nil, "wrap %d", 100))
+}
+
+func TestFactory_With(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	parent := xerr.NewFactory(xerr.WithNoStack()).With(xerr.F("component", "mylib"))
+
+	// act
+	subject := parent.With(xerr.F("operation", "read"))
+
+	// assert
+	childErr := subject.New("child error")
+	assertEqual(t, map[string]any{"component": "mylib", "operation": "read"}, xerr.Fields(childErr))
+	assertNil(t, xerr.Frames(childErr))
+	parentErr := parent.New("parent error")
+	assertEqual(t, map[string]any{"component": "mylib"}, xerr.Fields(parentErr))
+	noFieldsErr := xerr.NewFactory().New("some error")
+	assertNil(t, xerr.Fields(noFieldsErr))
+	assertTrue(t, len(xerr.Frames(noFieldsErr)) > 0)
+}
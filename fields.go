@@ -0,0 +1,130 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr
+
+import "sort"
+
+// Field is a key/value pair of structured metadata attached to an error.
+type Field struct {
+	Key   string
+	Value any
+}
+
+// F returns a [Field] with given key and value.
+func F(key string, value any) Field {
+	return Field{Key: key, Value: value}
+}
+
+// fieldsKey is the annotation key under which an error's fields are stored.
+type fieldsKey struct{}
+
+// WithFields returns an error annotating err with given key/value fields,
+// which can be later on retrieved with [FieldsOf] / [Fields],
+// for example by a structured logger.
+// If err is nil, WithFields returns nil.
+//
+// Example:
+//
+//	err = xerr.WithFields(err, xerr.F("user_id", 42), xerr.F("order", orderID))
+func WithFields(err error, fields ...Field) error {
+	if err == nil {
+		return nil
+	}
+	if len(fields) == 0 {
+		return err
+	}
+	fieldsCopy := make([]Field, len(fields))
+	copy(fieldsCopy, fields)
+
+	return withValue(err, fieldsKey{}, fieldsCopy)
+}
+
+// withFields attaches the given map fields to err, ordered by key.
+// If there are no fields, err is returned as it is.
+func withFields(err error, fields map[string]any) error {
+	if len(fields) == 0 {
+		return err
+	}
+
+	fieldsList := make([]Field, 0, len(fields))
+	for key, val := range fields {
+		fieldsList = append(fieldsList, F(key, val))
+	}
+	sort.Slice(fieldsList, func(i, j int) bool {
+		return fieldsList[i].Key < fieldsList[j].Key
+	})
+
+	return withValue(err, fieldsKey{}, fieldsList)
+}
+
+// FieldsOf returns the key/value fields attached to an error,
+// collected across its whole chain, from the outermost error inwards,
+// in the order they were given at each level.
+// If the same key was attached at multiple levels, the outermost value wins.
+// Returns nil if the error does not have any fields.
+func FieldsOf(err error) []Field {
+	var (
+		fields []Field
+		keys   map[string]struct{}
+	)
+	walkChain(err, func(e error) bool {
+		for _, field := range fieldsOf(e) {
+			if _, exists := keys[field.Key]; exists {
+				continue
+			}
+			if keys == nil {
+				keys = make(map[string]struct{})
+			}
+			keys[field.Key] = struct{}{}
+			fields = append(fields, field)
+		}
+
+		return true
+	})
+
+	return fields
+}
+
+// Fields returns the key/value fields attached to an error,
+// collected across its whole chain, as a map.
+// If the same key was attached at multiple levels, the outermost value wins.
+// Returns nil if the error does not have any fields.
+func Fields(err error) map[string]any {
+	var fields map[string]any
+	walkChain(err, func(e error) bool {
+		fields = collectFields(fields, e)
+
+		return true
+	})
+
+	return fields
+}
+
+// collectFields adds to dst the fields stored by err, if it is a fields annotation.
+// Keys already existing in dst are not overwritten.
+// Returns dst, eventually initialized.
+func collectFields(dst map[string]any, err error) map[string]any {
+	for _, field := range fieldsOf(err) {
+		if dst == nil {
+			dst = make(map[string]any)
+		}
+		if _, exists := dst[field.Key]; !exists {
+			dst[field.Key] = field.Value
+		}
+	}
+
+	return dst
+}
+
+// fieldsOf returns the fields stored by err (not its chain),
+// if it is a fields annotation.
+func fieldsOf(err error) []Field {
+	if vErr, ok := err.(*valueError); ok && vErr.key == (fieldsKey{}) {
+		return vErr.val.([]Field)
+	}
+
+	return nil
+}
@@ -0,0 +1,182 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/actforgood/xerr"
+)
+
+func TestFields(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		subject  = xerr.Fields
+		innerCtx = context.WithValue(
+			context.WithValue(context.Background(), ctxTestKey("request_id"), "inner-req"),
+			ctxTestKey("tenant"), "acme",
+		)
+		outerCtx = context.WithValue(context.Background(), ctxTestKey("request_id"), "outer-req")
+		innerErr = xerr.NewCtx(innerCtx, "inner")
+		tests    = [...]struct {
+			name     string
+			inputErr error
+			expected map[string]any
+		}{
+			{
+				name:     "nil error",
+				inputErr: nil,
+				expected: nil,
+			},
+			{
+				name:     "standard error",
+				inputErr: errors.New("some standard error"),
+				expected: nil,
+			},
+			{
+				name:     "error with fields",
+				inputErr: innerErr,
+				expected: map[string]any{"request_id": "inner-req", "tenant": "acme"},
+			},
+			{
+				name:     "wrapped error with fields, outermost value wins",
+				inputErr: xerr.WrapCtx(outerCtx, innerErr, "outer"),
+				expected: map[string]any{"request_id": "outer-req", "tenant": "acme"},
+			},
+			{
+				name:     "error with fields wrapped by a standard error",
+				inputErr: fmt.Errorf("std wrap: %w", innerErr),
+				expected: map[string]any{"request_id": "inner-req", "tenant": "acme"},
+			},
+			{
+				name: "error with fields in a MultiError",
+				inputErr: xerr.NewMultiError().Add(
+					errors.New("some standard error"),
+					xerr.NewCtx(outerCtx, "outer"),
+				),
+				expected: map[string]any{"request_id": "outer-req"},
+			},
+		}
+	)
+
+	for _, testData := range tests {
+		test := testData // capture range variable
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			// act
+			result := subject(test.inputErr)
+
+			// assert
+			assertEqual(t, test.expected, result)
+		})
+	}
+}
+
+func TestWithFields(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		subject = xerr.WithFields
+		origErr = xerr.New("some error with stack")
+		fields  = []xerr.Field{xerr.F("user_id", 42), xerr.F("order", "ord-1")}
+	)
+
+	// act
+	resultErr := subject(origErr, fields...)
+	fields[0] = xerr.F("modified", true) // see fields were copied
+
+	// assert
+	if assertNotNil(t, resultErr) {
+		assertEqual(t, origErr.Error(), resultErr.Error())
+		assertEqual(t, fmt.Sprintf("%+v", origErr), fmt.Sprintf("%+v", resultErr))
+		assertTrue(t, errors.Is(resultErr, origErr))
+		assertEqual(
+			t,
+			[]xerr.Field{{Key: "user_id", Value: 42}, {Key: "order", Value: "ord-1"}},
+			xerr.FieldsOf(resultErr),
+		)
+	}
+	assertNil(t, subject(nil, xerr.F("user_id", 42)))
+	assertEqual(t, origErr, subject(origErr))
+}
+
+func TestFieldsOf(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		subject = xerr.FieldsOf
+		ctx     = context.WithValue(
+			context.WithValue(context.Background(), ctxTestKey("tenant"), "acme"),
+			ctxTestKey("request_id"), "req-1",
+		)
+		innerErr = xerr.WithFields(errors.New("inner"), xerr.F("user_id", 1), xerr.F("attempt", 1))
+		tests    = [...]struct {
+			name     string
+			inputErr error
+			expected []xerr.Field
+		}{
+			{
+				name:     "nil error",
+				inputErr: nil,
+				expected: nil,
+			},
+			{
+				name:     "standard error",
+				inputErr: errors.New("some standard error"),
+				expected: nil,
+			},
+			{
+				name:     "fields in given order",
+				inputErr: innerErr,
+				expected: []xerr.Field{xerr.F("user_id", 1), xerr.F("attempt", 1)},
+			},
+			{
+				name: "fields across chain, outermost first and winning",
+				inputErr: xerr.WithFields(
+					xerr.Wrap(innerErr, "wrap"),
+					xerr.F("attempt", 2),
+					xerr.F("order", "ord-1"),
+				),
+				expected: []xerr.Field{
+					xerr.F("attempt", 2),
+					xerr.F("order", "ord-1"),
+					xerr.F("user_id", 1),
+				},
+			},
+			{
+				name:     "context fields ordered by key",
+				inputErr: xerr.WrapCtx(ctx, innerErr, "wrap"),
+				expected: []xerr.Field{
+					xerr.F("request_id", "req-1"),
+					xerr.F("tenant", "acme"),
+					xerr.F("user_id", 1),
+					xerr.F("attempt", 1),
+				},
+			},
+		}
+	)
+
+	for _, testData := range tests {
+		test := testData // capture range variable
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			// act
+			result := subject(test.inputErr)
+
+			// assert
+			assertEqual(t, test.expected, result)
+		})
+	}
+}
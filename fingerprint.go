@@ -0,0 +1,182 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr
+
+import (
+	"hash/fnv"
+	"regexp"
+	"strconv"
+)
+
+// fingerprintAppFrames is the number of application frames taken into account
+// by [FingerprintTopAppFrames].
+const fingerprintAppFrames = 3
+
+var (
+	fingerprinter Fingerprinter = FingerprintTopAppFrames
+
+	// isStdFrame decides whether a frame belongs to the Go standard library / runtime.
+	isStdFrame = SkipFrameGoRootSrcPath(AllowFrame)
+
+	// messageVariablesRegex matches the variable parts of a message:
+	// quoted strings, UUIDs, hexadecimal and decimal numbers.
+	messageVariablesRegex = regexp.MustCompile(
+		`"[^"]*"|'[^']*'|` +
+			`\b[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}\b|` +
+			`\b0[xX][0-9a-fA-F]+\b|` +
+			`\d+(\.\d+)?`,
+	)
+)
+
+// Fingerprinter is an alias for a function that computes an error's fingerprint,
+// an identifier which groups together similar errors.
+// It receives the error and the frames of its stack trace (empty if
+// the error does not have a stack trace), as they are, with no
+// [SkipFrame] / [FrameFnNameProcessor] / [FrameFileProcessor] applied.
+type Fingerprinter func(err error, frames []Frame) string
+
+// SetFingerprinter configures the function this package uses
+// in order to compute an error's fingerprint.
+// By default, [FingerprintTopAppFrames] is used. Passing nil restores the default.
+// You will call it usually somewhere in the bootstrap process of your
+// application. For example:
+//
+//	// myapp/bootstrap.go
+//	func init() {
+//		xerr.SetFingerprinter(xerr.FingerprintMessageTemplate)
+//	}
+func SetFingerprinter(fn Fingerprinter) {
+	if fn == nil {
+		fn = FingerprintTopAppFrames
+	}
+	fingerprinter = fn
+}
+
+// Fingerprint returns an identifier which groups together similar errors,
+// computed with the configured [Fingerprinter].
+// Returns empty string for a nil error.
+func Fingerprint(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	return fingerprinter(err, rawStackFrames(err))
+}
+
+// rawStackFrames returns the stack trace frames of an error, as they are,
+// the ones of the outermost error with stack trace found in its chain,
+// falling back on the ones of the outermost foreign [StackTracer].
+func rawStackFrames(err error) []Frame {
+	if stackPCs := stackOf(err); len(stackPCs) > 0 {
+		return rawFrames(stackPCs)
+	}
+	if st := stackTracerOf(err); st != nil {
+		return st.StackFrames()
+	}
+
+	return nil
+}
+
+// FingerprintTopFrame is a [Fingerprinter] which groups errors
+// by the top frame of their stack trace (the place they were created at).
+// For an error without stack trace, the message is used.
+func FingerprintTopFrame(err error, frames []Frame) string {
+	if len(frames) == 0 {
+		return hashStrings(err.Error())
+	}
+
+	return hashFrames(frames[:1])
+}
+
+// FingerprintTopAppFrames is a [Fingerprinter] which groups errors
+// by the top 3 application frames of their stack trace
+// (frames outside Go standard library).
+// For an error without such frames, the message is used.
+func FingerprintTopAppFrames(err error, frames []Frame) string {
+	appFrames := make([]Frame, 0, fingerprintAppFrames)
+	for _, f := range frames {
+		if isStdFrame(f.Function, f.File) {
+			continue
+		}
+		appFrames = append(appFrames, f)
+		if len(appFrames) == fingerprintAppFrames {
+			break
+		}
+	}
+	if len(appFrames) == 0 {
+		return hashStrings(err.Error())
+	}
+
+	return hashFrames(appFrames)
+}
+
+// FingerprintMessageTemplate is a [Fingerprinter] which groups errors
+// by their message's template, the message with its variable parts
+// (quoted strings, UUIDs, numbers) stripped.
+// Example: `user 42 not found` and `user 43 not found` are grouped together.
+func FingerprintMessageTemplate(err error, _ []Frame) string {
+	return hashStrings(messageVariablesRegex.ReplaceAllString(err.Error(), "?"))
+}
+
+// FingerprintCode is a [Fingerprinter] which groups errors by their code
+// (see [CodeOf]). For an error without code, [FingerprintTopAppFrames] is used.
+func FingerprintCode(err error, frames []Frame) string {
+	if code := CodeOf(err); code != "" {
+		return hashStrings("code:", string(code))
+	}
+
+	return FingerprintTopAppFrames(err, frames)
+}
+
+// hashFrames returns the hexadecimal hash of given frames.
+func hashFrames(frames []Frame) string {
+	h := fnv.New64a()
+	for _, f := range frames {
+		_, _ = h.Write([]byte(f.Function))
+		_, _ = h.Write([]byte(f.File))
+		_, _ = h.Write([]byte(strconv.FormatInt(int64(f.Line), 10)))
+	}
+
+	return strconv.FormatUint(h.Sum64(), 16)
+}
+
+// hashStrings returns the hexadecimal hash of given strings.
+func hashStrings(values ...string) string {
+	h := fnv.New64a()
+	for _, value := range values {
+		_, _ = h.Write([]byte(value))
+	}
+
+	return strconv.FormatUint(h.Sum64(), 16)
+}
+
+// stackOf returns the stack trace of the outermost stack error
+// found in err's chain, if any.
+func stackOf(err error) []uintptr {
+	if sErr := stackErrorOf(err); sErr != nil {
+		return sErr.stackPCs
+	}
+
+	return nil
+}
+
+// stackErrorOf returns the outermost stack error having a stack trace
+// found in err's chain, if any.
+// The errors stored in a [MultiError] are not looked into, see [walkStackChain].
+func stackErrorOf(err error) *stackError {
+	var result *stackError
+	walkStackChain(err, func(e error) bool {
+		if sErr, ok := e.(*stackError); ok && len(sErr.stackPCs) > 0 {
+			result = sErr
+
+			return false
+		}
+
+		return true
+	})
+
+	return result
+}
@@ -0,0 +1,183 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr_test
+
+import (
+	"errors"
+	"os"
+	"runtime"
+	"testing"
+
+	"github.com/actforgood/xerr"
+)
+
+func TestFingerprint(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	samePlaceErrs := make([]error, 2)
+	for idx, msg := range []string{"foo", "bar"} {
+		samePlaceErrs[idx] = xerr.New(msg)
+	}
+	var (
+		subject     = xerr.Fingerprint
+		samePlace1  = samePlaceErrs[0]
+		samePlace2  = samePlaceErrs[1]
+		otherPlace  = xerr.New("foo")
+		stdErr1     = errors.New("some standard error")
+		stdErr2     = errors.New("some standard error")
+		otherStdErr = errors.New("some other standard error")
+	)
+
+	// act & assert
+	assertEqual(t, "", subject(nil))
+	assertTrue(t, subject(samePlace1) != "")
+	assertEqual(t, subject(samePlace1), subject(samePlace2))
+	assertTrue(t, subject(samePlace1) != subject(otherPlace))
+	assertEqual(t, subject(stdErr1), subject(stdErr2))
+	assertTrue(t, subject(stdErr1) != subject(otherStdErr))
+	assertEqual(t, subject(samePlace1), subject(xerr.WithSeverity(samePlace1, xerr.SeverityWarn)))
+}
+
+func TestFingerprint_multiError(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		subject = xerr.Fingerprint
+		mErr1   = xerr.NewMultiError().Add(xerr.New("foo"), errors.New("bar"))
+		mErr2   = xerr.NewMultiError().Add(xerr.New("foo"), errors.New("bar"))
+	)
+
+	// act & assert - stored errors' stack traces are not the MultiError's one.
+	assertNil(t, xerr.Frames(mErr1))
+	assertEqual(t, subject(mErr1), subject(mErr2))
+	assertEqual(t, subject(errors.New(mErr1.Error())), subject(mErr1))
+	wrapped := xerr.Wrap(mErr1, "wrap")
+	assertTrue(t, len(xerr.Frames(wrapped)) > 0)
+	assertTrue(t, subject(wrapped) != subject(mErr1))
+}
+
+func TestFingerprintTopFrame(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	subject := xerr.FingerprintTopFrame
+	frames := []xerr.Frame{
+		{Function: "pkg.Foo", File: "/app/foo.go", Line: 10},
+		{Function: "pkg.Bar", File: "/app/bar.go", Line: 20},
+	}
+	otherCallerFrames := []xerr.Frame{
+		{Function: "pkg.Foo", File: "/app/foo.go", Line: 10},
+		{Function: "pkg.Baz", File: "/app/baz.go", Line: 30},
+	}
+	err := errors.New("some error")
+
+	// act & assert
+	assertEqual(t, subject(err, frames), subject(err, otherCallerFrames))
+	assertTrue(t, subject(err, frames) != subject(err, frames[1:]))
+	assertEqual(t, subject(err, nil), subject(errors.New("some error"), nil))
+	assertTrue(t, subject(err, nil) != subject(errors.New("some other error"), nil))
+}
+
+func TestFingerprintTopAppFrames(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		subject    = xerr.FingerprintTopAppFrames
+		goSrc      = runtime.GOROOT() + string(os.PathSeparator) + "src" + string(os.PathSeparator)
+		err        = errors.New("some error")
+		withStdlib = []xerr.Frame{
+			{Function: "io.ReadAll", File: goSrc + "io/io.go", Line: 1},
+			{Function: "pkg.Foo", File: "/app/foo.go", Line: 10},
+			{Function: "pkg.Bar", File: "/app/bar.go", Line: 20},
+			{Function: "pkg.Baz", File: "/app/baz.go", Line: 30},
+			{Function: "pkg.Qux", File: "/app/qux.go", Line: 40},
+		}
+		withoutStdlib = []xerr.Frame{
+			{Function: "pkg.Foo", File: "/app/foo.go", Line: 10},
+			{Function: "pkg.Bar", File: "/app/bar.go", Line: 20},
+			{Function: "pkg.Baz", File: "/app/baz.go", Line: 30},
+			{Function: "pkg.Other", File: "/app/other.go", Line: 50},
+		}
+		onlyStdlib = []xerr.Frame{
+			{Function: "io.ReadAll", File: goSrc + "io/io.go", Line: 1},
+		}
+	)
+
+	// act & assert
+	assertEqual(t, subject(err, withStdlib), subject(err, withoutStdlib))
+	assertTrue(t, subject(err, withStdlib) != subject(err, withoutStdlib[1:]))
+	assertEqual(t, subject(err, onlyStdlib), subject(errors.New("some error"), nil))
+}
+
+func TestFingerprintMessageTemplate(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	subject := xerr.FingerprintMessageTemplate
+
+	// act & assert
+	assertEqual(
+		t,
+		subject(errors.New(`user 42 not found in "eu-west"`), nil),
+		subject(errors.New(`user 1337 not found in "us-east"`), nil),
+	)
+	assertEqual(
+		t,
+		subject(errors.New("order 123e4567-e89b-12d3-a456-426614174000 at 0xc000123 failed"), nil),
+		subject(errors.New("order 00000000-0000-0000-0000-000000000000 at 0xc000456 failed"), nil),
+	)
+	assertTrue(
+		t,
+		subject(errors.New("user 42 not found"), nil) != subject(errors.New("order 42 not found"), nil),
+	)
+}
+
+func TestSetFingerprinter(t *testing.T) {
+	// arrange
+	var (
+		callsCnt int
+		err      = xerr.New("some error")
+	)
+	xerr.SetFingerprinter(func(e error, frames []xerr.Frame) string {
+		callsCnt++
+		assertEqual(t, err, e)
+		if assertTrue(t, len(frames) > 0) {
+			assertEqual(t, "github.com/actforgood/xerr_test.TestSetFingerprinter", frames[0].Function)
+		}
+
+		return "custom"
+	})
+	defer xerr.SetFingerprinter(nil) // restore original global state
+
+	// act
+	result := xerr.Fingerprint(err)
+
+	// assert
+	assertEqual(t, "custom", result)
+	assertEqual(t, 1, callsCnt)
+}
+
+func TestFingerprintCode(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		subject = xerr.FingerprintCode
+		frames  = []xerr.Frame{{Function: "pkg.Foo", File: "/app/foo.go", Line: 10}}
+		err1    = xerr.WithCode(errors.New("user 1 not found"), "user_not_found")
+		err2    = xerr.WithCode(errors.New("user 2 not found"), "user_not_found")
+		err3    = xerr.WithCode(errors.New("user 1 not found"), "other_code")
+		noCode  = errors.New("user 1 not found")
+	)
+
+	// act & assert
+	assertEqual(t, subject(err1, frames), subject(err2, nil))
+	assertTrue(t, subject(err1, frames) != subject(err3, frames))
+	assertEqual(t, xerr.FingerprintTopAppFrames(noCode, frames), subject(noCode, frames))
+}
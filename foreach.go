@@ -0,0 +1,64 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// ForEach calls fn for each of the items, with at most concurrency calls running
+// in parallel (a non-positive concurrency means no limit), and waits for them to finish.
+// The errors returned by fn are collected into a [MultiError], in items' order,
+// each of them labelled with its item's index, like "items[3]" (see [MultiError.AddNamed]).
+// Once ctx is done, no more items are processed, and ctx's error (see [context.Cause])
+// is collected, too.
+// It returns the collected errors, as [MultiError.ErrOrNil] does.
+//
+// Example:
+//
+//	err := xerr.ForEach(ctx, files, 8, func(file string) error {
+//		return process(ctx, file)
+//	})
+func ForEach[T any](ctx context.Context, items []T, concurrency int, fn func(T) error) error {
+	if concurrency <= 0 || concurrency > len(items) {
+		concurrency = len(items)
+	}
+
+	var (
+		errs     = make([]error, len(items))
+		next     atomic.Int64
+		canceled atomic.Bool
+		wg       sync.WaitGroup
+	)
+	wg.Add(concurrency)
+	for i := 0; i < concurrency; i++ {
+		go func() {
+			defer wg.Done()
+			for idx := int(next.Add(1) - 1); idx < len(items); idx = int(next.Add(1) - 1) {
+				if ctx.Err() != nil {
+					canceled.Store(true)
+
+					return
+				}
+				errs[idx] = fn(items[idx])
+			}
+		}()
+	}
+	wg.Wait()
+
+	var mErr *MultiError
+	for idx, err := range errs {
+		mErr = mErr.AddNamed("items["+strconv.Itoa(idx)+"]", err)
+	}
+	if canceled.Load() {
+		mErr = mErr.Add(context.Cause(ctx))
+	}
+
+	return mErr.ErrOrNil()
+}
@@ -0,0 +1,126 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/actforgood/xerr"
+)
+
+func TestForEach(t *testing.T) {
+	t.Parallel()
+
+	t.Run("errors are labelled by index", testForEachErrorsLabelled)
+	t.Run("concurrency is bounded", testForEachConcurrencyBounded)
+	t.Run("no errors", testForEachNoErrors)
+	t.Run("context is done", testForEachContextDone)
+}
+
+func testForEachErrorsLabelled(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	items := []int{1, 2, 3, 4, 5, 6}
+
+	// act
+	err := xerr.ForEach(context.Background(), items, 2, func(item int) error {
+		if item%2 == 0 {
+			return errors.New("even number " + strconv.Itoa(item))
+		}
+
+		return nil
+	})
+
+	// assert
+	var mErr *xerr.MultiError
+	if assertTrue(t, errors.As(err, &mErr)) {
+		assertEqual(t, "items[1]: even number 2\nitems[3]: even number 4\nitems[5]: even number 6", mErr.Error())
+		assertEqual(t, "items[3]", xerr.NameOf(mErr.Errors()[1]))
+	}
+}
+
+func testForEachConcurrencyBounded(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		items               = make([]int, 50)
+		running, maxRunning atomic.Int32
+	)
+
+	// act
+	err := xerr.ForEach(context.Background(), items, 3, func(int) error {
+		current := running.Add(1)
+		defer running.Add(-1)
+		for {
+			prevMax := maxRunning.Load()
+			if current <= prevMax || maxRunning.CompareAndSwap(prevMax, current) {
+				break
+			}
+		}
+
+		return nil
+	})
+
+	// assert
+	assertNil(t, err)
+	assertTrue(t, maxRunning.Load() <= 3)
+}
+
+func testForEachNoErrors(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var processed atomic.Int32
+
+	// act
+	err := xerr.ForEach(context.Background(), []string{"a", "b", "c"}, 0, func(string) error {
+		processed.Add(1)
+
+		return nil
+	})
+	errNoItems := xerr.ForEach(context.Background(), []string{}, 4, func(string) error {
+		return errors.New("not expected to be called")
+	})
+
+	// assert
+	assertNil(t, err)
+	assertEqual(t, int32(3), processed.Load())
+	assertNil(t, errNoItems)
+}
+
+func testForEachContextDone(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		ctx, cancel = context.WithCancel(context.Background())
+		items       = []int{1, 2, 3, 4}
+		processed   atomic.Int32
+	)
+
+	// act
+	err := xerr.ForEach(ctx, items, 1, func(item int) error {
+		processed.Add(1)
+		if item == 2 {
+			cancel()
+
+			return errors.New("failed")
+		}
+
+		return nil
+	})
+
+	// assert
+	assertEqual(t, int32(2), processed.Load())
+	assertTrue(t, errors.Is(err, context.Canceled))
+	assertEqual(t, "items[1]: failed\ncontext canceled", err.Error())
+}
@@ -0,0 +1,123 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr
+
+import (
+	"strconv"
+	"strings"
+)
+
+// gcpReportedErrorEventType is the type Google Cloud Error Reporting
+// recognizes a structured log entry's payload as an error event by.
+const gcpReportedErrorEventType = "type.googleapis.com/google.devtools.clouderrorreporting.v1beta1.ReportedErrorEvent"
+
+// GCPServiceContext identifies the service an error event is reported for,
+// in Google Cloud Error Reporting.
+type GCPServiceContext struct {
+	// Service is the name of the service, for example "user-service".
+	Service string
+	// Version is the version of the service, for example "v1.4.2".
+	Version string
+}
+
+// GCPStack returns an error rendered in the layout Google Cloud Error Reporting
+// parses Go stack traces from, which is the one [runtime.Stack] produces:
+// the error's message, followed by a goroutine header and the stack trace frames, like:
+//
+//	could not load user: not found
+//
+//	goroutine 1 [running]:
+//	github.com/actforgood/xerr_test.LoadUser(...)
+//		/Users/bogdan/work/go/xerr/user.go:42
+//	main.main(...)
+//		/Users/bogdan/work/go/xerr/main.go:7
+//
+// Configured [SkipFrame], [FrameFnNameProcessor] and [FrameFileProcessor] are honored.
+// If the error has no stack trace, only its message is returned.
+// Returns empty string for a nil error.
+func GCPStack(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	frames := Frames(err)
+	if len(frames) == 0 {
+		return err.Error()
+	}
+
+	var sb strings.Builder
+	sb.WriteString(err.Error())
+	sb.WriteString("\n\ngoroutine 1 [running]:")
+	for _, f := range frames {
+		sb.WriteString("\n")
+		sb.WriteString(f.Function)
+		sb.WriteString("(...)\n\t")
+		sb.WriteString(f.File)
+		sb.WriteString(":")
+		sb.WriteString(strconv.Itoa(f.Line))
+	}
+
+	return sb.String()
+}
+
+// GCPErrorEvent returns the structured payload (the "jsonPayload" of a Cloud Logging entry)
+// Google Cloud Error Reporting recognizes as a ReportedErrorEvent, for an error.
+// The payload has the following keys:
+// "@type" - the ReportedErrorEvent type, "message" - the error rendered by [GCPStack],
+// "severity" - the Cloud Logging severity equivalent to the error's one (see [SeverityOf]),
+// "serviceContext" - given service's name and version, if set,
+// "context" - the report location, the top frame of the error's stack trace, if any.
+// Returns nil for a nil error.
+//
+// Example:
+//
+//	payload := xerr.GCPErrorEvent(err, xerr.GCPServiceContext{Service: "user-service", Version: "v1.4.2"})
+//	_ = json.NewEncoder(os.Stdout).Encode(payload) // on GKE, stdout is collected by Cloud Logging.
+func GCPErrorEvent(err error, svc GCPServiceContext) map[string]any {
+	if err == nil {
+		return nil
+	}
+
+	event := map[string]any{
+		"@type":    gcpReportedErrorEventType,
+		"message":  GCPStack(err),
+		"severity": gcpSeverity(SeverityOf(err)),
+	}
+	if svc.Service != "" {
+		serviceContext := map[string]any{"service": svc.Service}
+		if svc.Version != "" {
+			serviceContext["version"] = svc.Version
+		}
+		event["serviceContext"] = serviceContext
+	}
+	if frames := Frames(err); len(frames) > 0 {
+		event["context"] = map[string]any{
+			"reportLocation": map[string]any{
+				"filePath":     frames[0].File,
+				"lineNumber":   frames[0].Line,
+				"functionName": frames[0].Function,
+			},
+		}
+	}
+
+	return event
+}
+
+// gcpSeverity returns the Cloud Logging severity equivalent to given severity.
+func gcpSeverity(sev Severity) string {
+	switch {
+	case sev <= SeverityDebug:
+		return "DEBUG"
+	case sev == SeverityInfo:
+		return "INFO"
+	case sev == SeverityWarn:
+		return "WARNING"
+	case sev == SeverityError:
+		return "ERROR"
+	default:
+		return "CRITICAL"
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr_test
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/actforgood/xerr"
+)
+
+func TestGCPStack(t *testing.T) {
+	t.Parallel()
+
+	t.Run("error with stack", testGCPStackErrorWithStack)
+	t.Run("error without stack", testGCPStackErrorWithoutStack)
+	t.Run("nil error", testGCPStackNilError)
+}
+
+func testGCPStackErrorWithStack(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	err := xerr.Wrap(xerr.New("not found"), "could not load user")
+	expectedRegex := "^could not load user: not found\n\ngoroutine 1 \\[running\\]:\n" +
+		"github\\.com/actforgood/xerr_test\\.testGCPStackErrorWithStack\\(\\.\\.\\.\\)\n" +
+		"\t.+gcp_test\\.go:\\d+\n"
+
+	// act
+	result := xerr.GCPStack(err)
+
+	// assert
+	matched, _ := regexp.MatchString(expectedRegex, result)
+	if !assertTrue(t, matched) {
+		t.Log("result", result)
+	}
+}
+
+func testGCPStackErrorWithoutStack(t *testing.T) {
+	t.Parallel()
+
+	// act
+	result := xerr.GCPStack(errors.New("std error"))
+
+	// assert
+	assertEqual(t, "std error", result)
+}
+
+func testGCPStackNilError(t *testing.T) {
+	t.Parallel()
+
+	// act
+	result := xerr.GCPStack(nil)
+
+	// assert
+	assertEqual(t, "", result)
+}
+
+func TestGCPErrorEvent(t *testing.T) {
+	t.Parallel()
+
+	t.Run("error with stack", testGCPErrorEventErrorWithStack)
+	t.Run("error without stack", testGCPErrorEventErrorWithoutStack)
+	t.Run("nil error", testGCPErrorEventNilError)
+}
+
+func testGCPErrorEventErrorWithStack(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		err = xerr.WithSeverity(xerr.New("not found"), xerr.SeverityWarn)
+		svc = xerr.GCPServiceContext{Service: "user-service", Version: "v1.4.2"}
+	)
+
+	// act
+	event := xerr.GCPErrorEvent(err, svc)
+
+	// assert
+	assertEqual(t, "type.googleapis.com/google.devtools.clouderrorreporting.v1beta1.ReportedErrorEvent", event["@type"])
+	assertEqual(t, xerr.GCPStack(err), event["message"])
+	assertEqual(t, "WARNING", event["severity"])
+	assertEqual(t, map[string]any{"service": "user-service", "version": "v1.4.2"}, event["serviceContext"])
+	ctx, _ := event["context"].(map[string]any)
+	location, _ := ctx["reportLocation"].(map[string]any)
+	if assertNotNil(t, location) {
+		assertEqual(t, "github.com/actforgood/xerr_test.testGCPErrorEventErrorWithStack", location["functionName"])
+		matched, _ := regexp.MatchString(".+gcp_test\\.go$", location["filePath"].(string))
+		assertTrue(t, matched)
+	}
+}
+
+func testGCPErrorEventErrorWithoutStack(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	err := xerr.WithSeverity(errors.New("std error"), xerr.SeverityFatal)
+
+	// act
+	event := xerr.GCPErrorEvent(err, xerr.GCPServiceContext{})
+
+	// assert
+	assertEqual(t, "std error", event["message"])
+	assertEqual(t, "CRITICAL", event["severity"])
+	_, found := event["serviceContext"]
+	assertFalse(t, found)
+	_, found = event["context"]
+	assertFalse(t, found)
+}
+
+func testGCPErrorEventNilError(t *testing.T) {
+	t.Parallel()
+
+	// act
+	event := xerr.GCPErrorEvent(nil, xerr.GCPServiceContext{Service: "user-service"})
+
+	// assert
+	assertNil(t, event)
+}
@@ -1,3 +1,3 @@
 module github.com/actforgood/xerr
 
-go 1.16
+go 1.21
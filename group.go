@@ -0,0 +1,67 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr
+
+import (
+	"context"
+	"sync"
+)
+
+// Group is a collection of goroutines working on subtasks of a common task.
+// Unlike golang.org/x/sync/errgroup, it collects every goroutine's error
+// into a [MultiError], instead of keeping only the first one.
+// A zero Group is valid and does not cancel on error.
+type Group struct {
+	wg     sync.WaitGroup
+	mu     sync.Mutex
+	mErr   *MultiError
+	cancel context.CancelCauseFunc
+}
+
+// GroupWithContext returns a new Group and an associated context derived from ctx.
+// The derived context is canceled the first time a function passed to [Group.Go]
+// returns a non-nil error, having that error as cause (see [context.Cause]),
+// or the first time [Group.Wait] returns, whichever occurs first.
+// The other goroutines' errors are still collected.
+func GroupWithContext(ctx context.Context) (*Group, context.Context) {
+	ctx, cancel := context.WithCancelCause(ctx)
+
+	return &Group{cancel: cancel}, ctx
+}
+
+// Go calls the given function in a new goroutine.
+// The error it returns, if any, is collected.
+func (g *Group) Go(fn func() error) {
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+
+		if err := fn(); err != nil {
+			g.mu.Lock()
+			if g.mErr == nil && g.cancel != nil {
+				g.cancel(err)
+			}
+			g.mErr = g.mErr.Add(err)
+			g.mu.Unlock()
+		}
+	}()
+}
+
+// Wait blocks until all function calls from the [Group.Go] method have returned,
+// then returns the collected errors, as [MultiError.ErrOrNil] does:
+// nil if there was no error, the error itself if there was only one,
+// a [MultiError] holding all of them, in the order they occurred, otherwise.
+func (g *Group) Wait() error {
+	g.wg.Wait()
+	if g.cancel != nil {
+		g.cancel(nil)
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.mErr.ErrOrNil()
+}
@@ -0,0 +1,134 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/actforgood/xerr"
+)
+
+func TestGroup(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no error", testGroupNoError)
+	t.Run("one error", testGroupOneError)
+	t.Run("all errors are collected", testGroupAllErrorsAreCollected)
+	t.Run("with context", testGroupWithContext)
+}
+
+func testGroupNoError(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var subject xerr.Group
+	calls := make(chan struct{}, 3)
+
+	// act
+	for i := 0; i < 3; i++ {
+		subject.Go(func() error {
+			calls <- struct{}{}
+
+			return nil
+		})
+	}
+	result := subject.Wait()
+
+	// assert
+	assertNil(t, result)
+	assertEqual(t, 3, len(calls))
+}
+
+func testGroupOneError(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var subject xerr.Group
+	expectedErr := errors.New("some error")
+
+	// act
+	subject.Go(func() error { return nil })
+	subject.Go(func() error { return expectedErr })
+	result := subject.Wait()
+
+	// assert
+	assertEqual(t, expectedErr, result)
+}
+
+func testGroupAllErrorsAreCollected(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		subject xerr.Group
+		errs    = []error{errors.New("err 1"), errors.New("err 2"), errors.New("err 3")}
+	)
+
+	// act
+	for _, err := range errs {
+		err := err
+		subject.Go(func() error { return err })
+	}
+	subject.Go(func() error { return nil })
+	result := subject.Wait()
+
+	// assert
+	var mErr *xerr.MultiError
+	if assertTrue(t, errors.As(result, &mErr)) {
+		assertEqual(t, 3, len(mErr.Errors()))
+		for _, err := range errs {
+			assertTrue(t, errors.Is(result, err))
+		}
+	}
+}
+
+func testGroupWithContext(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	subject, ctx := xerr.GroupWithContext(context.Background())
+	firstErr := errors.New("first error")
+	secondErr := errors.New("second error")
+	firstReturned := make(chan struct{})
+
+	// act
+	subject.Go(func() error {
+		defer close(firstReturned)
+
+		return firstErr
+	})
+	subject.Go(func() error {
+		<-firstReturned
+		<-ctx.Done() // canceled by first error.
+
+		return secondErr
+	})
+	result := subject.Wait()
+
+	// assert
+	assertTrue(t, errors.Is(result, firstErr))
+	assertTrue(t, errors.Is(result, secondErr))
+	assertTrue(t, errors.Is(ctx.Err(), context.Canceled))
+	assertEqual(t, firstErr, context.Cause(ctx))
+}
+
+func TestGroupWithContext_canceledOnWait(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	subject, ctx := xerr.GroupWithContext(context.Background())
+	subject.Go(func() error { return nil })
+
+	// act
+	result := subject.Wait()
+
+	// assert
+	assertNil(t, result)
+	assertTrue(t, errors.Is(ctx.Err(), context.Canceled))
+	assertTrue(t, errors.Is(context.Cause(ctx), context.Canceled))
+}
@@ -0,0 +1,62 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	// helpers holds the names of the functions marked as helpers.
+	helpers sync.Map
+	// hasHelpers tells whether any function was marked as helper,
+	// in order to not look up frames at all, if none was.
+	hasHelpers atomic.Bool
+)
+
+// MarkHelper marks the calling function as an error helper function,
+// analogous to [testing.T.Helper].
+// When a stack trace is captured, the helper functions frames at its top
+// are skipped, so that the stack trace starts with the helper's caller.
+// It composes with helpers calling other helpers, unlike a fixed
+// number of skipped callers (see [NewSkip], [WithCallerSkip]).
+// It is safe to be called concurrently and multiple times.
+//
+// Example:
+//
+//	func notFound(entity string, id int) error {
+//		xerr.MarkHelper()
+//
+//		return xerr.Errorf("%s %d not found", entity, id)
+//	}
+func MarkHelper() {
+	pc, _, _, ok := runtime.Caller(1)
+	if !ok {
+		return
+	}
+	fnName, _, _ := getFrame(pc - 1)
+	if _, loaded := helpers.LoadOrStore(fnName, struct{}{}); !loaded {
+		hasHelpers.Store(true)
+	}
+}
+
+// leadingHelperFrames returns the number of helper functions frames
+// at the beginning of given program counters.
+func leadingHelperFrames(stackPCs []uintptr) int {
+	if !hasHelpers.Load() {
+		return 0
+	}
+	for idx, pc := range stackPCs {
+		fnName, _, _ := getFrame(pc - 1)
+		if _, isHelper := helpers.Load(fnName); !isHelper {
+			return idx
+		}
+	}
+
+	return len(stackPCs)
+}
@@ -0,0 +1,115 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/actforgood/xerr"
+)
+
+func TestMarkHelper(t *testing.T) {
+	t.Parallel()
+
+	t.Run("helper", testMarkHelperHelper)
+	t.Run("nested helpers", testMarkHelperNestedHelpers)
+	t.Run("wrap helper", testMarkHelperWrapHelper)
+	t.Run("not a helper", testMarkHelperNotAHelper)
+}
+
+func testMarkHelperHelper(t *testing.T) {
+	t.Parallel()
+
+	// act
+	result := newHelperError("something went bad")
+
+	// assert
+	assertEqual(t, "something went bad", result.Error())
+	frames := xerr.Frames(result)
+	if assertTrue(t, len(frames) > 1) {
+		assertEqual(t, "github.com/actforgood/xerr_test.testMarkHelperHelper", frames[0].Function)
+	}
+}
+
+func testMarkHelperNestedHelpers(t *testing.T) {
+	t.Parallel()
+
+	// act
+	result := newNestedHelperError("something went bad")
+
+	// assert
+	frames := xerr.Frames(result)
+	if assertTrue(t, len(frames) > 1) {
+		assertEqual(t, "github.com/actforgood/xerr_test.testMarkHelperNestedHelpers", frames[0].Function)
+	}
+}
+
+func testMarkHelperWrapHelper(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	origErr := xerr.New("some error")
+	stdErr := errors.New("some standard error")
+
+	// act
+	result := wrapHelperError(origErr, "wrap")
+	resultStd := wrapHelperError(stdErr, "wrap")
+
+	// assert
+	assertEqual(t, "wrap: some error", result.Error())
+	frames := xerr.Frames(result)
+	origFrames := xerr.Frames(origErr)
+	if assertEqual(t, len(origFrames)+1, len(frames)) {
+		assertEqual(t, "github.com/actforgood/xerr_test.testMarkHelperWrapHelper", frames[0].Function)
+		assertEqual(t, origFrames, frames[1:])
+	}
+	framesStd := xerr.Frames(resultStd)
+	if assertTrue(t, len(framesStd) > 1) {
+		assertEqual(t, "github.com/actforgood/xerr_test.testMarkHelperWrapHelper", framesStd[0].Function)
+	}
+}
+
+func testMarkHelperNotAHelper(t *testing.T) {
+	t.Parallel()
+
+	// act
+	result := newNotHelperError("something went bad")
+
+	// assert
+	frames := xerr.Frames(result)
+	if assertTrue(t, len(frames) > 1) {
+		assertEqual(t, "github.com/actforgood/xerr_test.newNotHelperError", frames[0].Function)
+	}
+}
+
+func newHelperError(msg string) error {
+	xerr.MarkHelper()
+
+	return xerr.New(msg)
+}
+
+func newNestedHelperError(msg string) error {
+	xerr.MarkHelper()
+
+	return newHelperError(msg)
+}
+
+func wrapHelperError(err error, msg string) error {
+	xerr.MarkHelper()
+
+	return xerr.Wrap(err, msg)
+}
+
+func newNotHelperError(msg string) error {
+	return xerr.New(msg)
+}
+
+func BenchmarkNew_withHelper(b *testing.B) {
+	for n := 0; n < b.N; n++ {
+		_ = newHelperError("some error with stack trace")
+	}
+}
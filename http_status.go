@@ -0,0 +1,99 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr
+
+// Note: HTTP status codes are written as literals, in order not to
+// make every program using this package depend on "net/http".
+
+// statusClientClosedRequest is the (non-standard) HTTP status code used
+// for requests canceled by the client.
+const statusClientClosedRequest = 499
+
+// httpStatusByCode maps codes named like gRPC codes to HTTP status codes.
+var httpStatusByCode = map[Code]int{
+	"CANCELLED":           statusClientClosedRequest,
+	"UNKNOWN":             500, // Internal Server Error
+	"INVALID_ARGUMENT":    400, // Bad Request
+	"DEADLINE_EXCEEDED":   504, // Gateway Timeout
+	"NOT_FOUND":           404, // Not Found
+	"ALREADY_EXISTS":      409, // Conflict
+	"PERMISSION_DENIED":   403, // Forbidden
+	"RESOURCE_EXHAUSTED":  429, // Too Many Requests
+	"FAILED_PRECONDITION": 400, // Bad Request
+	"ABORTED":             409, // Conflict
+	"OUT_OF_RANGE":        400, // Bad Request
+	"UNIMPLEMENTED":       501, // Not Implemented
+	"INTERNAL":            500, // Internal Server Error
+	"UNAVAILABLE":         503, // Service Unavailable
+	"DATA_LOSS":           500, // Internal Server Error
+	"UNAUTHENTICATED":     401, // Unauthorized
+}
+
+// httpStatusByKind maps kinds to HTTP status codes.
+var httpStatusByKind = map[Kind]int{
+	KindNotFound:        404, // Not Found
+	KindInvalid:         400, // Bad Request
+	KindPermission:      403, // Forbidden
+	KindUnauthenticated: 401, // Unauthorized
+	KindConflict:        409, // Conflict
+	KindUnavailable:     503, // Service Unavailable
+	KindInternal:        500, // Internal Server Error
+}
+
+// httpStatusKey is the annotation key under which an error's HTTP status code is stored.
+type httpStatusKey struct{}
+
+// WithHTTPStatus returns an error annotating err with the HTTP status code
+// which should be responded with, see [HTTPStatus].
+// If err is nil, WithHTTPStatus returns nil.
+func WithHTTPStatus(err error, status int) error {
+	if err == nil {
+		return nil
+	}
+
+	return withValue(err, httpStatusKey{}, status)
+}
+
+// HTTPStatus returns the HTTP status code an error should be responded with.
+// It is, in this order of precedence:
+// the status code attached with [WithHTTPStatus], the outermost one found in the error's chain,
+// 499 (client closed request) / 504 (gateway timeout) for context errors,
+// the status code equivalent to an error code named like a gRPC code (for example "NOT_FOUND", see [CodeOf]),
+// the status code mapped to the error's kind (see [KindOf]),
+// 400 (bad request) for an error holding field violations (see [Validation]),
+// 500 (internal server error) otherwise.
+// It returns 200 (OK) for a nil error.
+//
+// Example:
+//
+//	if err := svc.Do(r.Context()); err != nil {
+//		w.WriteHeader(xerr.HTTPStatus(err))
+//	}
+func HTTPStatus(err error) int {
+	if err == nil {
+		return 200 // OK
+	}
+	if status, found := lookupValue(err, httpStatusKey{}); found {
+		return status.(int)
+	}
+	if IsCanceled(err) {
+		return statusClientClosedRequest
+	}
+	if IsDeadline(err) {
+		return 504 // Gateway Timeout
+	}
+	if status, found := httpStatusByCode[CodeOf(err)]; found {
+		return status
+	}
+	if status, found := httpStatusByKind[KindOf(err)]; found {
+		return status
+	}
+	if len(FieldViolations(err)) > 0 {
+		return 400 // Bad Request
+	}
+
+	return 500 // Internal Server Error
+}
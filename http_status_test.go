@@ -0,0 +1,98 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/actforgood/xerr"
+)
+
+func TestHTTPStatus(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		subject    = xerr.HTTPStatus
+		validation = xerr.NewValidation()
+	)
+	validation.Fail("email", "is required")
+	tests := [...]struct {
+		name     string
+		inputErr error
+		expected int
+	}{
+		{
+			name:     "nil error",
+			inputErr: nil,
+			expected: http.StatusOK,
+		},
+		{
+			name:     "standard error",
+			inputErr: errors.New("some standard error"),
+			expected: http.StatusInternalServerError,
+		},
+		{
+			name:     "attached status",
+			inputErr: fmt.Errorf("wrap: %w", xerr.WithHTTPStatus(errors.New("quota exceeded"), http.StatusPaymentRequired)),
+			expected: http.StatusPaymentRequired,
+		},
+		{
+			name:     "outermost attached status",
+			inputErr: xerr.WithHTTPStatus(xerr.WithHTTPStatus(xerr.NotFound("user"), http.StatusGone), http.StatusTeapot),
+			expected: http.StatusTeapot,
+		},
+		{
+			name:     "attached status takes precedence over context error",
+			inputErr: xerr.WithHTTPStatus(context.DeadlineExceeded, http.StatusServiceUnavailable),
+			expected: http.StatusServiceUnavailable,
+		},
+		{
+			name:     "context canceled",
+			inputErr: xerr.WithCode(xerr.Wrap(context.Canceled, "wrap"), "NOT_FOUND"),
+			expected: 499,
+		},
+		{
+			name:     "context deadline exceeded",
+			inputErr: xerr.Wrap(context.DeadlineExceeded, "wrap"),
+			expected: http.StatusGatewayTimeout,
+		},
+		{
+			name:     "code named like a gRPC code",
+			inputErr: xerr.WithCode(xerr.Unavailable("db down"), "RESOURCE_EXHAUSTED"),
+			expected: http.StatusTooManyRequests,
+		},
+		{
+			name:     "kind",
+			inputErr: xerr.WithCode(xerr.Wrap(xerr.NotFound("user %d", 42), "wrap"), "E001"),
+			expected: http.StatusNotFound,
+		},
+		{
+			name:     "field violations",
+			inputErr: validation.ErrOrNil(),
+			expected: http.StatusBadRequest,
+		},
+	}
+
+	for _, testData := range tests {
+		test := testData // capture range variable
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			// act
+			result := subject(test.inputErr)
+
+			// assert
+			assertEqual(t, test.expected, result)
+		})
+	}
+
+	assertNil(t, xerr.WithHTTPStatus(nil, http.StatusNotFound))
+}
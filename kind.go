@@ -0,0 +1,146 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Kind is the category of an error, a common vocabulary
+// for classifying errors across application layers.
+type Kind int
+
+// Kinds.
+const (
+	// KindOther is an unclassified error.
+	KindOther Kind = iota
+	// KindNotFound is an error for an entity that does not exist.
+	KindNotFound
+	// KindInvalid is an error for an invalid operation / input.
+	KindInvalid
+	// KindPermission is an error for an operation that is not permitted.
+	KindPermission
+	// KindUnauthenticated is an error for an operation lacking valid credentials.
+	KindUnauthenticated
+	// KindConflict is an error for an entity that already exists, or is in a conflicting state.
+	KindConflict
+	// KindUnavailable is an error for a service that is temporarily unavailable.
+	KindUnavailable
+	// KindInternal is an error for an internal failure.
+	KindInternal
+)
+
+// String returns the kind's name.
+// Implements [fmt.Stringer].
+func (kind Kind) String() string {
+	switch kind {
+	case KindOther:
+		return "other"
+	case KindNotFound:
+		return "not_found"
+	case KindInvalid:
+		return "invalid"
+	case KindPermission:
+		return "permission"
+	case KindUnauthenticated:
+		return "unauthenticated"
+	case KindConflict:
+		return "conflict"
+	case KindUnavailable:
+		return "unavailable"
+	case KindInternal:
+		return "internal"
+	default:
+		return "kind(" + strconv.FormatInt(int64(kind), 10) + ")"
+	}
+}
+
+// kindKey is the annotation key under which an error's kind is stored.
+type kindKey struct{}
+
+// WithKind returns an error annotating err with given kind.
+// If err is nil, WithKind returns nil.
+func WithKind(err error, kind Kind) error {
+	if err == nil {
+		return nil
+	}
+
+	return withValue(err, kindKey{}, kind)
+}
+
+// KindOf returns the kind of an error, the outermost one found in its chain.
+// If no kind was set, [KindOther] is returned.
+func KindOf(err error) Kind {
+	if kind, found := lookupValue(err, kindKey{}); found {
+		return kind.(Kind)
+	}
+
+	return KindOther
+}
+
+// NotFound returns an error of [KindNotFound] kind, with the message
+// formatted according to a format specifier.
+// It also records the stack trace at the point it was called.
+func NotFound(format string, args ...any) error {
+	return newKindError(KindNotFound, format, args)
+}
+
+// Invalid returns an error of [KindInvalid] kind, with the message
+// formatted according to a format specifier.
+// It also records the stack trace at the point it was called.
+func Invalid(format string, args ...any) error {
+	return newKindError(KindInvalid, format, args)
+}
+
+// Permission returns an error of [KindPermission] kind, with the message
+// formatted according to a format specifier.
+// It also records the stack trace at the point it was called.
+func Permission(format string, args ...any) error {
+	return newKindError(KindPermission, format, args)
+}
+
+// Unauthenticated returns an error of [KindUnauthenticated] kind, with the message
+// formatted according to a format specifier.
+// It also records the stack trace at the point it was called.
+func Unauthenticated(format string, args ...any) error {
+	return newKindError(KindUnauthenticated, format, args)
+}
+
+// Conflict returns an error of [KindConflict] kind, with the message
+// formatted according to a format specifier.
+// It also records the stack trace at the point it was called.
+func Conflict(format string, args ...any) error {
+	return newKindError(KindConflict, format, args)
+}
+
+// Unavailable returns an error of [KindUnavailable] kind, with the message
+// formatted according to a format specifier.
+// It also records the stack trace at the point it was called.
+func Unavailable(format string, args ...any) error {
+	return newKindError(KindUnavailable, format, args)
+}
+
+// Internal returns an error of [KindInternal] kind, with the message
+// formatted according to a format specifier.
+// It also records the stack trace at the point it was called.
+func Internal(format string, args ...any) error {
+	return newKindError(KindInternal, format, args)
+}
+
+// newKindError returns an error of given kind.
+// It must be called directly from the exported constructor, as the stack trace
+// is captured starting with the constructor's caller.
+func newKindError(kind Kind, format string, args []any) error {
+	err := &stackError{
+		msg:       fmt.Sprintf(format, args...),
+		stackPCs:  getCallStackSkip(1, maxStackFrames),
+		createdAt: time.Now(),
+	}
+
+	return created(withValue(err, kindKey{}, kind))
+}
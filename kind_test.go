@@ -0,0 +1,140 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr_test
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/actforgood/xerr"
+)
+
+func TestKindOf(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		subject = xerr.KindOf
+		stdErr  = errors.New("some standard error")
+		tests   = [...]struct {
+			name     string
+			inputErr error
+			expected xerr.Kind
+		}{
+			{
+				name:     "nil error",
+				inputErr: nil,
+				expected: xerr.KindOther,
+			},
+			{
+				name:     "error without kind",
+				inputErr: stdErr,
+				expected: xerr.KindOther,
+			},
+			{
+				name:     "error with kind",
+				inputErr: xerr.WithKind(stdErr, xerr.KindConflict),
+				expected: xerr.KindConflict,
+			},
+			{
+				name:     "wrapped error with kind",
+				inputErr: fmt.Errorf("wrap: %w", xerr.Wrap(xerr.NotFound("user %d", 42), "wrap")),
+				expected: xerr.KindNotFound,
+			},
+			{
+				name:     "error with multiple kinds, expect outermost",
+				inputErr: xerr.WithKind(xerr.Invalid("bad input"), xerr.KindInternal),
+				expected: xerr.KindInternal,
+			},
+		}
+	)
+
+	for _, testData := range tests {
+		test := testData // capture range variable
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			// act
+			result := subject(test.inputErr)
+
+			// assert
+			assertEqual(t, test.expected, result)
+		})
+	}
+
+	assertNil(t, xerr.WithKind(nil, xerr.KindNotFound))
+}
+
+func TestKindConstructors(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	tests := [...]struct {
+		name         string
+		subject      func(format string, args ...any) error
+		expectedKind xerr.Kind
+	}{
+		{name: "NotFound", subject: xerr.NotFound, expectedKind: xerr.KindNotFound},
+		{name: "Invalid", subject: xerr.Invalid, expectedKind: xerr.KindInvalid},
+		{name: "Permission", subject: xerr.Permission, expectedKind: xerr.KindPermission},
+		{name: "Unauthenticated", subject: xerr.Unauthenticated, expectedKind: xerr.KindUnauthenticated},
+		{name: "Conflict", subject: xerr.Conflict, expectedKind: xerr.KindConflict},
+		{name: "Unavailable", subject: xerr.Unavailable, expectedKind: xerr.KindUnavailable},
+		{name: "Internal", subject: xerr.Internal, expectedKind: xerr.KindInternal},
+	}
+	stackReg := `^user 42\ngithub\.com/actforgood/xerr_test\.TestKindConstructors\.func1\n\t.+kind_test\.go:\d+\n`
+
+	for _, testData := range tests {
+		test := testData // capture range variable
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			// act
+			result := test.subject("user %d", 42)
+
+			// assert
+			assertEqual(t, "user 42", result.Error())
+			assertEqual(t, test.expectedKind, xerr.KindOf(result))
+			matched, _ := regexp.MatchString(stackReg, fmt.Sprintf("%+v", result))
+			assertTrue(t, matched)
+		})
+	}
+}
+
+func TestKind_String(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	tests := [...]struct {
+		kind     xerr.Kind
+		expected string
+	}{
+		{kind: xerr.KindOther, expected: "other"},
+		{kind: xerr.KindNotFound, expected: "not_found"},
+		{kind: xerr.KindInvalid, expected: "invalid"},
+		{kind: xerr.KindPermission, expected: "permission"},
+		{kind: xerr.KindUnauthenticated, expected: "unauthenticated"},
+		{kind: xerr.KindConflict, expected: "conflict"},
+		{kind: xerr.KindUnavailable, expected: "unavailable"},
+		{kind: xerr.KindInternal, expected: "internal"},
+		{kind: xerr.Kind(100), expected: "kind(100)"},
+	}
+
+	for _, testData := range tests {
+		test := testData // capture range variable
+		t.Run(test.expected, func(t *testing.T) {
+			t.Parallel()
+
+			// act
+			result := test.kind.String()
+
+			// assert
+			assertEqual(t, test.expected, result)
+		})
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr
+
+import (
+	"log/slog"
+	"strconv"
+)
+
+// LogValue implements [slog.LogValuer].
+// The error is expanded into a group with the following attributes:
+// "msg" - the error's message, "causes" - the messages of the wrapped errors,
+// "stack" - the stack trace frames, "fields" - the error's fields.
+// Attributes with no value are omitted.
+func (err *stackError) LogValue() slog.Value {
+	return logValue(err)
+}
+
+// LogValue implements [slog.LogValuer].
+// The error is expanded the same way a stack error is,
+// so that annotating an error does not lose its structured output.
+func (err *valueError) LogValue() slog.Value {
+	return logValue(err)
+}
+
+// LogValue implements [slog.LogValuer].
+// The multi-error is expanded into a group with the following attributes:
+// "msg" - the multi-error's message, "errors" - a group with stored errors,
+// keyed by their index.
+func (mErr *MultiError) LogValue() slog.Value {
+	errs := mErr.Errors()
+	errsAttrs := make([]slog.Attr, len(errs))
+	for idx, err := range errs {
+		errsAttrs[idx] = slog.Any(strconv.Itoa(idx), err)
+	}
+
+	return slog.GroupValue(
+		slog.String("msg", mErr.Error()),
+		slog.Attr{Key: "errors", Value: slog.GroupValue(errsAttrs...)},
+	)
+}
+
+// logValue returns the structured representation of an error.
+func logValue(err error) slog.Value {
+	attrs := make([]slog.Attr, 1, 4)
+	attrs[0] = slog.String("msg", err.Error())
+	if causes := causeMessages(err); len(causes) > 0 {
+		attrs = append(attrs, slog.Any("causes", causes))
+	}
+	if frames := Frames(err); len(frames) > 0 {
+		attrs = append(attrs, slog.Any("stack", formatFrames(frames)))
+	}
+	if fields := FieldsOf(err); len(fields) > 0 {
+		fieldsAttrs := make([]slog.Attr, len(fields))
+		for idx, field := range fields {
+			fieldsAttrs[idx] = slog.Any(field.Key, field.Value)
+		}
+		attrs = append(attrs, slog.Attr{Key: "fields", Value: slog.GroupValue(fieldsAttrs...)})
+	}
+
+	return slog.GroupValue(attrs...)
+}
+
+// causeMessages returns the distinct messages of the errors wrapped by err,
+// outermost first.
+func causeMessages(err error) []string {
+	var (
+		causes  []string
+		lastMsg = err.Error()
+	)
+	for layer := 0; layer < maxChainDepth; layer++ {
+		u, ok := err.(interface{ Unwrap() error })
+		if !ok {
+			break
+		}
+		if err = u.Unwrap(); err == nil {
+			break
+		}
+		if msg := err.Error(); msg != lastMsg {
+			causes = append(causes, msg)
+			lastMsg = msg
+		}
+	}
+
+	return causes
+}
+
+// FramesAttrs returns the stack trace frames of an error (see [Frames]) as attributes,
+// one group per frame, keyed by its index, holding the "function", "file" and "line" attributes,
+// so that log backends can index individual frames.
+// Configured [SkipFrame], [FrameFnNameProcessor] and [FrameFileProcessor] are honored.
+// Returns nil if there is no stack trace.
+//
+// Example:
+//
+//	slog.Error("could not process order", slog.String("err", err.Error()),
+//		slog.Attr{Key: "frames", Value: slog.GroupValue(xerr.FramesAttrs(err)...)})
+func FramesAttrs(err error) []slog.Attr {
+	frames := Frames(err)
+	if len(frames) == 0 {
+		return nil
+	}
+
+	attrs := make([]slog.Attr, len(frames))
+	for idx, f := range frames {
+		attrs[idx] = slog.Group(
+			strconv.Itoa(idx),
+			slog.String("function", f.Function),
+			slog.String("file", f.File),
+			slog.Int("line", f.Line),
+		)
+	}
+
+	return attrs
+}
+
+// FramesMaps returns the stack trace frames of an error (see [Frames]),
+// one map per frame, holding the "function", "file" and "line" keys,
+// for loggers / encoders not supporting [slog.Attr]s.
+// Configured [SkipFrame], [FrameFnNameProcessor] and [FrameFileProcessor] are honored.
+// Returns nil if there is no stack trace.
+func FramesMaps(err error) []map[string]any {
+	frames := Frames(err)
+	if len(frames) == 0 {
+		return nil
+	}
+
+	maps := make([]map[string]any, len(frames))
+	for idx, f := range frames {
+		maps[idx] = map[string]any{
+			"function": f.Function,
+			"file":     f.File,
+			"line":     f.Line,
+		}
+	}
+
+	return maps
+}
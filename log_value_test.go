@@ -0,0 +1,160 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"regexp"
+	"testing"
+
+	"github.com/actforgood/xerr"
+)
+
+func TestLogValue(t *testing.T) {
+	t.Parallel()
+
+	t.Run("stack error", testLogValueStackError)
+	t.Run("annotated error", testLogValueAnnotatedError)
+	t.Run("multi error", testLogValueMultiError)
+}
+
+func testLogValueStackError(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	err := xerr.Wrap(xerr.Wrap(errors.New("connection refused"), "db query failed"), "could not get user")
+
+	// act
+	result := logErr(t, err)
+
+	// assert
+	assertEqual(t, "could not get user: db query failed: connection refused", result["msg"])
+	assertEqual(
+		t,
+		[]any{"db query failed: connection refused", "connection refused"},
+		result["causes"],
+	)
+	stack, _ := result["stack"].([]any)
+	if assertTrue(t, len(stack) > 0) {
+		topFrame, _ := stack[0].(string)
+		matched, _ := regexp.MatchString(
+			`^github\.com/actforgood/xerr_test\.testLogValueStackError .+log_value_test\.go:\d+$`,
+			topFrame,
+		)
+		assertTrue(t, matched)
+	}
+	assertNil(t, result["fields"])
+}
+
+func testLogValueAnnotatedError(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	err := xerr.WithFields(xerr.New("user not found"), xerr.F("user_id", 42), xerr.F("tenant", "acme"))
+
+	// act
+	result := logErr(t, err)
+
+	// assert
+	assertEqual(t, "user not found", result["msg"])
+	assertNil(t, result["causes"])
+	stack, _ := result["stack"].([]any)
+	assertTrue(t, len(stack) > 0)
+	assertEqual(t, map[string]any{"user_id": float64(42), "tenant": "acme"}, result["fields"])
+}
+
+func testLogValueMultiError(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	err := xerr.NewMultiError().Add(
+		errors.New("standard error"),
+		xerr.WithFields(xerr.New("xerr error"), xerr.F("key", "value")),
+	)
+
+	// act
+	result := logErr(t, err)
+
+	// assert
+	assertEqual(t, err.Error(), result["msg"])
+	errs, _ := result["errors"].(map[string]any)
+	if assertEqual(t, 2, len(errs)) {
+		assertEqual(t, "standard error", errs["0"])
+		secondErr, _ := errs["1"].(map[string]any)
+		assertEqual(t, "xerr error", secondErr["msg"])
+		assertEqual(t, map[string]any{"key": "value"}, secondErr["fields"])
+	}
+}
+
+// logErr logs given error with a JSON handler and returns the decoded "err" attribute.
+func logErr(t *testing.T, err error) map[string]any {
+	t.Helper()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	logger.Error("some log message", slog.Any("err", err))
+
+	var record map[string]any
+	if decodeErr := json.Unmarshal(buf.Bytes(), &record); decodeErr != nil {
+		t.Fatalf("could not decode log record: %v", decodeErr)
+	}
+	result, _ := record["err"].(map[string]any)
+
+	return result
+}
+
+func TestFramesAttrs(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	err := xerr.New("something went bad")
+	frames := xerr.Frames(err)
+
+	// act
+	attrs := xerr.FramesAttrs(err)
+	stdAttrs := xerr.FramesAttrs(errors.New("std error"))
+
+	// assert
+	if assertEqual(t, len(frames), len(attrs)) {
+		assertTrue(t, attrs[0].Equal(slog.Group(
+			"0",
+			slog.String("function", "github.com/actforgood/xerr_test.TestFramesAttrs"),
+			slog.String("file", frames[0].File),
+			slog.Int("line", frames[0].Line),
+		)))
+		assertEqual(t, "1", attrs[1].Key)
+	}
+	assertNil(t, stdAttrs)
+}
+
+func TestFramesMaps(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	err := xerr.New("something went bad")
+	frames := xerr.Frames(err)
+
+	// act
+	maps := xerr.FramesMaps(err)
+	stdMaps := xerr.FramesMaps(errors.New("std error"))
+
+	// assert
+	if assertEqual(t, len(frames), len(maps)) {
+		assertEqual(
+			t,
+			map[string]any{
+				"function": "github.com/actforgood/xerr_test.TestFramesMaps",
+				"file":     frames[0].File,
+				"line":     frames[0].Line,
+			},
+			maps[0],
+		)
+	}
+	assertNil(t, stdMaps)
+}
@@ -0,0 +1,69 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr
+
+import (
+	"errors"
+	"fmt"
+)
+
+// markedError is an error marked as being a sentinel error.
+// It is transparent: message, formatting and unwrapping
+// are delegated to the marked error.
+type markedError struct {
+	// err is the marked error.
+	err error
+	// sentinel is the error err is marked as.
+	sentinel error
+}
+
+// Error returns the marked error's message.
+// Implements std error interface.
+func (err *markedError) Error() string {
+	return err.err.Error()
+}
+
+// Format implements [fmt.Formatter].
+// It delegates to the marked error.
+func (err *markedError) Format(f fmt.State, verb rune) {
+	formatError(f, verb, err.err)
+}
+
+// Unwrap returns the marked error.
+// It implements [errors.Is] / [errors.As] APIs.
+func (err *markedError) Unwrap() error {
+	return err.err
+}
+
+// Is implements standard [errors.Is] API,
+// reporting whether the sentinel error is (or wraps) target.
+func (err *markedError) Is(target error) bool {
+	return errors.Is(err.sentinel, target)
+}
+
+// MarkAs returns an error marking err as being the sentinel error, so that
+// errors.Is(result, sentinel) reports true, while err's message, stack trace
+// and chain are kept untouched.
+// It is useful for classifying third party errors into an own sentinel errors taxonomy.
+// If err is nil, MarkAs returns nil. If sentinel is nil, err is returned.
+//
+// Example:
+//
+//	var ErrNotFound = errors.New("not found")
+//
+//	if errors.Is(err, sql.ErrNoRows) {
+//		return xerr.MarkAs(err, ErrNotFound)
+//	}
+func MarkAs(err, sentinel error) error {
+	if err == nil || sentinel == nil {
+		return err
+	}
+
+	return &markedError{
+		err:      err,
+		sentinel: sentinel,
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/actforgood/xerr"
+)
+
+func TestMarkAs(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		errNotFound = errors.New("not found")
+		errClient   = fmt.Errorf("client error: %w", errNotFound)
+		origErr     = xerr.WithCode(xerr.Wrap(io.EOF, "reading row"), "E001")
+	)
+
+	// act
+	result := xerr.MarkAs(origErr, errClient)
+
+	// assert
+	assertEqual(t, "reading row: EOF", result.Error())
+	assertTrue(t, errors.Is(result, errClient))
+	assertTrue(t, errors.Is(result, errNotFound))
+	assertTrue(t, errors.Is(result, io.EOF))
+	assertFalse(t, errors.Is(result, io.ErrUnexpectedEOF))
+	assertTrue(t, errors.Is(xerr.Wrap(result, "get user"), errNotFound))
+	assertEqual(t, xerr.Code("E001"), xerr.CodeOf(result))
+	assertEqual(t, xerr.Frames(origErr), xerr.Frames(result))
+	assertEqual(t, fmt.Sprintf("%+v", origErr), fmt.Sprintf("%+v", result))
+}
+
+func TestMarkAs_nil(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	errNotFound := errors.New("not found")
+
+	// act
+	resultNilErr := xerr.MarkAs(nil, errNotFound)
+	resultNilSentinel := xerr.MarkAs(io.EOF, nil)
+
+	// assert
+	assertNil(t, resultNilErr)
+	assertEqual(t, io.EOF, resultNilSentinel)
+}
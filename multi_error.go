@@ -10,8 +10,11 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"reflect"
 	"strconv"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // MultiError holds a pool of errors.
@@ -20,6 +23,42 @@ import (
 type MultiError struct {
 	errors []error
 	mu     *sync.RWMutex
+	// dedupKey is the custom deduplication key function, see [WithDedupKey].
+	dedupKey func(err error) any
+	// index speeds up [MultiError.AddOnce] lookups, it is built on demand.
+	index *dedupIndex
+	// formatter is the custom formatter, see [WithMultiErrorFormatter].
+	formatter MultiErrorFormatter
+	// shards holds the added errors not merged yet, see [NewMultiErrorConcurrent].
+	shards *addShards
+	// rendered memoizes the stored errors' renderings, it is dropped on every change.
+	rendered atomic.Pointer[multiErrorRender]
+	// ringSize is the maximum number of errors retained, see [NewMultiErrorRing].
+	ringSize int
+	// dropped is the number of errors dropped, in order to retain only ringSize errors.
+	dropped int
+	// counted indexes the errors stored with [MultiError.AddCounted], by their key.
+	counted map[any]*countedError
+}
+
+// MultiErrorOption defines optional function for configuring a [MultiError].
+type MultiErrorOption func(*MultiError)
+
+// WithDedupKey configures the function returning the key by which errors are
+// deduplicated by [MultiError.AddOnce], instead of [errors.Is] semantics.
+// Two errors are considered the same if they have equal keys.
+// Keys must be comparable (like strings, numbers, etc.).
+//
+// Example:
+//
+//	// deduplicate errors by their message.
+//	mErr := xerr.NewMultiError(xerr.WithDedupKey(func(err error) any {
+//		return err.Error()
+//	}))
+func WithDedupKey(fn func(err error) any) MultiErrorOption {
+	return func(mErr *MultiError) {
+		mErr.dedupKey = fn
+	}
 }
 
 // NewMultiError instantiates a new MultiError object.
@@ -28,11 +67,29 @@ type MultiError struct {
 // as parameter to a function. Otherwise just declare the variable's type
 // and get effective instance returned by [MultiError.Add] / [MultiError.AddOnce] APIs,
 // to avoid unnecessary allocation if those APIs end up never being called.
-func NewMultiError() *MultiError {
-	return &MultiError{
+func NewMultiError(opts ...MultiErrorOption) *MultiError {
+	mErr := &MultiError{
 		errors: make([]error, 0),
 		mu:     new(sync.RWMutex),
 	}
+	for _, opt := range opts {
+		opt(mErr)
+	}
+
+	return mErr
+}
+
+// NewMultiErrorRing instantiates a new MultiError object which retains only
+// the most recent n errors (n is at least 1), the older ones being dropped as new
+// errors are added. The total number of errors added can be obtained with [MultiError.Total].
+// It is useful for long-running processes accumulating errors (like connection errors),
+// which need recent context, not an unbounded history.
+// Note: [MultiError.AddOnce] deduplicates errors only against the retained ones.
+func NewMultiErrorRing(n int, opts ...MultiErrorOption) *MultiError {
+	mErr := NewMultiError(opts...)
+	mErr.ringSize = max(n, 1)
+
+	return mErr
 }
 
 // newMultiError initializes internally a MultiError object, not concurrent safe.
@@ -52,32 +109,55 @@ func (mErr *MultiError) Error() string {
 	mErr.rLock()
 	defer mErr.rUnlock()
 
-	switch len(mErr.errors) {
-	case 0:
+	if len(mErr.errors) == 0 {
 		return ""
-	case 1:
-		return mErr.errors[0].Error()
-	default:
-		buf := bytes.Buffer{}
-		for _, err := range mErr.errors {
-			buf.WriteString(err.Error())
-			buf.WriteByte('\n')
-		}
+	}
+
+	return mErr.memoized(renderError, mErr.errorMsg)
+}
+
+// errorMsg returns the stored errors' messages, see [MultiError.Error].
+// Caller must hold the read lock.
+func (mErr *MultiError) errorMsg() string {
+	if formatter := mErr.getFormatter(); formatter != nil {
+		var state stringState
+		formatter(&state, 's', mErr.errors)
 
-		return string(buf.Bytes()[:buf.Len()-1])
+		return state.String()
+	}
+
+	if len(mErr.errors) == 1 {
+		return mErr.errors[0].Error()
+	}
+	buf := bytes.Buffer{}
+	for _, err := range mErr.errors {
+		buf.WriteString(err.Error())
+		buf.WriteByte('\n')
 	}
+
+	return string(buf.Bytes()[:buf.Len()-1])
 }
 
 // Add appends the given error(s) in MultiError.
+// An error which is, or (indirectly) wraps, this MultiError is not stored,
+// as storing it would make the MultiError wrap itself (and its message infinite),
+// [ErrCyclicMultiError] is stored instead, in its place.
 // It returns the MultiError, eventually initialized.
 func (mErr *MultiError) Add(errs ...error) *MultiError {
 	for _, err := range errs {
 		if err != nil {
+			err = mErr.acyclic(err)
 			if mErr == nil {
 				mErr = newMultiError()
 			}
+			if mErr.shards != nil {
+				mErr.shards.add(err)
+
+				continue
+			}
 			mErr.lock()
 			mErr.errors = append(mErr.errors, err)
+			mErr.trimRing()
 			mErr.unlock()
 		}
 	}
@@ -85,15 +165,43 @@ func (mErr *MultiError) Add(errs ...error) *MultiError {
 	return mErr
 }
 
+// ErrCyclicMultiError is the error stored by a [MultiError] in place of
+// an error which is, or (indirectly) wraps, the MultiError itself, see [MultiError.Add].
+// Check for it with [errors.Is], if errors wrapping a MultiError may be added to it.
+var ErrCyclicMultiError = errors.New("xerr: an error wrapping the MultiError was added to itself")
+
+// acyclic returns given error, or [ErrCyclicMultiError] if it is,
+// or (indirectly) wraps, this MultiError.
+func (mErr *MultiError) acyclic(err error) error {
+	if mErr == nil {
+		return err
+	}
+
+	wrapsMErr := !walkChain(err, func(e error) bool {
+		return e != error(mErr)
+	})
+	if wrapsMErr {
+		return ErrCyclicMultiError
+	}
+
+	return err
+}
+
 // AddOnce stores the given error(s) in MultiError,
 // only if they do not exist already. Comparison is
-// accomplished with [errors.Is] API.
+// accomplished with [errors.Is] API (unless [WithDedupKey] is configured).
+// Stored errors are indexed, so that lookups are roughly O(1), instead of
+// scanning all stored errors (only errors having custom Is(error) bool methods
+// in their chain are scanned).
+// An error which is, or (indirectly) wraps, this MultiError is replaced
+// by [ErrCyclicMultiError], see [MultiError.Add].
 // It returns the MultiError, eventually initialized.
 func (mErr *MultiError) AddOnce(errs ...error) *MultiError {
 	for _, err := range errs {
 		if err == nil {
 			continue
 		}
+		err = mErr.acyclic(err)
 		if mErr == nil {
 			mErr = newMultiError()
 		}
@@ -105,16 +213,363 @@ func (mErr *MultiError) AddOnce(errs ...error) *MultiError {
 			continue
 		}
 		mErr.errors = append(mErr.errors, err)
+		mErr.trimRing()
 		mErr.unlock()
 	}
 
 	return mErr
 }
 
+// AddNamed stores the given error in MultiError, labelled with given name
+// (a field name, a shard ID, a file name, etc.), so that it can be told
+// which item failed. The label is included in the error's message,
+// formatting and JSON form (see [Serializer]), and can be retrieved with [NameOf].
+// It returns the MultiError, eventually initialized.
+//
+// Example:
+//
+//	for _, file := range files {
+//		if err := process(file); err != nil {
+//			multiErr = multiErr.AddNamed(file, err)
+//		}
+//	}
+func (mErr *MultiError) AddNamed(name string, err error) *MultiError {
+	if err == nil {
+		return mErr
+	}
+
+	return mErr.Add(&namedError{name: name, err: err})
+}
+
+// AddWarning stores the given error(s) in MultiError as warnings, that is
+// annotated with [SeverityWarn] severity (see [WithSeverity]).
+// Warnings are non-fatal findings, accumulated together with real failures,
+// see [MultiError.HasFatal], [MultiError.Warnings].
+// It returns the MultiError, eventually initialized.
+//
+// Example:
+//
+//	if deprecated(field) {
+//		multiErr = multiErr.AddWarning(fmt.Errorf("field %q is deprecated", field))
+//	}
+func (mErr *MultiError) AddWarning(errs ...error) *MultiError {
+	for _, err := range errs {
+		if err != nil {
+			mErr = mErr.Add(WithSeverity(err, SeverityWarn))
+		}
+	}
+
+	return mErr
+}
+
+// HasFatal returns true if MultiError stores at least an error which is not
+// a warning, that is having at least [SeverityError] severity (see [SeverityOf]).
+func (mErr *MultiError) HasFatal() bool {
+	if mErr == nil {
+		return false
+	}
+	mErr.rLock()
+	defer mErr.rUnlock()
+
+	for _, err := range mErr.errors {
+		if !isWarning(err) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Warnings returns the stored warnings, that is errors having a lower
+// severity than [SeverityError] (see [MultiError.AddWarning], [SeverityOf]).
+func (mErr *MultiError) Warnings() []error {
+	if mErr == nil {
+		return nil
+	}
+	mErr.rLock()
+	defer mErr.rUnlock()
+
+	var warnings []error
+	for _, err := range mErr.errors {
+		if isWarning(err) {
+			warnings = append(warnings, err)
+		}
+	}
+
+	return warnings
+}
+
+// isWarning returns true if given error has a lower severity than [SeverityError].
+func isWarning(err error) bool {
+	return SeverityOf(err) < SeverityError
+}
+
+// Shard returns n child MultiErrors, meant to be used individually
+// by n workers, without locking, for a contention-free aggregation of errors.
+// Once the workers are done, the children can be merged back with [MultiError.Merge].
+// Note: children are not concurrent safe, each of them must be used by a single goroutine.
+//
+// Example:
+//
+//	shards := multiErr.Shard(workersNo)
+//	for i := 0; i < workersNo; i++ {
+//		go worker(shards[i]) // calls shards[i].Add(err)
+//	}
+//	// wait for workers ...
+//	multiErr = multiErr.Merge(shards...)
+func (mErr *MultiError) Shard(n int) []*MultiError {
+	if n < 0 {
+		n = 0
+	}
+	shards := make([]*MultiError, n)
+	for idx := range shards {
+		shards[idx] = newMultiError()
+	}
+
+	return shards
+}
+
+// Merge moves the errors stored by given MultiErrors (usually obtained with
+// [MultiError.Shard]) into this MultiError, in the order children are given.
+// Children get reset.
+// Stored errors which are, or (indirectly) wrap, this MultiError are replaced
+// by [ErrCyclicMultiError], see [MultiError.Add].
+// It returns the MultiError, eventually initialized.
+func (mErr *MultiError) Merge(children ...*MultiError) *MultiError {
+	for _, child := range children {
+		if child == nil || child == mErr {
+			continue
+		}
+		// the child is not locked while this MultiError is, as it may be merging
+		// this MultiError into itself concurrently.
+		errs := child.take()
+		if len(errs) == 0 {
+			continue
+		}
+		if mErr == nil {
+			mErr = newMultiError()
+		}
+		for idx, err := range errs {
+			errs[idx] = mErr.acyclic(err)
+		}
+
+		mErr.lock()
+		mErr.errors = append(mErr.errors, errs...)
+		for _, err := range errs {
+			if cErr, ok := err.(*countedError); ok {
+				mErr.registerCounted(cErr)
+			}
+		}
+		mErr.trimRing()
+		mErr.unlock()
+	}
+
+	return mErr
+}
+
+// take removes the stored errors, and returns them, the MultiError being reset.
+func (mErr *MultiError) take() []error {
+	mErr.lock()
+	defer mErr.unlock()
+
+	errs := mErr.errors
+	if len(errs) > 0 {
+		mErr.errors = make([]error, 0)
+		mErr.dropped = 0
+		clear(mErr.counted)
+		mErr.resetIndex()
+	}
+
+	return errs
+}
+
+// Filter returns a new MultiError holding the stored errors
+// for which given predicate returns true, in the same order.
+// This MultiError is not altered.
+//
+// Example:
+//
+//	// drop ignorable errors.
+//	multiErr = multiErr.Filter(func(err error) bool {
+//		return !errors.Is(err, context.Canceled)
+//	})
+func (mErr *MultiError) Filter(pred func(err error) bool) *MultiError {
+	if mErr == nil {
+		return nil
+	}
+	mErr.rLock()
+	defer mErr.rUnlock()
+
+	result := mErr.derive()
+	for _, err := range mErr.errors {
+		if pred(err) {
+			result.errors = append(result.errors, err)
+		}
+	}
+
+	return result
+}
+
+// Map returns a new MultiError holding the stored errors
+// transformed by given function, in the same order.
+// Errors mapped to nil are dropped.
+// This MultiError is not altered.
+//
+// Example:
+//
+//	// annotate each error.
+//	multiErr = multiErr.Map(func(err error) error {
+//		return xerr.Wrap(err, "batch #"+batchID)
+//	})
+func (mErr *MultiError) Map(fn func(err error) error) *MultiError {
+	if mErr == nil {
+		return nil
+	}
+	mErr.rLock()
+	defer mErr.rUnlock()
+
+	result := mErr.derive()
+	for _, err := range mErr.errors {
+		if mappedErr := fn(err); mappedErr != nil {
+			result.errors = append(result.errors, mappedErr)
+		}
+	}
+
+	return result
+}
+
+// Split returns two new MultiErrors, the first one holding the stored errors
+// for which given predicate returns true, and the second one holding the rest of them.
+// This MultiError is not altered.
+//
+// Example:
+//
+//	// separate warnings from failures.
+//	warnings, failures := multiErr.Split(func(err error) bool {
+//		return errors.Is(err, ErrWarning)
+//	})
+func (mErr *MultiError) Split(pred func(err error) bool) (match, rest *MultiError) {
+	if mErr == nil {
+		return nil, nil
+	}
+	mErr.rLock()
+	defer mErr.rUnlock()
+
+	match, rest = mErr.derive(), mErr.derive()
+	for _, err := range mErr.errors {
+		if pred(err) {
+			match.errors = append(match.errors, err)
+		} else {
+			rest.errors = append(rest.errors, err)
+		}
+	}
+
+	return match, rest
+}
+
+// derive returns a new, empty, MultiError having the same configuration
+// as this MultiError (concurrent safety, dedup key, formatter, ring size).
+func (mErr *MultiError) derive() *MultiError {
+	result := newMultiError()
+	if mErr.mu != nil {
+		result.mu = new(sync.RWMutex)
+	}
+	result.dedupKey = mErr.dedupKey
+	result.formatter = mErr.formatter
+	result.ringSize = mErr.ringSize
+
+	return result
+}
+
 // hasError checks if an error already exists in MultiError.
-// Comparison is done with [errors.Is] API.
+// Comparison is done with [errors.Is] API, or by the configured dedup key.
 func (mErr *MultiError) hasError(err error) bool {
-	for _, storedErr := range mErr.errors {
+	if mErr.index == nil {
+		mErr.index = &dedupIndex{keys: make(map[any]struct{})}
+	}
+	idx := mErr.index
+	for ; idx.indexedLen < len(mErr.errors); idx.indexedLen++ {
+		idx.add(mErr.errors[idx.indexedLen], mErr.dedupKey)
+	}
+
+	return idx.has(err, mErr.dedupKey)
+}
+
+// trimRing drops the oldest errors, in order to retain at most ringSize errors,
+// if MultiError was created with [NewMultiErrorRing].
+// Caller must hold the write lock.
+func (mErr *MultiError) trimRing() {
+	if mErr.ringSize <= 0 || len(mErr.errors) <= mErr.ringSize {
+		return
+	}
+
+	// reslicing, the backing array is reallocated, with the retained errors only,
+	// when its capacity is exhausted, so adding errors is amortized O(1).
+	toDrop := len(mErr.errors) - mErr.ringSize
+	mErr.unregisterCounted(mErr.errors[:toDrop])
+	clear(mErr.errors[:toDrop]) // release dropped errors
+	mErr.errors = mErr.errors[toDrop:]
+	mErr.dropped += toDrop
+	mErr.resetIndex() // indexed positions are not valid anymore.
+}
+
+// resetIndex drops the AddOnce lookup index, to be called
+// whenever stored errors are removed.
+func (mErr *MultiError) resetIndex() {
+	mErr.index = nil
+}
+
+// dedupIndex indexes stored errors, for [MultiError.AddOnce] lookups.
+// It covers the first indexedLen stored errors.
+type dedupIndex struct {
+	// keys holds the comparable errors found in stored errors' chains,
+	// or, if a dedup key function is configured, stored errors' keys.
+	keys map[any]struct{}
+	// isers holds the stored errors having in their chain an error with
+	// a custom Is(error) bool method, which need to be checked with [errors.Is].
+	isers []error
+	// indexedLen is the number of stored errors indexed.
+	indexedLen int
+}
+
+// add indexes given stored error.
+func (idx *dedupIndex) add(storedErr error, dedupKey func(error) any) {
+	if dedupKey != nil {
+		idx.keys[dedupKey(storedErr)] = struct{}{}
+
+		return
+	}
+
+	hasIser := false
+	walkChain(storedErr, func(e error) bool {
+		if reflect.TypeOf(e).Comparable() {
+			idx.keys[e] = struct{}{}
+		}
+		if _, ok := e.(interface{ Is(error) bool }); ok {
+			hasIser = true
+		}
+
+		return true
+	})
+	if hasIser {
+		idx.isers = append(idx.isers, storedErr)
+	}
+}
+
+// has checks whether given error matches any of indexed stored errors.
+func (idx *dedupIndex) has(err error, dedupKey func(error) any) bool {
+	if dedupKey != nil {
+		_, found := idx.keys[dedupKey(err)]
+
+		return found
+	}
+
+	if reflect.TypeOf(err).Comparable() {
+		if _, found := idx.keys[err]; found {
+			return true
+		}
+	}
+	for _, storedErr := range idx.isers {
 		if errors.Is(storedErr, err) {
 			return true
 		}
@@ -136,6 +591,60 @@ func (mErr *MultiError) Errors() []error {
 	return errors
 }
 
+// Len returns the number of stored errors.
+func (mErr *MultiError) Len() int {
+	if mErr == nil {
+		return 0
+	}
+	mErr.rLock()
+	defer mErr.rUnlock()
+
+	return len(mErr.errors)
+}
+
+// Total returns the total number of errors added, including the ones
+// dropped by a MultiError created with [NewMultiErrorRing].
+// For other MultiErrors, it is the same as [MultiError.Len].
+func (mErr *MultiError) Total() int {
+	if mErr == nil {
+		return 0
+	}
+	mErr.rLock()
+	defer mErr.rUnlock()
+
+	return len(mErr.errors) + mErr.dropped
+}
+
+// First returns the earliest stored error, or nil if there are no stored errors.
+func (mErr *MultiError) First() error {
+	if mErr == nil {
+		return nil
+	}
+	mErr.rLock()
+	defer mErr.rUnlock()
+
+	if len(mErr.errors) == 0 {
+		return nil
+	}
+
+	return mErr.errors[0]
+}
+
+// Last returns the latest stored error, or nil if there are no stored errors.
+func (mErr *MultiError) Last() error {
+	if mErr == nil {
+		return nil
+	}
+	mErr.rLock()
+	defer mErr.rUnlock()
+
+	if len(mErr.errors) == 0 {
+		return nil
+	}
+
+	return mErr.errors[len(mErr.errors)-1]
+}
+
 // Reset cleans up stored errors, if any.
 func (mErr *MultiError) Reset() {
 	if mErr == nil {
@@ -150,6 +659,9 @@ func (mErr *MultiError) Reset() {
 		}
 		mErr.errors = mErr.errors[:0]
 	}
+	mErr.dropped = 0
+	clear(mErr.counted)
+	mErr.resetIndex()
 	mErr.unlock()
 }
 
@@ -173,6 +685,98 @@ func (mErr *MultiError) ErrOrNil() error {
 	}
 }
 
+// ErrOrNilIf returns nil if MultiError does not have more stored errors than
+// the given threshold (a negative one is treated as 0), otherwise, a snapshot
+// of the stored errors (as [MultiError.ErrOrNil] returns them)
+// annotated with the count information: the message is prefixed with it, and
+// "failures", "failures_threshold" fields are attached (see [Fields]).
+// It is useful for batch jobs which can tolerate up to N failures.
+//
+// Example:
+//
+//	return multiErr.ErrOrNilIf(5) // "7 failures exceed the threshold of 5: ..."
+func (mErr *MultiError) ErrOrNilIf(threshold int) error {
+	threshold = max(threshold, 0)
+	failures, err := mErr.snapshot()
+	if failures <= threshold {
+		return nil
+	}
+
+	return thresholdError(
+		err,
+		strconv.Itoa(failures)+" failures exceed the threshold of "+strconv.Itoa(threshold),
+		F("failures", failures),
+		F("failures_threshold", threshold),
+	)
+}
+
+// ErrOrNilRatio returns nil if the ratio of stored errors out of given total
+// number of processed items does not exceed maxFailureRatio (a value between 0 and 1),
+// otherwise, a snapshot of the stored errors (as [MultiError.ErrOrNil] returns them)
+// annotated with the count information: the message is prefixed with it, and
+// "failures", "failures_total", "max_failure_ratio" fields are attached (see [Fields]).
+// It is useful for batch jobs which can tolerate up to X% failures.
+// If total is smaller than the number of stored errors, the latter is considered.
+//
+// Example:
+//
+//	return multiErr.ErrOrNilRatio(0.1, len(items)) // "15 of 100 failed, exceeding the max failure ratio of 0.1: ..."
+func (mErr *MultiError) ErrOrNilRatio(maxFailureRatio float64, total int) error {
+	failures, err := mErr.snapshot()
+	if failures == 0 {
+		return nil
+	}
+	total = max(total, failures)
+	if float64(failures)/float64(total) <= maxFailureRatio {
+		return nil
+	}
+
+	return thresholdError(
+		err,
+		strconv.Itoa(failures)+" of "+strconv.Itoa(total)+" failed, exceeding the max failure ratio of "+
+			strconv.FormatFloat(maxFailureRatio, 'g', -1, 64),
+		F("failures", failures),
+		F("failures_total", total),
+		F("max_failure_ratio", maxFailureRatio),
+	)
+}
+
+// snapshot returns the number of stored errors, and the stored errors,
+// as [MultiError.ErrOrNil] returns them, taken at once, so that they agree
+// even if errors are added concurrently. If there is more than one stored error,
+// a new MultiError holding a copy of them is returned.
+func (mErr *MultiError) snapshot() (int, error) {
+	if mErr == nil {
+		return 0, nil
+	}
+	mErr.rLock()
+	defer mErr.rUnlock()
+
+	switch len(mErr.errors) {
+	case 0:
+		return 0, nil
+	case 1:
+		return 1, mErr.errors[0]
+	default:
+		result := mErr.derive()
+		result.errors = make([]error, len(mErr.errors))
+		copy(result.errors, mErr.errors)
+
+		return len(result.errors), result
+	}
+}
+
+// thresholdError returns err annotated with given message and fields.
+// err's stack trace, if any, is kept.
+func thresholdError(err error, msg string, fields ...Field) error {
+	return WithFields(&stackError{
+		origErr:   err,
+		msg:       msg,
+		stackPCs:  existingCallStack(err),
+		createdAt: time.Now(),
+	}, fields...)
+}
+
 // Format implements [fmt.Formatter].
 // It relies upon individual error's Format() API if applicable,
 // otherwise Error() 's outcome is taken into account.
@@ -183,32 +787,54 @@ func (mErr *MultiError) Format(f fmt.State, verb rune) {
 	mErr.rLock()
 	defer mErr.rUnlock()
 
-	errorsLen := len(mErr.errors)
-	if errorsLen == 0 {
+	if len(mErr.errors) == 0 {
 		return
 	}
 
-	for idx, err := range mErr.errors {
+	if isPlainFormat(f) && (verb == 's' || verb == 'v') {
+		kind := renderS
 		if verb == 'v' {
-			_, _ = io.WriteString(f, "error #")
-			_, _ = io.WriteString(f, strconv.FormatInt(int64(idx+1), 10))
-			_, _ = io.WriteString(f, "\n")
-		}
-		if errFmt, ok := err.(fmt.Formatter); ok {
-			errFmt.Format(f, verb)
-		} else {
-			_, _ = io.WriteString(f, err.Error())
-		}
-		if idx != errorsLen-1 {
-			_, _ = io.WriteString(f, "\n")
+			kind = renderV
 		}
+		_, _ = io.WriteString(f, mErr.memoized(kind, func() string {
+			var state stringState
+			mErr.format(&state, verb)
+
+			return state.String()
+		}))
+
+		return
+	}
+
+	mErr.format(f, verb)
+}
+
+// format writes the stored errors with the configured formatter.
+// Caller must hold the read lock.
+func (mErr *MultiError) format(f fmt.State, verb rune) {
+	if formatter := mErr.getFormatter(); formatter != nil {
+		formatter(f, verb, mErr.errors)
+	} else {
+		formatMultiError(f, verb, mErr.errors)
 	}
 }
 
-// Unwrap returns original error (can be nil).
-// It implements standard [errors.Is] / [errors.As] APIs.
-// Returns recursively first error from stored errors.
-func (mErr *MultiError) Unwrap() error {
+// getFormatter returns the configured formatter, if any.
+// The MultiError's own formatter takes precedence over the global one.
+func (mErr *MultiError) getFormatter() MultiErrorFormatter {
+	if mErr.formatter != nil {
+		return mErr.formatter
+	}
+
+	return multiErrorFormatter
+}
+
+// Unwrap returns a copy of stored errors.
+// It implements standard [errors.Is] / [errors.As] APIs (Go 1.20+ multi-unwrap
+// semantics), so that all stored errors are traversed.
+// Note: [errors.Unwrap] returns nil for a MultiError, as it only handles
+// single unwrap errors.
+func (mErr *MultiError) Unwrap() []error {
 	if mErr == nil {
 		return nil
 	}
@@ -217,23 +843,15 @@ func (mErr *MultiError) Unwrap() error {
 
 	if len(mErr.errors) == 0 {
 		return nil
-	} else if len(mErr.errors) == 1 {
-		return mErr.errors[0]
-	}
-
-	var newMultiErr *MultiError
-	if mErr.mu != nil {
-		newMultiErr = NewMultiError()
-	} else {
-		newMultiErr = newMultiError()
 	}
-	_ = newMultiErr.Add(mErr.errors[1:]...)
+	errs := make([]error, len(mErr.errors))
+	copy(errs, mErr.errors)
 
-	return newMultiErr
+	return errs
 }
 
 // As implements standard [errors.As] API,
-// comparing the first error from stored ones.
+// finding the first error from stored ones that matches target.
 func (mErr *MultiError) As(target interface{}) bool {
 	if mErr == nil {
 		return false
@@ -241,8 +859,10 @@ func (mErr *MultiError) As(target interface{}) bool {
 	mErr.rLock()
 	defer mErr.rUnlock()
 
-	if len(mErr.errors) > 0 {
-		return errors.As(mErr.errors[0], target)
+	for _, err := range mErr.errors {
+		if errors.As(err, target) {
+			return true
+		}
 	}
 
 	return false
@@ -267,7 +887,9 @@ func (mErr *MultiError) Is(target error) bool {
 func (mErr *MultiError) lock() {
 	if mErr.mu != nil {
 		mErr.mu.Lock()
+		mErr.mergeShards()
 	}
+	mErr.rendered.Store(nil) // lock is acquired only for changing stored errors.
 }
 
 func (mErr *MultiError) unlock() {
@@ -278,10 +900,25 @@ func (mErr *MultiError) unlock() {
 
 func (mErr *MultiError) rLock() {
 	if mErr.mu != nil {
+		if mErr.shards != nil && mErr.shards.pending() {
+			mErr.mu.Lock()
+			mErr.mergeShards()
+			mErr.mu.Unlock()
+		}
 		mErr.mu.RLock()
 	}
 }
 
+// mergeShards moves the errors added in shards, if any, into stored errors.
+// Caller must hold the write lock.
+func (mErr *MultiError) mergeShards() {
+	if mErr.shards != nil && mErr.shards.pending() {
+		mErr.errors = mErr.shards.mergeInto(mErr.errors)
+		mErr.trimRing()
+		mErr.rendered.Store(nil)
+	}
+}
+
 func (mErr *MultiError) rUnlock() {
 	if mErr.mu != nil {
 		mErr.mu.RUnlock()
@@ -0,0 +1,62 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// This file contains a compatibility layer with hashicorp/go-multierror,
+// easing the migration from it to MultiError, without changing every call site
+// and log output expectation.
+//
+// Example:
+//
+//	// myapp/bootstrap.go
+//	func init() {
+//		xerr.SetMultiErrorFormatter(xerr.HashicorpFormatter)
+//	}
+//
+//	// call sites
+//	var result *xerr.MultiError
+//	result = result.Add(err1, err2)
+//	return result.ErrorOrNil()
+
+// ErrorOrNil is an alias for [MultiError.ErrOrNil], matching hashicorp/go-multierror API.
+// Note: unlike hashicorp's, it returns the single error if only one is stored.
+func (mErr *MultiError) ErrorOrNil() error {
+	return mErr.ErrOrNil()
+}
+
+// WrappedErrors is an alias for [MultiError.Errors], matching hashicorp/go-multierror API.
+func (mErr *MultiError) WrappedErrors() []error {
+	return mErr.Errors()
+}
+
+// HashicorpFormatter is a [MultiErrorFormatter] producing
+// hashicorp/go-multierror's list format:
+//
+//	2 errors occurred:
+//		* first error
+//		* second error
+//
+// (note the trailing empty line).
+func HashicorpFormatter(f fmt.State, verb rune, errs []error) {
+	if len(errs) == 1 {
+		_, _ = io.WriteString(f, "1 error occurred:\n")
+	} else {
+		_, _ = io.WriteString(f, strconv.Itoa(len(errs)))
+		_, _ = io.WriteString(f, " errors occurred:\n")
+	}
+	for _, err := range errs {
+		_, _ = io.WriteString(f, "\t* ")
+		formatError(f, verb, err)
+		_, _ = io.WriteString(f, "\n")
+	}
+	_, _ = io.WriteString(f, "\n")
+}
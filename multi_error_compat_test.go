@@ -0,0 +1,71 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr_test
+
+import (
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/actforgood/xerr"
+)
+
+func TestMultiError_ErrorOrNil_WrappedErrors(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var subject *xerr.MultiError
+
+	// act & assert
+	assertNil(t, subject.ErrorOrNil())
+	assertNil(t, subject.WrappedErrors())
+
+	// act & assert
+	subject = subject.Add(io.EOF)
+	assertEqual(t, io.EOF, subject.ErrorOrNil())
+	assertEqual(t, []error{io.EOF}, subject.WrappedErrors())
+
+	// act & assert
+	subject = subject.Add(io.ErrUnexpectedEOF)
+	assertTrue(t, subject.ErrorOrNil() == subject)
+	assertEqual(t, []error{io.EOF, io.ErrUnexpectedEOF}, subject.WrappedErrors())
+}
+
+func TestHashicorpFormatter(t *testing.T) {
+	t.Parallel()
+
+	tests := [...]struct {
+		name           string
+		errs           []error
+		expectedResult string
+	}{
+		{
+			name:           "1 error",
+			errs:           []error{io.EOF},
+			expectedResult: "1 error occurred:\n\t* EOF\n\n",
+		},
+		{
+			name:           "2 errors",
+			errs:           []error{io.EOF, xerr.NewOpt("not found", xerr.WithNoStack())},
+			expectedResult: "2 errors occurred:\n\t* EOF\n\t* not found\n\n",
+		},
+	}
+
+	for _, testData := range tests {
+		test := testData // capture range variable
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			// arrange
+			subject := xerr.NewMultiError(xerr.WithMultiErrorFormatter(xerr.HashicorpFormatter)).
+				Add(test.errs...)
+
+			// act & assert
+			assertEqual(t, test.expectedResult, subject.Error())
+			assertEqual(t, test.expectedResult, fmt.Sprintf("%v", subject))
+		})
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr
+
+import (
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// NewMultiErrorConcurrent instantiates a new MultiError object optimized for
+// high contention workloads, where hundreds of goroutines call [MultiError.Add]
+// in parallel.
+// Added errors are spread over independently locked shards (roughly one per P),
+// and are merged back, in the order they were added, the first time
+// the MultiError is read (with [MultiError.Errors], [MultiError.Error], etc.).
+// For low contention workloads, or workloads mixing reads and writes,
+// prefer [NewMultiError].
+func NewMultiErrorConcurrent(opts ...MultiErrorOption) *MultiError {
+	mErr := NewMultiError(opts...)
+	mErr.shards = newAddShards()
+
+	return mErr
+}
+
+// addShards holds the errors added to a MultiError
+// created with [NewMultiErrorConcurrent], not yet merged.
+type addShards struct {
+	// seq is the number of errors added so far.
+	seq atomic.Uint64
+	// merged is the number of errors merged so far.
+	merged atomic.Uint64
+	// pool caches the shards per P, so that goroutines running
+	// on different Ps do not contend for the same shard.
+	pool sync.Pool
+	// all holds all the shards ever created (pool may drop its items).
+	all []*addShard
+	mu  sync.Mutex
+}
+
+// addShard is a shard of added errors.
+type addShard struct {
+	mu   sync.Mutex
+	errs []seqError
+}
+
+// seqError is an added error, along with its sequence number.
+type seqError struct {
+	seq uint64
+	err error
+}
+
+// newAddShards instantiates the shards holder.
+func newAddShards() *addShards {
+	as := new(addShards)
+	as.pool.New = func() any {
+		shard := new(addShard)
+		as.mu.Lock()
+		as.all = append(as.all, shard)
+		as.mu.Unlock()
+
+		return shard
+	}
+
+	return as
+}
+
+// add stores given error in a shard.
+func (as *addShards) add(err error) {
+	shard := as.pool.Get().(*addShard)
+	shard.mu.Lock()
+	shard.errs = append(shard.errs, seqError{seq: as.seq.Add(1), err: err})
+	shard.mu.Unlock()
+	as.pool.Put(shard)
+}
+
+// pending returns true if there are added errors not merged yet.
+func (as *addShards) pending() bool {
+	return as.seq.Load() != as.merged.Load()
+}
+
+// mergeInto appends the added errors to given errors, in the order they were added.
+// Caller must hold the MultiError's write lock.
+func (as *addShards) mergeInto(errs []error) []error {
+	as.mu.Lock()
+	shards := as.all
+	as.mu.Unlock()
+
+	var added []seqError
+	for _, shard := range shards {
+		shard.mu.Lock()
+		added = append(added, shard.errs...)
+		clear(shard.errs) // keep the allocated memory
+		shard.errs = shard.errs[:0]
+		shard.mu.Unlock()
+	}
+	if len(added) == 0 {
+		return errs
+	}
+	as.merged.Add(uint64(len(added)))
+
+	slices.SortFunc(added, func(a, b seqError) int {
+		switch {
+		case a.seq < b.seq:
+			return -1
+		case a.seq > b.seq:
+			return 1
+		default:
+			return 0
+		}
+	})
+	for _, e := range added {
+		errs = append(errs, e.err)
+	}
+
+	return errs
+}
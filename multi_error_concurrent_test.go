@@ -0,0 +1,140 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr_test
+
+import (
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/actforgood/xerr"
+)
+
+func TestNewMultiErrorConcurrent(t *testing.T) {
+	t.Parallel()
+
+	t.Run("order is preserved", testNewMultiErrorConcurrentOrder)
+	t.Run("concurrent adds and reads", testNewMultiErrorConcurrentAddsAndReads)
+	t.Run("mixed with other APIs", testNewMultiErrorConcurrentMixedAPIs)
+}
+
+func testNewMultiErrorConcurrentOrder(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		subject  = xerr.NewMultiErrorConcurrent()
+		errsNo   = 100
+		expected = make([]error, 0, errsNo)
+	)
+
+	// act
+	for i := 0; i < errsNo; i++ {
+		err := errors.New("err " + strconv.Itoa(i))
+		expected = append(expected, err)
+		_ = subject.Add(err, nil)
+	}
+
+	// assert
+	assertEqual(t, expected, subject.Errors())
+	assertEqual(t, errsNo, subject.Len())
+	assertEqual(t, expected[0], subject.First())
+	assertEqual(t, expected[errsNo-1], subject.Last())
+}
+
+func testNewMultiErrorConcurrentAddsAndReads(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		subject      = xerr.NewMultiErrorConcurrent()
+		goroutinesNo = 50
+		errsPerG     = 20
+		wg           sync.WaitGroup
+	)
+
+	// act
+	for i := 0; i < goroutinesNo; i++ {
+		wg.Add(1)
+		go func(gNo int) {
+			defer wg.Done()
+			for j := 0; j < errsPerG; j++ {
+				_ = subject.Add(errors.New(strconv.Itoa(gNo) + "-" + strconv.Itoa(j)))
+				if j%5 == 0 {
+					_ = subject.Len()
+					_ = subject.Error()
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	// assert
+	errs := subject.Errors()
+	assertEqual(t, goroutinesNo*errsPerG, len(errs))
+	lastIdxPerG := make(map[string]int, goroutinesNo) // each goroutine's errors keep their order.
+	for _, err := range errs {
+		parts := strings.SplitN(err.Error(), "-", 2)
+		idx, _ := strconv.Atoi(parts[1])
+		if lastIdx, found := lastIdxPerG[parts[0]]; found {
+			assertTrue(t, idx > lastIdx)
+		}
+		lastIdxPerG[parts[0]] = idx
+	}
+}
+
+func testNewMultiErrorConcurrentMixedAPIs(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	subject := xerr.NewMultiErrorConcurrent()
+
+	// act & assert
+	_ = subject.Add(io.EOF)
+	_ = subject.AddOnce(io.EOF, io.ErrUnexpectedEOF)
+	_ = subject.Add(io.ErrShortWrite)
+	assertEqual(t, []error{io.EOF, io.ErrUnexpectedEOF, io.ErrShortWrite}, subject.Errors())
+	assertTrue(t, errors.Is(subject, io.EOF))
+
+	// act & assert
+	subject.Reset()
+	_ = subject.Add(io.ErrClosedPipe)
+	assertEqual(t, io.ErrClosedPipe, subject.ErrOrNil())
+
+	// act & assert
+	other := xerr.NewMultiError().Merge(subject)
+	assertEqual(t, []error{io.ErrClosedPipe}, other.Errors())
+	assertEqual(t, 0, subject.Len())
+}
+
+func BenchmarkMultiError_Add_parallel(b *testing.B) {
+	err := errors.New("some error to be Added to MultiError")
+
+	b.Run("NewMultiError", func(b *testing.B) {
+		mErr := xerr.NewMultiError()
+		b.ReportAllocs()
+		b.ResetTimer()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				_ = mErr.Add(err)
+			}
+		})
+	})
+
+	b.Run("NewMultiErrorConcurrent", func(b *testing.B) {
+		mErr := xerr.NewMultiErrorConcurrent()
+		b.ReportAllocs()
+		b.ResetTimer()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				_ = mErr.Add(err)
+			}
+		})
+	})
+}
@@ -0,0 +1,139 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"sync/atomic"
+)
+
+// countedError is an error stored with [MultiError.AddCounted],
+// along with its number of occurrences.
+type countedError struct {
+	// err is the first occurrence of the error.
+	err error
+	// key is the key identical errors are grouped by.
+	key any
+	// count is the number of occurrences.
+	count atomic.Int64
+}
+
+// Error returns the error's message, suffixed with the number of occurrences,
+// if the error occurred more than once, like "connection refused (x137)".
+// Implements std error interface.
+func (err *countedError) Error() string {
+	if count := err.count.Load(); count > 1 {
+		return err.err.Error() + " (x" + strconv.FormatInt(count, 10) + ")"
+	}
+
+	return err.err.Error()
+}
+
+// Format implements [fmt.Formatter].
+// It delegates to the counted error, and writes the number of occurrences,
+// if the error occurred more than once.
+func (err *countedError) Format(f fmt.State, verb rune) {
+	formatError(f, verb, err.err)
+	if count := err.count.Load(); count > 1 {
+		_, _ = io.WriteString(f, " (x")
+		_, _ = io.WriteString(f, strconv.FormatInt(count, 10))
+		_, _ = io.WriteString(f, ")")
+	}
+}
+
+// Unwrap returns the counted error.
+// It implements [errors.Is] / [errors.As] APIs.
+func (err *countedError) Unwrap() error {
+	return err.err
+}
+
+// AddCounted stores the given error(s) in MultiError, counting occurrences:
+// an error identical to one previously added with AddCounted is not stored again,
+// instead, the occurrences counter of the latter gets incremented.
+// Errors are identical if they have the same [Fingerprint], or the same key,
+// if [WithDedupKey] is configured.
+// The number of occurrences is written in the error's message,
+// like "connection refused (x137)", and can be retrieved with [CountOf].
+// It is useful for retry-heavy workloads, which would otherwise store
+// thousands of identical errors.
+// An error which is, or (indirectly) wraps, this MultiError is replaced
+// by [ErrCyclicMultiError], see [MultiError.Add].
+// It returns the MultiError, eventually initialized.
+func (mErr *MultiError) AddCounted(errs ...error) *MultiError {
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+		err = mErr.acyclic(err)
+		if mErr == nil {
+			mErr = newMultiError()
+		}
+
+		var key any
+		if mErr.dedupKey != nil {
+			key = mErr.dedupKey(err)
+		} else {
+			key = Fingerprint(err)
+		}
+
+		mErr.lock()
+		if cErr, found := mErr.counted[key]; found {
+			cErr.count.Add(1)
+			mErr.unlock()
+
+			continue
+		}
+		cErr := &countedError{err: err, key: key}
+		cErr.count.Store(1)
+		mErr.registerCounted(cErr)
+		mErr.errors = append(mErr.errors, cErr)
+		mErr.trimRing()
+		mErr.unlock()
+	}
+
+	return mErr
+}
+
+// registerCounted indexes given counted error by its key, if not already indexed.
+// Caller must hold the write lock.
+func (mErr *MultiError) registerCounted(cErr *countedError) {
+	if mErr.counted == nil {
+		mErr.counted = make(map[any]*countedError)
+	}
+	if _, found := mErr.counted[cErr.key]; !found {
+		mErr.counted[cErr.key] = cErr
+	}
+}
+
+// unregisterCounted removes the given errors, if counted, from the index.
+// Caller must hold the write lock.
+func (mErr *MultiError) unregisterCounted(errs []error) {
+	if len(mErr.counted) == 0 {
+		return
+	}
+	for _, err := range errs {
+		if cErr, ok := err.(*countedError); ok && mErr.counted[cErr.key] == cErr {
+			delete(mErr.counted, cErr.key)
+		}
+	}
+}
+
+// CountOf returns the number of occurrences of an error stored with
+// [MultiError.AddCounted], or 1 for other errors (0 for a nil error).
+func CountOf(err error) int {
+	if err == nil {
+		return 0
+	}
+	var cErr *countedError
+	if errors.As(err, &cErr) {
+		return int(cErr.count.Load())
+	}
+
+	return 1
+}
@@ -0,0 +1,124 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/actforgood/xerr"
+)
+
+func TestMultiError_AddCounted(t *testing.T) {
+	t.Parallel()
+
+	t.Run("identical errors are counted", testMultiErrorAddCountedIdentical)
+	t.Run("with dedup key", testMultiErrorAddCountedWithDedupKey)
+	t.Run("with ring", testMultiErrorAddCountedWithRing)
+	t.Run("concurrency", testMultiErrorAddCountedConcurrency)
+}
+
+func testMultiErrorAddCountedIdentical(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var subject *xerr.MultiError
+
+	// act
+	for i := 0; i < 137; i++ {
+		subject = subject.AddCounted(errors.New("connection refused"), nil)
+	}
+	subject = subject.AddCounted(io.EOF).Add(errors.New("connection refused"))
+
+	// assert
+	errs := subject.Errors()
+	if assertEqual(t, 3, len(errs)) {
+		assertEqual(t, "connection refused (x137)", errs[0].Error())
+		assertEqual(t, 137, xerr.CountOf(errs[0]))
+		assertEqual(t, "EOF", errs[1].Error())
+		assertEqual(t, 1, xerr.CountOf(errs[1]))
+		assertTrue(t, errors.Is(errs[1], io.EOF))
+		assertEqual(t, 1, xerr.CountOf(errs[2]))
+	}
+	assertEqual(t, 0, xerr.CountOf(nil))
+	assertEqual(t, "connection refused (x137)\nEOF\nconnection refused", subject.Error())
+	assertEqual(
+		t,
+		"error #1\nconnection refused (x137)\nerror #2\nEOF\nerror #3\nconnection refused",
+		fmt.Sprintf("%v", subject),
+	)
+
+	// act & assert - Reset
+	subject.Reset()
+	_ = subject.AddCounted(errors.New("connection refused"))
+	assertEqual(t, "connection refused", subject.Error())
+}
+
+func testMultiErrorAddCountedWithDedupKey(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	subject := xerr.NewMultiError(xerr.WithDedupKey(func(err error) any {
+		return strings.SplitN(err.Error(), ":", 2)[0]
+	}))
+
+	// act
+	_ = subject.AddCounted(
+		errors.New("dial tcp: connection refused"),
+		errors.New("dial tcp: i/o timeout"),
+		errors.New("read: EOF"),
+	)
+
+	// assert
+	assertEqual(t, "dial tcp: connection refused (x2)\nread: EOF", subject.Error())
+}
+
+func testMultiErrorAddCountedWithRing(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	subject := xerr.NewMultiErrorRing(1)
+
+	// act
+	_ = subject.AddCounted(io.EOF, io.EOF, io.ErrUnexpectedEOF, io.EOF)
+
+	// assert - dropped counted errors are not counted anymore
+	assertEqual(t, "EOF", subject.Error())
+	assertEqual(t, 3, subject.Total())
+}
+
+func testMultiErrorAddCountedConcurrency(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		subject      = xerr.NewMultiError()
+		goroutinesNo = 20
+		errsPerG     = 50
+		wg           sync.WaitGroup
+	)
+
+	// act
+	for i := 0; i < goroutinesNo; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < errsPerG; j++ {
+				_ = subject.AddCounted(io.ErrUnexpectedEOF)
+				_ = subject.Error()
+			}
+		}()
+	}
+	wg.Wait()
+
+	// assert
+	assertEqual(t, 1, subject.Len())
+	assertEqual(t, goroutinesNo*errsPerG, xerr.CountOf(subject.First()))
+}
@@ -0,0 +1,246 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+)
+
+// MultiErrorFormatter is an alias for a function that writes the stored errors
+// of a [MultiError], for given formatting verb.
+// It is also used for [MultiError.Error], with verb 's'.
+// An individual error can be written honoring the verb and flags with:
+//
+//	fmt.Fprintf(f, fmt.FormatString(f, verb), err)
+type MultiErrorFormatter func(f fmt.State, verb rune, errs []error)
+
+// multiErrorFormatter is the globally configured MultiError formatter.
+// If nil, the default layout is used.
+var multiErrorFormatter MultiErrorFormatter
+
+// SetMultiErrorFormatter configures the function this package uses
+// in order to write a [MultiError], replacing the default layout,
+// which writes the errors new line separated, each of them being prefixed
+// with "error #N" line, for %v verb (warnings being written last, prefixed
+// with "warning #N" line).
+// Pass nil to restore the default layout.
+// You will call it usually somewhere in the bootstrap process of your
+// application. For example:
+//
+//	// myapp/bootstrap.go
+//	func init() {
+//		xerr.SetMultiErrorFormatter(xerr.MultiErrorLayout{
+//			Header: func(n int) string { return strconv.Itoa(n) + " errors occurred:\n" },
+//			Prefix: func(int) string { return "  - " },
+//			Separator: "\n",
+//		}.Formatter())
+//	}
+func SetMultiErrorFormatter(fn MultiErrorFormatter) {
+	multiErrorFormatter = fn
+	multiErrorFormatterGen++
+}
+
+// WithMultiErrorFormatter configures the [MultiErrorFormatter] to be used
+// by a MultiError, instead of the globally configured one (see [SetMultiErrorFormatter]).
+func WithMultiErrorFormatter(fn MultiErrorFormatter) MultiErrorOption {
+	return func(mErr *MultiError) {
+		mErr.formatter = fn
+	}
+}
+
+// MultiErrorLayout describes a common [MultiError] layout:
+//
+//	<Header><Prefix(0)><error #1><Separator><Prefix(1)><error #2>...<Footer>
+//
+// All parts are optional.
+type MultiErrorLayout struct {
+	// Header returns the text written before the errors,
+	// given the number of errors. Example: "3 errors occurred:\n".
+	Header func(n int) string
+	// Prefix returns the text written before each error,
+	// given its index (0 based). Example: "error #1: ".
+	Prefix func(idx int) string
+	// Separator is the text written between errors.
+	Separator string
+	// Footer is the text written after the errors.
+	Footer string
+}
+
+// Formatter returns the [MultiErrorFormatter] writing the errors with this layout.
+func (layout MultiErrorLayout) Formatter() MultiErrorFormatter {
+	return func(f fmt.State, verb rune, errs []error) {
+		if layout.Header != nil {
+			_, _ = io.WriteString(f, layout.Header(len(errs)))
+		}
+		for idx, err := range errs {
+			if idx > 0 {
+				_, _ = io.WriteString(f, layout.Separator)
+			}
+			if layout.Prefix != nil {
+				_, _ = io.WriteString(f, layout.Prefix(idx))
+			}
+			formatError(f, verb, err)
+		}
+		_, _ = io.WriteString(f, layout.Footer)
+	}
+}
+
+// formatMultiError writes the errors with the default layout.
+// For %v verb, if there are warnings (see [MultiError.AddWarning]), they are
+// written after the other errors, as a separate group.
+func formatMultiError(f fmt.State, verb rune, errs []error) {
+	if verb != 'v' {
+		for idx, err := range errs {
+			if idx > 0 {
+				_, _ = io.WriteString(f, "\n")
+			}
+			formatError(f, verb, err)
+		}
+
+		return
+	}
+
+	var errorNo, warningNo int
+	for _, warnings := range [...]bool{false, true} {
+		for _, err := range errs {
+			if isWarning(err) != warnings {
+				continue
+			}
+			if errorNo+warningNo > 0 {
+				_, _ = io.WriteString(f, "\n")
+			}
+			if warnings {
+				warningNo++
+				_, _ = io.WriteString(f, "warning #")
+				_, _ = io.WriteString(f, strconv.FormatInt(int64(warningNo), 10))
+			} else {
+				errorNo++
+				_, _ = io.WriteString(f, "error #")
+				_, _ = io.WriteString(f, strconv.FormatInt(int64(errorNo), 10))
+			}
+			_, _ = io.WriteString(f, "\n")
+			formatError(f, verb, err)
+		}
+	}
+}
+
+// stringState is a [fmt.State] with no flags, width or precision, writing
+// into a buffer. It is used to obtain the message of a custom formatted MultiError.
+type stringState struct {
+	bytes.Buffer
+}
+
+// Width implements [fmt.State].
+func (*stringState) Width() (int, bool) { return 0, false }
+
+// Precision implements [fmt.State].
+func (*stringState) Precision() (int, bool) { return 0, false }
+
+// Flag implements [fmt.State].
+func (*stringState) Flag(int) bool { return false }
+
+// multiErrorFormatterGen is incremented every time the global MultiError formatter
+// is changed, so that MultiErrors' memoized renderings get refreshed.
+var multiErrorFormatterGen uint64
+
+// Kinds of memoized MultiError renderings.
+const (
+	renderError = iota // Error().
+	renderS            // Format() with plain %s.
+	renderV            // Format() with plain %v.
+)
+
+// multiErrorRender holds the memoized renderings of a MultiError.
+// It is immutable, a new one is stored for every new rendering.
+type multiErrorRender struct {
+	// formatterGen is the multiErrorFormatterGen the renderings were made with.
+	formatterGen uint64
+	// volatile is true if a stored error's message may change
+	// (see [hasImmutableMessage]), case in which renderings are not memoized.
+	volatile bool
+	// texts holds the renderings, indexed by their kind, if already made.
+	texts [3]*string
+}
+
+// memoized returns the rendering of given kind, made with given function
+// and memoized, if not already memoized.
+// Caller must hold the read lock.
+func (mErr *MultiError) memoized(kind int, render func() string) string {
+	cache := mErr.rendered.Load()
+	if cache == nil || cache.formatterGen != multiErrorFormatterGen {
+		cache = &multiErrorRender{formatterGen: multiErrorFormatterGen}
+		for _, err := range mErr.errors {
+			if !hasImmutableMessage(err) {
+				cache.volatile = true
+
+				break
+			}
+		}
+	} else if text := cache.texts[kind]; text != nil {
+		return *text
+	}
+
+	text := render()
+	if !cache.volatile {
+		newCache := *cache
+		newCache.texts[kind] = &text
+		cache = &newCache
+	}
+	mErr.rendered.Store(cache)
+
+	return text
+}
+
+// immutableStdErrorTypes holds the types of the standard library errors
+// whose messages never change, see [hasImmutableMessage].
+var immutableStdErrorTypes = map[reflect.Type]struct{}{
+	reflect.TypeOf(errors.New("")):                     {}, // also fmt.Errorf without %w.
+	reflect.TypeOf(fmt.Errorf("%w", io.EOF)):           {},
+	reflect.TypeOf(fmt.Errorf("%w%w", io.EOF, io.EOF)): {},
+	reflect.TypeOf(errors.Join(io.EOF)):                {},
+}
+
+// hasImmutableMessage returns true if err's chain is made only of errors whose
+// messages (and formatting) never change: this package's errors, and the errors
+// made with [errors.New], [fmt.Errorf] and [errors.Join].
+// It returns false if err's chain contains a [MultiError], as errors can be added
+// to it anytime, an error stored with [MultiError.AddCounted], as its occurrences
+// can increase, a [FieldViolation], as its fields are exported, or any other
+// (foreign) error, as its message may change (like a third party aggregate error's).
+func hasImmutableMessage(err error) bool {
+	return walkChain(err, func(e error) bool {
+		switch e.(type) {
+		case *stackError, *valueError, *namedError, *markedError, *remoteError, *Definition, Const:
+			return true
+		default:
+			_, found := immutableStdErrorTypes[reflect.TypeOf(e)]
+
+			return found
+		}
+	})
+}
+
+// isPlainFormat returns true if no flag, width or precision is set.
+func isPlainFormat(f fmt.State) bool {
+	if _, ok := f.Width(); ok {
+		return false
+	}
+	if _, ok := f.Precision(); ok {
+		return false
+	}
+	for _, flag := range "+-# 0" {
+		if f.Flag(int(flag)) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,206 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/actforgood/xerr"
+)
+
+func TestSetMultiErrorFormatter(t *testing.T) {
+	// test is not parallel as it changes global configuration.
+
+	// arrange
+	xerr.SetMultiErrorFormatter(xerr.MultiErrorLayout{
+		Header:    func(n int) string { return strconv.Itoa(n) + " errors occurred:\n" },
+		Prefix:    func(idx int) string { return "  " + strconv.Itoa(idx+1) + ". " },
+		Separator: "\n",
+	}.Formatter())
+	defer xerr.SetMultiErrorFormatter(nil)
+	subject := xerr.NewMultiError().Add(io.EOF, io.ErrUnexpectedEOF)
+	expected := "2 errors occurred:\n  1. EOF\n  2. unexpected EOF"
+
+	// act & assert
+	assertEqual(t, expected, subject.Error())
+	assertEqual(t, expected, fmt.Sprintf("%s", subject))
+	assertEqual(t, expected, fmt.Sprintf("%v", subject))
+
+	// act & assert - empty MultiError is not formatted
+	assertEqual(t, "", xerr.NewMultiError().Error())
+	assertEqual(t, "", fmt.Sprintf("%v", xerr.NewMultiError()))
+
+	// act & assert - default is restored
+	xerr.SetMultiErrorFormatter(nil)
+	assertEqual(t, "EOF\nunexpected EOF", subject.Error())
+	assertEqual(t, "error #1\nEOF\nerror #2\nunexpected EOF", fmt.Sprintf("%v", subject))
+}
+
+func TestWithMultiErrorFormatter(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	formatter := func(f fmt.State, verb rune, errs []error) {
+		msgs := make([]string, 0, len(errs))
+		for _, err := range errs {
+			msgs = append(msgs, fmt.Sprintf(fmt.FormatString(f, verb), err))
+		}
+		_, _ = io.WriteString(f, "["+strings.Join(msgs, "; ")+"]")
+	}
+	subject := xerr.NewMultiError(xerr.WithMultiErrorFormatter(formatter)).
+		Add(io.EOF, xerr.NewOpt("not found", xerr.WithNoStack()))
+
+	// act & assert
+	assertEqual(t, "[EOF; not found]", subject.Error())
+	assertEqual(t, "[EOF; not found]", fmt.Sprintf("%+v", subject))
+	filtered := subject.Filter(func(err error) bool { return !errors.Is(err, io.EOF) })
+	assertEqual(t, "[not found]", filtered.Error())
+}
+
+func TestMultiErrorLayout(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		subject = xerr.MultiErrorLayout{
+			Separator: ", ",
+			Footer:    ".",
+		}.Formatter()
+		mErr = xerr.NewMultiError(xerr.WithMultiErrorFormatter(subject)).
+			Add(io.EOF, io.ErrUnexpectedEOF, io.ErrShortWrite)
+	)
+
+	// act
+	result := mErr.Error()
+
+	// assert
+	assertEqual(t, "EOF, unexpected EOF, short write.", result)
+}
+
+func TestMultiError_memoizedRendering(t *testing.T) {
+	t.Parallel()
+
+	t.Run("changes are reflected", testMultiErrorMemoizedRenderingChanges)
+	t.Run("nested MultiError changes are reflected", testMultiErrorMemoizedRenderingNested)
+	t.Run("foreign mutable error changes are reflected", testMultiErrorMemoizedRenderingForeign)
+}
+
+func testMultiErrorMemoizedRenderingChanges(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	subject := xerr.NewMultiError().Add(io.EOF)
+
+	// act & assert
+	assertEqual(t, "EOF", subject.Error())
+	assertEqual(t, "error #1\nEOF", fmt.Sprintf("%v", subject))
+	_ = subject.Add(io.ErrUnexpectedEOF)
+	assertEqual(t, "EOF\nunexpected EOF", subject.Error())
+	assertEqual(t, "EOF\nunexpected EOF", fmt.Sprintf("%s", subject))
+	assertEqual(t, "error #1\nEOF\nerror #2\nunexpected EOF", fmt.Sprintf("%v", subject))
+	_ = subject.AddOnce(io.ErrShortWrite)
+	assertEqual(t, "EOF\nunexpected EOF\nshort write", subject.Error())
+	subject.Reset()
+	_ = subject.Add(io.ErrClosedPipe)
+	assertEqual(t, "io: read/write on closed pipe", subject.Error())
+	assertEqual(t, "error #1\nio: read/write on closed pipe", fmt.Sprintf("%v", subject))
+}
+
+func testMultiErrorMemoizedRenderingNested(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		nested  = xerr.NewMultiError().Add(io.EOF)
+		wrapped = xerr.Wrap(nested, "nested")
+		subject = xerr.NewMultiError().Add(nested)
+	)
+
+	// act & assert
+	assertEqual(t, "EOF", subject.Error())
+	assertEqual(t, "nested: EOF", wrapped.Error())
+	_ = nested.Add(io.ErrUnexpectedEOF)
+	assertEqual(t, "EOF\nunexpected EOF", subject.Error())
+	assertEqual(t, "nested: EOF\nunexpected EOF", wrapped.Error())
+}
+
+// mutableAggErr is a foreign (third party like) aggregate error,
+// to which errors can be appended, changing its message.
+type mutableAggErr struct {
+	mu   sync.Mutex
+	errs []error
+}
+
+func (aggErr *mutableAggErr) Append(err error) {
+	aggErr.mu.Lock()
+	aggErr.errs = append(aggErr.errs, err)
+	aggErr.mu.Unlock()
+}
+
+func (aggErr *mutableAggErr) Error() string {
+	aggErr.mu.Lock()
+	defer aggErr.mu.Unlock()
+
+	msgs := make([]string, len(aggErr.errs))
+	for idx, err := range aggErr.errs {
+		msgs[idx] = err.Error()
+	}
+
+	return fmt.Sprintf("%d errors occurred: %s", len(msgs), strings.Join(msgs, "; "))
+}
+
+func testMultiErrorMemoizedRenderingForeign(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		aggErr  = new(mutableAggErr)
+		subject = xerr.NewMultiError().Add(io.EOF, aggErr)
+	)
+	aggErr.Append(io.ErrUnexpectedEOF)
+
+	// act & assert
+	assertEqual(t, "EOF\n1 errors occurred: unexpected EOF", subject.Error())
+	assertEqual(t, "EOF\n1 errors occurred: unexpected EOF", fmt.Sprintf("%s", subject))
+	aggErr.Append(io.ErrShortWrite)
+	assertEqual(t, "EOF\n2 errors occurred: unexpected EOF; short write", subject.Error())
+	assertEqual(t, "EOF\n2 errors occurred: unexpected EOF; short write", fmt.Sprintf("%s", subject))
+}
+
+func TestMultiError_memoizedRendering_noAllocs(t *testing.T) {
+	// test is not parallel as testing.AllocsPerRun requires it.
+
+	// arrange
+	subject := xerr.NewMultiError().Add(io.EOF, xerr.Wrap(io.ErrUnexpectedEOF, "read"))
+	_ = subject.Error()
+
+	// act
+	allocs := testing.AllocsPerRun(100, func() {
+		_ = subject.Error()
+	})
+
+	// assert
+	assertEqual(t, 0.0, allocs)
+}
+
+func BenchmarkMultiError_Error(b *testing.B) {
+	mErr := xerr.NewMultiError()
+	for i := 0; i < 100; i++ {
+		_ = mErr.Add(xerr.Wrap(io.ErrUnexpectedEOF, "err #"+strconv.Itoa(i)))
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for n := 0; n < b.N; n++ {
+		_ = mErr.Error()
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+//go:build go1.23
+
+package xerr
+
+import "iter"
+
+// All returns an iterator over stored errors, in the order they were added.
+// Unlike [MultiError.Errors], stored errors are not copied.
+// The lock is not held while yielding, so errors can be added
+// to this MultiError from within the loop (they will be iterated too).
+//
+// Example:
+//
+//	for err := range multiErr.All() {
+//		log.Println(err)
+//	}
+func (mErr *MultiError) All() iter.Seq[error] {
+	return func(yield func(error) bool) {
+		for idx := 0; ; idx++ {
+			err := mErr.at(idx)
+			if err == nil || !yield(err) {
+				return
+			}
+		}
+	}
+}
+
+// AllIndexed returns an iterator over stored errors and their indexes,
+// in the order they were added. See also [MultiError.All].
+//
+// Example:
+//
+//	for idx, err := range multiErr.AllIndexed() {
+//		log.Printf("error #%d: %v", idx+1, err)
+//	}
+func (mErr *MultiError) AllIndexed() iter.Seq2[int, error] {
+	return func(yield func(int, error) bool) {
+		for idx := 0; ; idx++ {
+			err := mErr.at(idx)
+			if err == nil || !yield(idx, err) {
+				return
+			}
+		}
+	}
+}
+
+// at returns the stored error at given index, or nil if index is out of range.
+func (mErr *MultiError) at(idx int) error {
+	if mErr == nil {
+		return nil
+	}
+	mErr.rLock()
+	defer mErr.rUnlock()
+
+	if idx >= len(mErr.errors) {
+		return nil
+	}
+
+	return mErr.errors[idx]
+}
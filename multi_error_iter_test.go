@@ -0,0 +1,115 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+//go:build go1.23
+
+package xerr_test
+
+import (
+	"io"
+	"testing"
+
+	"github.com/actforgood/xerr"
+)
+
+func TestMultiError_All(t *testing.T) {
+	t.Parallel()
+
+	t.Run("all errors are iterated", testMultiErrorAllIteratesAll)
+	t.Run("break stops iteration", testMultiErrorAllBreak)
+	t.Run("errors added while iterating", testMultiErrorAllAddWhileIterating)
+	t.Run("not initialized", testMultiErrorAllNotInitialized)
+}
+
+func testMultiErrorAllIteratesAll(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	subject := xerr.NewMultiError().Add(io.EOF, io.ErrUnexpectedEOF, io.ErrShortWrite)
+	var (
+		errs    []error
+		indexes []int
+	)
+
+	// act
+	for err := range subject.All() {
+		errs = append(errs, err)
+	}
+	for idx := range subject.AllIndexed() {
+		indexes = append(indexes, idx)
+	}
+
+	// assert
+	assertEqual(t, []error{io.EOF, io.ErrUnexpectedEOF, io.ErrShortWrite}, errs)
+	assertEqual(t, []int{0, 1, 2}, indexes)
+}
+
+func testMultiErrorAllBreak(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	subject := xerr.NewMultiError().Add(io.EOF, io.ErrUnexpectedEOF, io.ErrShortWrite)
+	var (
+		errs    []error
+		indexes []int
+	)
+
+	// act
+	for err := range subject.All() {
+		errs = append(errs, err)
+		if err == io.ErrUnexpectedEOF {
+			break
+		}
+	}
+	for idx := range subject.AllIndexed() {
+		indexes = append(indexes, idx)
+
+		break
+	}
+
+	// assert
+	assertEqual(t, []error{io.EOF, io.ErrUnexpectedEOF}, errs)
+	assertEqual(t, []int{0}, indexes)
+}
+
+func testMultiErrorAllAddWhileIterating(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	subject := xerr.NewMultiError().Add(io.EOF)
+	var errs []error
+
+	// act
+	for err := range subject.All() {
+		errs = append(errs, err)
+		if err == io.EOF {
+			_ = subject.Add(io.ErrUnexpectedEOF) // should not deadlock
+		}
+	}
+
+	// assert
+	assertEqual(t, []error{io.EOF, io.ErrUnexpectedEOF}, errs)
+}
+
+func testMultiErrorAllNotInitialized(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		subject *xerr.MultiError
+		count   int
+	)
+
+	// act
+	for range subject.All() {
+		count++
+	}
+	for range subject.AllIndexed() {
+		count++
+	}
+
+	// assert
+	assertEqual(t, 0, count)
+}
@@ -9,8 +9,11 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"os"
 	"regexp"
+	"runtime"
 	"strconv"
+	"strings"
 	"sync"
 	"testing"
 
@@ -215,6 +218,254 @@ func TestMultiError_Reset(t *testing.T) {
 	assertEqual(t, 0, len(subject.Errors()))
 }
 
+func TestMultiError_Len_First_Last(t *testing.T) {
+	t.Parallel()
+
+	// act & assert - subject not initialized
+	var subject *xerr.MultiError
+	assertEqual(t, 0, subject.Len())
+	assertEqual(t, 0, subject.Total())
+	assertNil(t, subject.First())
+	assertNil(t, subject.Last())
+
+	// act & assert - subject is initialized, has no errors
+	subject = xerr.NewMultiError()
+	assertEqual(t, 0, subject.Len())
+	assertNil(t, subject.First())
+	assertNil(t, subject.Last())
+
+	// act & assert - subject with 1 error
+	subject.Add(io.EOF)
+	assertEqual(t, 1, subject.Len())
+	assertEqual(t, io.EOF, subject.First())
+	assertEqual(t, io.EOF, subject.Last())
+
+	// act & assert - subject with errors
+	subject.Add(io.ErrUnexpectedEOF, io.ErrShortWrite)
+	assertEqual(t, 3, subject.Len())
+	assertEqual(t, 3, subject.Total())
+	assertEqual(t, io.EOF, subject.First())
+	assertEqual(t, io.ErrShortWrite, subject.Last())
+}
+
+func TestMultiError_AddNamed(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var subject *xerr.MultiError
+
+	// act
+	subject = subject.AddNamed("field1", nil)
+	assertNil(t, subject)
+	subject = subject.
+		AddNamed("field1", io.ErrUnexpectedEOF).
+		Add(io.EOF).
+		AddNamed("shard #2", xerr.NewOpt("shard failed", xerr.WithNoStack()))
+
+	// assert
+	assertEqual(t, "field1: unexpected EOF\nEOF\nshard #2: shard failed", subject.Error())
+	assertEqual(t, "field1: unexpected EOF\nEOF\nshard #2: shard failed", fmt.Sprintf("%s", subject))
+	assertEqual(
+		t,
+		"error #1\nfield1: unexpected EOF\nerror #2\nEOF\nerror #3\nshard #2: shard failed",
+		fmt.Sprintf("%v", subject),
+	)
+	errs := subject.Errors()
+	if assertEqual(t, 3, len(errs)) {
+		assertEqual(t, "field1", xerr.NameOf(errs[0]))
+		assertTrue(t, errors.Is(errs[0], io.ErrUnexpectedEOF))
+		assertEqual(t, "", xerr.NameOf(errs[1]))
+		assertEqual(t, "shard #2", xerr.NameOf(xerr.Wrap(errs[2], "wrapped")))
+	}
+	assertTrue(t, errors.Is(subject, io.ErrUnexpectedEOF))
+}
+
+func TestMultiError_ErrOrNilIf(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var subject *xerr.MultiError
+
+	// act & assert - subject not initialized
+	assertNil(t, subject.ErrOrNilIf(0))
+
+	// act & assert - threshold not exceeded
+	subject = subject.Add(io.EOF, io.ErrUnexpectedEOF)
+	assertNil(t, subject.ErrOrNilIf(2))
+
+	// act & assert - threshold exceeded
+	err := subject.ErrOrNilIf(1)
+	if assertNotNil(t, err) {
+		assertEqual(t, "2 failures exceed the threshold of 1: EOF\nunexpected EOF", err.Error())
+		assertTrue(t, errors.Is(err, io.ErrUnexpectedEOF))
+		assertEqual(t, map[string]any{"failures": 2, "failures_threshold": 1}, xerr.Fields(err))
+	}
+
+	// act & assert - single error keeps its stack trace
+	stackErr := xerr.New("stack err")
+	err = xerr.NewMultiError().Add(stackErr).ErrOrNilIf(-1)
+	if assertNotNil(t, err) {
+		assertEqual(t, "1 failures exceed the threshold of 0: stack err", err.Error())
+		assertEqual(t, xerr.Frames(stackErr), xerr.Frames(err))
+	}
+
+	// act & assert - negative threshold is treated as 0
+	err = xerr.NewMultiError().Add(io.EOF).ErrOrNilIf(-3)
+	if assertNotNil(t, err) {
+		assertEqual(t, "1 failures exceed the threshold of 0: EOF", err.Error())
+		assertEqual(t, map[string]any{"failures": 1, "failures_threshold": 0}, xerr.Fields(err))
+	}
+
+	// act & assert - stored errors are a snapshot, agreeing with the count
+	err = subject.ErrOrNilIf(1)
+	_ = subject.Add(io.ErrClosedPipe)
+	if assertNotNil(t, err) {
+		assertEqual(t, "2 failures exceed the threshold of 1: EOF\nunexpected EOF", err.Error())
+		assertFalse(t, errors.Is(err, io.ErrClosedPipe))
+	}
+}
+
+func TestMultiError_ErrOrNilRatio(t *testing.T) {
+	t.Parallel()
+
+	tests := [...]struct {
+		name            string
+		errs            []error
+		maxFailureRatio float64
+		total           int
+		expectedMsg     string
+		expectedFields  map[string]any
+	}{
+		{
+			name:            "no errors",
+			maxFailureRatio: 0,
+			total:           10,
+		},
+		{
+			name:            "ratio not exceeded",
+			errs:            []error{io.EOF},
+			maxFailureRatio: 0.1,
+			total:           10,
+		},
+		{
+			name:            "ratio exceeded",
+			errs:            []error{io.EOF, io.ErrUnexpectedEOF},
+			maxFailureRatio: 0.1,
+			total:           10,
+			expectedMsg:     "2 of 10 failed, exceeding the max failure ratio of 0.1: EOF\nunexpected EOF",
+			expectedFields:  map[string]any{"failures": 2, "failures_total": 10, "max_failure_ratio": 0.1},
+		},
+		{
+			name:            "total smaller than failures",
+			errs:            []error{io.EOF},
+			maxFailureRatio: 0.5,
+			total:           0,
+			expectedMsg:     "1 of 1 failed, exceeding the max failure ratio of 0.5: EOF",
+			expectedFields:  map[string]any{"failures": 1, "failures_total": 1, "max_failure_ratio": 0.5},
+		},
+	}
+
+	for _, testData := range tests {
+		test := testData // capture range variable
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			// arrange
+			var subject *xerr.MultiError
+			subject = subject.Add(test.errs...)
+
+			// act
+			err := subject.ErrOrNilRatio(test.maxFailureRatio, test.total)
+
+			// assert
+			if test.expectedMsg == "" {
+				assertNil(t, err)
+			} else if assertNotNil(t, err) {
+				assertEqual(t, test.expectedMsg, err.Error())
+				assertEqual(t, test.expectedFields, xerr.Fields(err))
+			}
+		})
+	}
+}
+
+func TestMultiError_AddWarning(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var subject *xerr.MultiError
+
+	// act & assert - subject not initialized
+	assertFalse(t, subject.HasFatal())
+	assertNil(t, subject.Warnings())
+	subject = subject.AddWarning(nil)
+	assertNil(t, subject)
+
+	// act & assert - only warnings
+	subject = subject.AddWarning(io.EOF)
+	assertFalse(t, subject.HasFatal())
+	assertEqual(t, "warning #1\nEOF", fmt.Sprintf("%v", subject))
+
+	// act & assert - warnings and failures
+	subject = subject.Add(io.ErrUnexpectedEOF).AddWarning(io.ErrShortWrite)
+	assertTrue(t, subject.HasFatal())
+	warnings := subject.Warnings()
+	if assertEqual(t, 2, len(warnings)) {
+		assertTrue(t, errors.Is(warnings[0], io.EOF))
+		assertEqual(t, xerr.SeverityWarn, xerr.SeverityOf(warnings[0]))
+		assertTrue(t, errors.Is(warnings[1], io.ErrShortWrite))
+	}
+	assertEqual(t, "EOF\nunexpected EOF\nshort write", subject.Error())
+	assertEqual(
+		t,
+		"error #1\nunexpected EOF\nwarning #1\nEOF\nwarning #2\nshort write",
+		fmt.Sprintf("%v", subject),
+	)
+}
+
+func TestNewMultiErrorRing(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		subject = xerr.NewMultiErrorRing(3)
+		errs    = make([]error, 10)
+	)
+	for idx := range errs {
+		errs[idx] = errors.New("err " + strconv.Itoa(idx))
+	}
+
+	// act & assert - fewer errors than ring's size
+	_ = subject.Add(errs[0], errs[1])
+	assertEqual(t, []error{errs[0], errs[1]}, subject.Errors())
+	assertEqual(t, 2, subject.Total())
+
+	// act & assert - oldest errors get dropped
+	_ = subject.Add(errs[2:6]...)
+	assertEqual(t, []error{errs[3], errs[4], errs[5]}, subject.Errors())
+	assertEqual(t, 3, subject.Len())
+	assertEqual(t, 6, subject.Total())
+
+	// act & assert - AddOnce deduplicates against retained errors
+	_ = subject.AddOnce(errs[5], errs[0])
+	assertEqual(t, []error{errs[4], errs[5], errs[0]}, subject.Errors())
+	assertEqual(t, 7, subject.Total())
+
+	// act & assert - Merge
+	shards := subject.Shard(2)
+	_ = shards[0].Add(errs[6], errs[7])
+	_ = shards[1].Add(errs[8])
+	_ = subject.Merge(shards...)
+	assertEqual(t, []error{errs[6], errs[7], errs[8]}, subject.Errors())
+	assertEqual(t, 10, subject.Total())
+
+	// act & assert - Reset
+	subject.Reset()
+	_ = subject.Add(errs[9])
+	assertEqual(t, []error{errs[9]}, subject.Errors())
+	assertEqual(t, 1, subject.Total())
+	assertEqual(t, 1, xerr.NewMultiErrorRing(0).Add(errs[0], errs[1]).Len())
+}
+
 func TestMultiError_Unwrap_Is(t *testing.T) {
 	t.Parallel()
 
@@ -234,6 +485,54 @@ func TestMultiError_Unwrap_Is(t *testing.T) {
 	assertTrue(t, errors.Is(subject, io.ErrShortWrite))
 }
 
+func TestMultiError_Unwrap(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		subject    = xerr.NewMultiError()
+		customErr  = dummyCustomErr{}
+		extractErr dummyCustomErr
+	)
+
+	// act & assert - no errors
+	assertNil(t, subject.Unwrap())
+	assertNil(t, (*xerr.MultiError)(nil).Unwrap())
+
+	// act & assert - errors stored
+	_ = subject.Add(io.ErrUnexpectedEOF, xerr.Wrap(fmt.Errorf("wrap: %w", customErr), "wrap"))
+	errs := subject.Unwrap()
+	assertEqual(t, subject.Errors(), errs)
+	errs[0] = nil // see we got a copy
+	assertEqual(t, io.ErrUnexpectedEOF, subject.Errors()[0])
+	assertNil(t, errors.Unwrap(subject))
+	assertTrue(t, errors.Is(subject, customErr))
+	assertTrue(t, errors.As(subject, &extractErr))
+	assertTrue(t, errors.Is(xerr.Wrap(subject, "wrap"), customErr))
+}
+
+func TestMultiError_As(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		subject    = xerr.NewMultiError()
+		customErr  = dummyCustomErr{}
+		extractErr dummyCustomErr
+		pathErr    *os.PathError
+	)
+
+	// act & assert - no errors
+	assertFalse(t, subject.As(&extractErr))
+	assertFalse(t, (*xerr.MultiError)(nil).As(&extractErr))
+
+	// act & assert - typed error is not the first one
+	_ = subject.Add(io.ErrUnexpectedEOF, fmt.Errorf("wrap: %w", customErr))
+	assertTrue(t, subject.As(&extractErr))
+	assertEqual(t, customErr, extractErr)
+	assertFalse(t, subject.As(&pathErr))
+}
+
 func TestMultiError_concurrency(t *testing.T) {
 	t.Parallel()
 
@@ -279,6 +578,311 @@ func TestMultiError_concurrency(t *testing.T) {
 	assertEqual(t, goroutinesNo*(goroutinesNo+1)/2, sum)
 }
 
+func TestMultiError_Shard_Merge(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		subject      = xerr.NewMultiError()
+		goroutinesNo = 4
+		errsPerShard = 50
+		wg           sync.WaitGroup
+	)
+	_ = subject.Add(io.ErrUnexpectedEOF)
+
+	// act
+	shards := subject.Shard(goroutinesNo)
+	for i := 0; i < goroutinesNo; i++ {
+		wg.Add(1)
+		go func(shard *xerr.MultiError, shardNo int) {
+			defer wg.Done()
+			for j := 0; j < errsPerShard; j++ {
+				_ = shard.Add(errors.New("err from shard " + strconv.Itoa(shardNo)))
+			}
+		}(shards[i], i)
+	}
+	wg.Wait()
+	result := subject.Merge(shards...)
+
+	// assert
+	assertTrue(t, result == subject)
+	errs := subject.Errors()
+	if assertEqual(t, 1+goroutinesNo*errsPerShard, len(errs)) {
+		assertEqual(t, io.ErrUnexpectedEOF, errs[0])
+		assertEqual(t, "err from shard 0", errs[1].Error())
+		assertEqual(t, "err from shard 3", errs[len(errs)-1].Error())
+	}
+	for _, shard := range shards {
+		assertEqual(t, 0, len(shard.Errors()))
+	}
+}
+
+func TestMultiError_Merge_notInitialized(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var subject *xerr.MultiError
+	shards := subject.Shard(2)
+	_ = shards[1].Add(io.ErrUnexpectedEOF)
+
+	// act
+	result := subject.Merge(shards...)
+
+	// assert
+	if assertNotNil(t, result) {
+		assertEqual(t, []error{io.ErrUnexpectedEOF}, result.Errors())
+	}
+	assertEqual(t, 0, len(subject.Shard(-1)))
+	assertNil(t, subject.Merge(xerr.NewMultiError(), nil))
+}
+
+func TestMultiError_Merge_crossed(t *testing.T) {
+	// test is not parallel as it changes GOMAXPROCS, for the merges to run in parallel.
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+
+	// arrange
+	var (
+		subject1 = xerr.NewMultiError()
+		subject2 = xerr.NewMultiError()
+		merges   = 10000
+		wg       sync.WaitGroup
+	)
+
+	// act
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < merges; i++ {
+			_ = subject2.Add(io.EOF)
+			_ = subject1.Merge(subject2)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < merges; i++ {
+			_ = subject1.Add(io.ErrUnexpectedEOF)
+			_ = subject2.Merge(subject1)
+		}
+	}()
+	wg.Wait()
+
+	// assert
+	assertEqual(t, 2*merges, subject1.Len()+subject2.Len())
+}
+
+func TestMultiError_wrappingItself(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		subject = xerr.NewMultiError().Add(io.EOF)
+		shards  = subject.Shard(1)
+	)
+	_ = shards[0].Add(xerr.Wrap(subject, "wrapped in shard"), io.ErrClosedPipe)
+
+	// act
+	_ = subject.Add(subject, xerr.Wrap(subject, "wrapped"), io.ErrUnexpectedEOF)
+	_ = subject.AddOnce(fmt.Errorf("wrapped: %w", subject))
+	_ = subject.AddCounted(xerr.NewMultiError().Add(subject))
+	_ = subject.AddNamed("named", subject)
+	_ = subject.AddWarning(xerr.Wrap(subject, "warning"))
+	_ = subject.Merge(shards...)
+
+	// assert
+	errs := subject.Errors()
+	if assertEqual(t, 9, len(errs)) {
+		assertEqual(t, io.EOF, errs[0])
+		assertEqual(t, io.ErrUnexpectedEOF, errs[3])
+		assertEqual(t, io.ErrClosedPipe, errs[8])
+		for _, idx := range []int{1, 2, 4, 5, 6, 7} { // AddOnce is deduplicated.
+			assertTrue(t, errors.Is(errs[idx], xerr.ErrCyclicMultiError))
+		}
+	}
+	assertTrue(t, strings.HasPrefix(subject.Error(), "EOF\n"+xerr.ErrCyclicMultiError.Error()+"\n"))
+	assertEqual(t, 3, xerr.Depth(xerr.Wrap(subject, "wrapped")))
+}
+
+// codeErr is an error matching other codeErr errors having the same code.
+type codeErr struct {
+	code string
+	msg  string
+}
+
+func (cErr *codeErr) Error() string { return cErr.msg }
+
+func (cErr *codeErr) Is(target error) bool {
+	var other *codeErr
+	if errors.As(target, &other) {
+		return other.code == cErr.code
+	}
+
+	return false
+}
+
+func TestMultiError_AddOnce(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		subject  = xerr.NewMultiError()
+		wrapped  = xerr.Wrap(io.ErrUnexpectedEOF, "read failed")
+		joined   = errors.Join(io.ErrShortWrite, io.ErrClosedPipe)
+		codeErr1 = &codeErr{code: "E1", msg: "first E1"}
+		codeErr2 = &codeErr{code: "E1", msg: "second E1"}
+		codeErr3 = &codeErr{code: "E2", msg: "first E2"}
+	)
+
+	// act
+	_ = subject.AddOnce(wrapped, joined, codeErr1)
+	_ = subject.AddOnce(
+		io.ErrUnexpectedEOF, // found in wrapped error's chain
+		io.ErrClosedPipe,    // found in joined errors
+		codeErr2,            // matched by codeErr1.Is
+		codeErr3,
+		wrapped,
+	)
+	_ = subject.Add(io.EOF)          // not indexed yet
+	_ = subject.AddOnce(io.EOF, nil) // stored errors added with Add are indexed too
+
+	// assert
+	assertEqual(t, []error{wrapped, joined, codeErr1, codeErr3, io.EOF}, subject.Errors())
+
+	// act & assert - index is dropped on Reset
+	subject.Reset()
+	_ = subject.AddOnce(io.ErrUnexpectedEOF)
+	assertEqual(t, []error{io.ErrUnexpectedEOF}, subject.Errors())
+}
+
+func TestMultiError_AddOnce_withDedupKey(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		subject = xerr.NewMultiError(xerr.WithDedupKey(func(err error) any {
+			return err.Error()
+		}))
+		err1 = errors.New("same message")
+		err2 = errors.New("same message")
+		err3 = errors.New("another message")
+	)
+
+	// act
+	_ = subject.AddOnce(err1, err2, err3)
+	_ = subject.AddOnce(xerr.Wrap(err1, "wrapped"), err3)
+
+	// assert
+	errs := subject.Errors()
+	if assertEqual(t, 3, len(errs)) {
+		assertTrue(t, errs[0] == err1)
+		assertTrue(t, errs[1] == err3)
+		assertEqual(t, "wrapped: same message", errs[2].Error())
+	}
+}
+
+func TestMultiError_AddOnce_afterMerge(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		subject = xerr.NewMultiError()
+		shards  = subject.Shard(1)
+	)
+	_ = shards[0].AddOnce(io.ErrUnexpectedEOF)
+
+	// act
+	_ = subject.Merge(shards...)
+	_ = shards[0].AddOnce(io.ErrUnexpectedEOF)
+	_ = subject.AddOnce(io.ErrUnexpectedEOF)
+
+	// assert
+	assertEqual(t, []error{io.ErrUnexpectedEOF}, subject.Errors())
+	assertEqual(t, []error{io.ErrUnexpectedEOF}, shards[0].Errors())
+}
+
+func TestMultiError_Filter_Map_Split(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Filter", testMultiErrorFilter)
+	t.Run("Map", testMultiErrorMap)
+	t.Run("Split", testMultiErrorSplit)
+	t.Run("not initialized", testMultiErrorFilterMapSplitNotInitialized)
+}
+
+func testMultiErrorFilter(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	subject := xerr.NewMultiError().Add(io.EOF, io.ErrUnexpectedEOF, io.ErrShortWrite)
+
+	// act
+	result := subject.Filter(func(err error) bool {
+		return err != io.ErrUnexpectedEOF
+	})
+
+	// assert
+	assertEqual(t, []error{io.EOF, io.ErrShortWrite}, result.Errors())
+	assertEqual(t, []error{io.EOF, io.ErrUnexpectedEOF, io.ErrShortWrite}, subject.Errors())
+	_ = result.AddOnce(io.EOF) // result is usable as any MultiError.
+	assertEqual(t, 2, len(result.Errors()))
+}
+
+func testMultiErrorMap(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	subject := xerr.NewMultiError().Add(io.EOF, io.ErrUnexpectedEOF)
+
+	// act
+	result := subject.Map(func(err error) error {
+		if err == io.EOF {
+			return nil
+		}
+
+		return fmt.Errorf("batch #1: %w", err)
+	})
+
+	// assert
+	errs := result.Errors()
+	if assertEqual(t, 1, len(errs)) {
+		assertEqual(t, "batch #1: unexpected EOF", errs[0].Error())
+		assertTrue(t, errors.Is(errs[0], io.ErrUnexpectedEOF))
+	}
+	assertEqual(t, []error{io.EOF, io.ErrUnexpectedEOF}, subject.Errors())
+}
+
+func testMultiErrorSplit(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var subject *xerr.MultiError
+	subject = subject.Add(io.EOF, io.ErrUnexpectedEOF, io.ErrShortWrite)
+
+	// act
+	match, rest := subject.Split(func(err error) bool {
+		return err == io.ErrUnexpectedEOF
+	})
+
+	// assert
+	assertEqual(t, []error{io.ErrUnexpectedEOF}, match.Errors())
+	assertEqual(t, []error{io.EOF, io.ErrShortWrite}, rest.Errors())
+	assertEqual(t, 3, len(subject.Errors()))
+}
+
+func testMultiErrorFilterMapSplitNotInitialized(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var subject *xerr.MultiError
+	pred := func(error) bool { return true }
+
+	// act & assert
+	assertNil(t, subject.Filter(pred))
+	assertNil(t, subject.Map(func(err error) error { return err }))
+	match, rest := subject.Split(pred)
+	assertNil(t, match)
+	assertNil(t, rest)
+}
+
 func BenchmarkMultiError_concurrentSafe(b *testing.B) {
 	var (
 		err  = errors.New("some error to be Added to MultiError")
@@ -324,3 +928,31 @@ func BenchmarkMultiError_Reset(b *testing.B) {
 		mErr.Reset()
 	}
 }
+
+func BenchmarkMultiError_AddOnce(b *testing.B) {
+	errs := make([]error, 1000)
+	for idx := range errs {
+		errs[idx] = errors.New("error #" + strconv.Itoa(idx))
+	}
+	mErr := xerr.NewMultiError()
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for n := 0; n < b.N; n++ {
+		_ = mErr.AddOnce(errs...)
+		mErr.Reset()
+	}
+}
+
+func BenchmarkMultiErrorRing_Add(b *testing.B) {
+	var (
+		err  = errors.New("some error to be Added to MultiError")
+		mErr = xerr.NewMultiErrorRing(100)
+	)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for n := 0; n < b.N; n++ {
+		_ = mErr.Add(err)
+	}
+}
@@ -0,0 +1,49 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr
+
+import "time"
+
+// Must returns v if err is nil, otherwise it panics with an error with stack trace
+// wrapping err (the stack trace starting with Must's caller).
+// It is meant for initialization code, where an error is not recoverable.
+//
+// Example:
+//
+//	var cfg = xerr.Must(config.Load("app.yml"))
+func Must[T any](v T, err error) T {
+	if err != nil {
+		panic(mustError(err))
+	}
+
+	return v
+}
+
+// Must0 panics with an error with stack trace wrapping err, if err is not nil
+// (the stack trace starting with Must0's caller).
+// It is meant for initialization code, where an error is not recoverable.
+//
+// Example:
+//
+//	xerr.Must0(db.Ping())
+func Must0(err error) {
+	if err != nil {
+		panic(mustError(err))
+	}
+}
+
+// mustError returns the error [Must] / [Must0] panic with.
+// It must be called directly from them, as the stack trace is captured
+// starting with their caller.
+func mustError(err error) error {
+	wErr := &stackError{
+		origErr:   err,
+		createdAt: time.Now(),
+	}
+	wErr.stackPCs, wErr.stackBuf = wrapCallStack(err, 1, maxStackFrames)
+
+	return created(wErr)
+}
@@ -0,0 +1,88 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr_test
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/actforgood/xerr"
+)
+
+func TestMust(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no error", testMustNoError)
+	t.Run("error", testMustError)
+}
+
+func testMustNoError(t *testing.T) {
+	t.Parallel()
+
+	// act
+	result := xerr.Must(strconv.Atoi("42"))
+
+	// assert
+	assertEqual(t, 42, result)
+}
+
+func testMustError(t *testing.T) {
+	t.Parallel()
+
+	// act
+	recovered := catchPanic(func() {
+		_ = xerr.Must(strconv.Atoi("x"))
+	})
+
+	// assert
+	err, ok := recovered.(error)
+	if assertTrue(t, ok) {
+		assertEqual(t, `strconv.Atoi: parsing "x": invalid syntax`, err.Error())
+		assertTrue(t, errors.Is(err, strconv.ErrSyntax))
+		frames := xerr.Frames(err)
+		if assertTrue(t, len(frames) > 0) {
+			assertEqual(t, "github.com/actforgood/xerr_test.testMustError.func1", frames[0].Function)
+		}
+	}
+}
+
+func TestMust0(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	origErr := xerr.New("some error")
+
+	// act
+	noPanic := catchPanic(func() {
+		xerr.Must0(nil)
+	})
+	recovered := catchPanic(func() {
+		xerr.Must0(origErr)
+	})
+
+	// assert
+	assertNil(t, noPanic)
+	err, ok := recovered.(error)
+	if assertTrue(t, ok) {
+		assertEqual(t, "some error", err.Error())
+		assertTrue(t, errors.Is(err, origErr))
+		frames := xerr.Frames(err)
+		if assertEqual(t, len(xerr.Frames(origErr))+1, len(frames)) {
+			assertEqual(t, "github.com/actforgood/xerr_test.TestMust0.func2", frames[0].Function)
+		}
+	}
+}
+
+// catchPanic calls fn, returning the recovered panic value, if any.
+func catchPanic(fn func()) (recovered any) {
+	defer func() {
+		recovered = recover()
+	}()
+	fn()
+
+	return nil
+}
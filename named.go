@@ -0,0 +1,58 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// namedError is an error labelled with a name,
+// see [MultiError.AddNamed].
+type namedError struct {
+	// name is the label (a field name, a shard ID, a file name, etc.).
+	name string
+	// err is the labelled error.
+	err error
+}
+
+// Error returns the error's message, prefixed with its name.
+// Implements std error interface.
+func (err *namedError) Error() string {
+	return err.name + ": " + err.err.Error()
+}
+
+// Format implements [fmt.Formatter].
+// It writes the name, and delegates to the labelled error.
+func (err *namedError) Format(f fmt.State, verb rune) {
+	_, _ = io.WriteString(f, err.name)
+	_, _ = io.WriteString(f, ": ")
+	formatError(f, verb, err.err)
+}
+
+// Unwrap returns the labelled error.
+// It implements [errors.Is] / [errors.As] APIs.
+func (err *namedError) Unwrap() error {
+	return err.err
+}
+
+// NameOf returns the name err was labelled with by [MultiError.AddNamed],
+// or empty string if err is not labelled.
+//
+// Example:
+//
+//	for _, err := range multiErr.Errors() {
+//		log.Printf("item %q failed: %v", xerr.NameOf(err), errors.Unwrap(err))
+//	}
+func NameOf(err error) string {
+	var nErr *namedError
+	if errors.As(err, &nErr) {
+		return nErr.name
+	}
+
+	return ""
+}
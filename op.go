@@ -0,0 +1,50 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr
+
+// opKey is the annotation key under which an error's operation is stored.
+type opKey struct{}
+
+// WithOp returns an error annotating err with the logical operation
+// that failed, like "repo.GetUser".
+// If err is nil, WithOp returns nil.
+//
+// Example:
+//
+//	func (r *Repo) GetUser(ctx context.Context, id int) (*User, error) {
+//		const op = "repo.GetUser"
+//		...
+//		if err != nil {
+//			return nil, xerr.WithOp(err, op)
+//		}
+//	}
+func WithOp(err error, op string) error {
+	if err == nil {
+		return nil
+	}
+
+	return withValue(err, opKey{}, op)
+}
+
+// Ops returns the operations found in err's chain, outermost first,
+// reconstructing the logical call path, like ["service.Register", "repo.GetUser"].
+// A [MultiError] ends the path, as its errors belong to different paths.
+// Returns nil if no operation was found.
+func Ops(err error) []string {
+	var ops []string
+	for e, layer := err, 0; e != nil && layer < maxChainDepth; layer++ {
+		if vErr, ok := e.(*valueError); ok && vErr.key == (opKey{}) {
+			ops = append(ops, vErr.val.(string))
+		}
+		unwrapper, ok := e.(interface{ Unwrap() error })
+		if !ok {
+			break
+		}
+		e = unwrapper.Unwrap()
+	}
+
+	return ops
+}
@@ -0,0 +1,90 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/actforgood/xerr"
+)
+
+func TestOps(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		subject = xerr.Ops
+		stdErr  = errors.New("some standard error")
+		tests   = [...]struct {
+			name     string
+			inputErr error
+			expected []string
+		}{
+			{
+				name:     "nil error",
+				inputErr: nil,
+				expected: nil,
+			},
+			{
+				name:     "error without operation",
+				inputErr: xerr.Wrap(stdErr, "wrap"),
+				expected: nil,
+			},
+			{
+				name:     "error with operation",
+				inputErr: xerr.WithOp(stdErr, "repo.GetUser"),
+				expected: []string{"repo.GetUser"},
+			},
+			{
+				name: "error with operations, outermost first",
+				inputErr: xerr.WithOp(
+					fmt.Errorf("register: %w", xerr.Wrap(xerr.WithOp(stdErr, "repo.GetUser"), "wrap")),
+					"service.Register",
+				),
+				expected: []string{"service.Register", "repo.GetUser"},
+			},
+			{
+				name: "multi error ends the path",
+				inputErr: xerr.WithOp(
+					xerr.NewMultiError().Add(xerr.WithOp(stdErr, "repo.GetUser"), xerr.WithOp(stdErr, "repo.GetOrder")),
+					"service.Report",
+				),
+				expected: []string{"service.Report"},
+			},
+		}
+	)
+
+	for _, testData := range tests {
+		test := testData // capture range variable
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			// act
+			result := subject(test.inputErr)
+
+			// assert
+			assertEqual(t, test.expected, result)
+		})
+	}
+}
+
+func TestWithOp(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	origErr := xerr.New("some error")
+
+	// act
+	result := xerr.WithOp(origErr, "repo.GetUser")
+
+	// assert
+	assertEqual(t, origErr.Error(), result.Error())
+	assertEqual(t, fmt.Sprintf("%+v", origErr), fmt.Sprintf("%+v", result))
+	assertTrue(t, errors.Is(result, origErr))
+	assertNil(t, xerr.WithOp(nil, "repo.GetUser"))
+}
@@ -0,0 +1,83 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr
+
+import "time"
+
+// maskedKey is the annotation key under which a masked error is stored.
+type maskedKey struct{}
+
+// Opaque returns an error with the same message and stack trace as err,
+// which does not wrap err anymore, so that [errors.Is] / [errors.As] can't
+// reach err's chain (like internal sentinel errors) through it.
+// It is useful at API boundaries.
+// If err does not have a stack trace, the stack trace is recorded at the point
+// Opaque was called.
+// Note: err's annotations (code, fields, etc.) are not preserved either.
+// If err is nil, Opaque returns nil.
+func Opaque(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return created(&stackError{
+		msg:       err.Error(),
+		stackPCs:  preservedCallStack(err),
+		createdAt: time.Now(),
+	})
+}
+
+// Mask returns an error hiding err behind publicErr: its message is publicErr's one,
+// and [errors.Is] / [errors.As] reach publicErr's chain, but not err's one.
+// err's stack trace is preserved for observability, and err itself can be
+// retrieved with [Unmask] (for logging purposes, for example).
+// If err does not have a stack trace, the stack trace is recorded at the point
+// Mask was called.
+// If err is nil, Mask returns nil. If publicErr is nil, Mask behaves like [Opaque].
+//
+// Example:
+//
+//	var ErrUnavailable = errors.New("service unavailable")
+//
+//	if err := repo.Save(ctx, user); err != nil {
+//		return xerr.Mask(err, ErrUnavailable) // errors.Is(result, ErrUnavailable) == true
+//	}
+func Mask(err, publicErr error) error {
+	if err == nil {
+		return nil
+	}
+
+	sErr := &stackError{
+		origErr:   publicErr,
+		stackPCs:  preservedCallStack(err),
+		createdAt: time.Now(),
+	}
+	if publicErr == nil {
+		sErr.msg = err.Error()
+	}
+
+	return created(withValue(sErr, maskedKey{}, err))
+}
+
+// Unmask returns the error hidden by [Mask], the outermost one found in err's chain.
+// Returns nil if err does not hide any error.
+func Unmask(err error) error {
+	if masked, found := lookupValue(err, maskedKey{}); found {
+		return masked.(error)
+	}
+
+	return nil
+}
+
+// preservedCallStack returns the stack trace of the outermost stack trace aware error
+// found in err's chain, if any, otherwise the call stack of the caller's caller is captured.
+func preservedCallStack(err error) []uintptr {
+	if stackPCs := existingCallStack(err); len(stackPCs) > 0 {
+		return stackPCs
+	}
+
+	return getCallStackSkip(1, maxStackFrames)
+}
@@ -0,0 +1,133 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr_test
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/actforgood/xerr"
+)
+
+func TestOpaque(t *testing.T) {
+	t.Parallel()
+
+	t.Run("error with stack", testOpaqueErrorWithStack)
+	t.Run("error without stack", testOpaqueErrorWithoutStack)
+	t.Run("nil error", testOpaqueNilError)
+}
+
+func testOpaqueErrorWithStack(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	origErr := xerr.Wrap(io.ErrUnexpectedEOF, "reading body")
+
+	// act
+	result := xerr.Opaque(origErr)
+
+	// assert
+	assertEqual(t, "reading body: unexpected EOF", result.Error())
+	assertFalse(t, errors.Is(result, io.ErrUnexpectedEOF))
+	assertNil(t, errors.Unwrap(result))
+	assertEqual(t, xerr.Frames(origErr), xerr.Frames(result))
+}
+
+func testOpaqueErrorWithoutStack(t *testing.T) {
+	t.Parallel()
+
+	// act
+	result := xerr.Opaque(io.ErrUnexpectedEOF)
+
+	// assert
+	assertEqual(t, "unexpected EOF", result.Error())
+	assertFalse(t, errors.Is(result, io.ErrUnexpectedEOF))
+	frames := xerr.Frames(result)
+	if assertTrue(t, len(frames) > 0) {
+		assertEqual(t, "github.com/actforgood/xerr_test.testOpaqueErrorWithoutStack", frames[0].Function)
+	}
+}
+
+func testOpaqueNilError(t *testing.T) {
+	t.Parallel()
+
+	// act
+	result := xerr.Opaque(nil)
+
+	// assert
+	assertNil(t, result)
+}
+
+func TestMask(t *testing.T) {
+	t.Parallel()
+
+	t.Run("error with stack", testMaskErrorWithStack)
+	t.Run("error without stack", testMaskErrorWithoutStack)
+	t.Run("nil public error", testMaskNilPublicError)
+	t.Run("nil error", testMaskNilError)
+}
+
+func testMaskErrorWithStack(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		errUnavailable = errors.New("service unavailable")
+		origErr        = xerr.Wrap(io.ErrUnexpectedEOF, "reading body")
+	)
+
+	// act
+	result := xerr.Mask(origErr, errUnavailable)
+
+	// assert
+	assertEqual(t, "service unavailable", result.Error())
+	assertTrue(t, errors.Is(result, errUnavailable))
+	assertFalse(t, errors.Is(result, io.ErrUnexpectedEOF))
+	assertEqual(t, xerr.Frames(origErr), xerr.Frames(result))
+	assertEqual(t, origErr, xerr.Unmask(xerr.Wrap(result, "saving user")))
+	assertNil(t, xerr.Unmask(origErr))
+}
+
+func testMaskErrorWithoutStack(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	errUnavailable := errors.New("service unavailable")
+
+	// act
+	result := xerr.Mask(io.ErrUnexpectedEOF, errUnavailable)
+
+	// assert
+	assertEqual(t, "service unavailable", result.Error())
+	frames := xerr.Frames(result)
+	if assertTrue(t, len(frames) > 0) {
+		assertEqual(t, "github.com/actforgood/xerr_test.testMaskErrorWithoutStack", frames[0].Function)
+	}
+	assertEqual(t, io.ErrUnexpectedEOF, xerr.Unmask(result))
+}
+
+func testMaskNilPublicError(t *testing.T) {
+	t.Parallel()
+
+	// act
+	result := xerr.Mask(io.ErrUnexpectedEOF, nil)
+
+	// assert
+	assertEqual(t, "unexpected EOF", result.Error())
+	assertFalse(t, errors.Is(result, io.ErrUnexpectedEOF))
+	assertEqual(t, io.ErrUnexpectedEOF, xerr.Unmask(result))
+}
+
+func testMaskNilError(t *testing.T) {
+	t.Parallel()
+
+	// act
+	result := xerr.Mask(nil, errors.New("service unavailable"))
+
+	// assert
+	assertNil(t, result)
+}
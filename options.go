@@ -0,0 +1,182 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr
+
+import "time"
+
+// options holds the configuration of an error created with [NewOpt] / [WrapOpt].
+type options struct {
+	depth           int
+	callerSkip      int
+	skipFrame       SkipFrame
+	fnNameProcessor FrameFnNameProcessor
+	fileProcessor   FrameFileProcessor
+	noStack         bool
+	stackMode       StackMode
+	hasStackMode    bool
+	buildInfo       bool
+	onError         func(err error)
+}
+
+// Option defines optional function for configuring
+// an error created with [NewOpt] / [WrapOpt].
+type Option func(*options)
+
+// WithDepth configures the maximum number of frames of the stack trace.
+// Defaults to 32. Non-positive values are ignored.
+func WithDepth(n int) Option {
+	return func(opts *options) {
+		if n > 0 {
+			opts.depth = n
+		}
+	}
+}
+
+// WithCallerSkip configures the number of callers to be skipped additionally
+// when capturing the stack trace. It is useful for helper functions
+// creating errors, which should not appear as the origin of the error.
+// Negative values are ignored.
+func WithCallerSkip(n int) Option {
+	return func(opts *options) {
+		if n >= 0 {
+			opts.callerSkip = n
+		}
+	}
+}
+
+// WithSkipFrame configures the [SkipFrame] to be applied on the error's
+// stack trace, instead of the globally configured one (see [SetSkipFrame]).
+// This way, a library can have its own stack policy, without altering
+// the process-wide configuration.
+func WithSkipFrame(fn SkipFrame) Option {
+	return func(opts *options) {
+		opts.skipFrame = fn
+	}
+}
+
+// WithFrameFnNameProcessor configures the [FrameFnNameProcessor] to be applied
+// on the error's stack trace, instead of the globally configured one
+// (see [SetFrameFnNameProcessor]).
+func WithFrameFnNameProcessor(fn FrameFnNameProcessor) Option {
+	return func(opts *options) {
+		opts.fnNameProcessor = fn
+	}
+}
+
+// WithFrameFileProcessor configures the [FrameFileProcessor] to be applied
+// on the error's stack trace, instead of the globally configured one
+// (see [SetFrameFileProcessor]).
+func WithFrameFileProcessor(fn FrameFileProcessor) Option {
+	return func(opts *options) {
+		opts.fileProcessor = fn
+	}
+}
+
+// WithNoStack configures the error not to capture any stack trace.
+// A wrapped stack trace aware error's stack trace is kept.
+// It is useful for expected errors on hot paths, where the stack trace cost is not justified.
+func WithNoStack() Option {
+	return func(opts *options) {
+		opts.noStack = true
+	}
+}
+
+// WithStackMode configures the way the error's stack trace is captured,
+// instead of the globally configured one (see [SetStackMode]).
+func WithStackMode(mode StackMode) Option {
+	return func(opts *options) {
+		opts.stackMode = mode
+		opts.hasStackMode = true
+	}
+}
+
+// WithOnError configures a hook invoked when the error is created,
+// instead of the globally configured one (see [SetOnError]).
+func WithOnError(fn func(err error)) Option {
+	return func(opts *options) {
+		opts.onError = fn
+	}
+}
+
+// newOptions returns the error configuration with given options applied.
+func newOptions(opts []Option) options {
+	result := options{depth: maxStackFrames}
+	for _, opt := range opts {
+		opt(&result)
+	}
+
+	return result
+}
+
+// NewOpt returns an error with the supplied message, configured with given options.
+// Unless [WithNoStack] is provided, NewOpt also records the stack trace at the point it was called.
+func NewOpt(msg string, opts ...Option) error {
+	o := newOptions(opts)
+
+	return o.created(newStackError(nil, msg, o))
+}
+
+// WrapOpt returns an error annotating err with the supplied message,
+// configured with given options.
+// Unless [WithNoStack] is provided, WrapOpt also records the stack trace
+// at the point it was called, the same way [Wrap] does.
+// If err is nil, WrapOpt returns nil.
+func WrapOpt(err error, msg string, opts ...Option) error {
+	if err == nil {
+		return nil
+	}
+
+	o := newOptions(opts)
+
+	return o.created(newStackError(err, msg, o))
+}
+
+// created notifies the configured hook about the creation of given error,
+// which is returned. See [WithOnError], [SetOnError].
+func (o options) created(err error) error {
+	if o.onError != nil {
+		stampID(err)
+		o.onError(err)
+		recordCreated(err)
+		logCreated(err)
+
+		return err
+	}
+
+	return created(err)
+}
+
+// newStackError returns a stack error configured with given options.
+// It must be called directly from the exported constructor, as the stack trace
+// is captured starting with the constructor's caller.
+func newStackError(origErr error, msg string, o options) *stackError {
+	sErr := &stackError{
+		origErr:         origErr,
+		msg:             msg,
+		createdAt:       time.Now(),
+		skipFrame:       o.skipFrame,
+		fnNameProcessor: o.fnNameProcessor,
+		fileProcessor:   o.fileProcessor,
+	}
+	if o.buildInfo {
+		sErr.build = currentBuildInfo()
+	}
+	mode := stackMode
+	switch {
+	case o.noStack:
+		mode = StackModeDisabled
+	case o.hasStackMode:
+		mode = o.stackMode
+	}
+	if origErr == nil {
+		sErr.stackPCs = callStack(o.callerSkip+1, mode.depth(o.depth))
+	} else {
+		// with no stack captured, the wrapped error's stack trace is kept.
+		sErr.stackPCs, sErr.stackBuf = wrapCallStackDepth(origErr, o.callerSkip+1, mode.depth(o.depth))
+	}
+
+	return sErr
+}
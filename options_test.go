@@ -0,0 +1,213 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/actforgood/xerr"
+)
+
+func TestNewOpt(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no options", testNewOptNoOptions)
+	t.Run("with depth", testNewOptWithDepth)
+	t.Run("with caller skip", testNewOptWithCallerSkip)
+	t.Run("with skip frame", testNewOptWithSkipFrame)
+	t.Run("with frame file processor", testNewOptWithFrameFileProcessor)
+	t.Run("with no stack", testNewOptWithNoStack)
+	t.Run("with stack mode", testNewOptWithStackMode)
+}
+
+func testNewOptNoOptions(t *testing.T) {
+	t.Parallel()
+
+	// act
+	result := xerr.NewOpt("something went bad")
+
+	// assert
+	assertEqual(t, "something went bad", result.Error())
+	frames := xerr.Frames(result)
+	if assertTrue(t, len(frames) > 1) {
+		assertEqual(t, "github.com/actforgood/xerr_test.testNewOptNoOptions", frames[0].Function)
+	}
+}
+
+func testNewOptWithDepth(t *testing.T) {
+	t.Parallel()
+
+	// act
+	result := xerr.NewOpt("something went bad", xerr.WithDepth(1), xerr.WithDepth(-1))
+
+	// assert
+	frames := xerr.Frames(result)
+	if assertEqual(t, 1, len(frames)) {
+		assertEqual(t, "github.com/actforgood/xerr_test.testNewOptWithDepth", frames[0].Function)
+	}
+}
+
+func testNewOptWithCallerSkip(t *testing.T) {
+	t.Parallel()
+
+	// act
+	result := newTestOptError("something went bad")
+
+	// assert
+	frames := xerr.Frames(result)
+	if assertTrue(t, len(frames) > 1) {
+		assertEqual(t, "github.com/actforgood/xerr_test.testNewOptWithCallerSkip", frames[0].Function)
+	}
+}
+
+func testNewOptWithSkipFrame(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	skipTesting := func(_, file string) bool {
+		return strings.Contains(file, "testing")
+	}
+
+	// act
+	result := xerr.NewOpt("something went bad", xerr.WithSkipFrame(skipTesting))
+
+	// assert
+	frames := xerr.Frames(result)
+	for _, frame := range frames {
+		assertFalse(t, strings.Contains(frame.File, "testing"))
+	}
+	errMsgWithStack := fmt.Sprintf("%+v", result)
+	assertFalse(t, strings.Contains(errMsgWithStack, "testing.go"))
+	assertTrue(t, strings.Contains(errMsgWithStack, "options_test.go"))
+	// the global configuration is not affected.
+	assertTrue(t, strings.Contains(fmt.Sprintf("%+v", xerr.New("other error")), "testing.go"))
+}
+
+func testNewOptWithFrameFileProcessor(t *testing.T) {
+	t.Parallel()
+
+	// act
+	result := xerr.NewOpt("something went bad", xerr.WithFrameFileProcessor(xerr.BaseNameOnly))
+
+	// assert
+	frames := xerr.Frames(result)
+	if assertTrue(t, len(frames) > 1) {
+		assertEqual(t, "options_test.go", frames[0].File)
+	}
+	errMsgWithStack := fmt.Sprintf("%+v", result)
+	assertTrue(t, strings.Contains(errMsgWithStack, "\n\toptions_test.go:"))
+	// the global configuration is not affected.
+	assertTrue(t, strings.Contains(fmt.Sprintf("%+v", xerr.New("other error")), "/options_test.go:"))
+}
+
+func testNewOptWithNoStack(t *testing.T) {
+	t.Parallel()
+
+	// act
+	result := xerr.NewOpt("something went bad", xerr.WithNoStack())
+
+	// assert
+	assertEqual(t, "something went bad", result.Error())
+	assertNil(t, xerr.Frames(result))
+	assertEqual(t, "something went bad", fmt.Sprintf("%+v", result))
+}
+
+func testNewOptWithStackMode(t *testing.T) {
+	t.Parallel()
+
+	// act
+	callerOnlyErr := xerr.NewOpt("something went bad", xerr.WithStackMode(xerr.StackModeCallerOnly))
+	disabledErr := xerr.NewOpt("something went bad", xerr.WithStackMode(xerr.StackModeDisabled))
+	wrapErr := xerr.WrapOpt(callerOnlyErr, "could not do that", xerr.WithStackMode(xerr.StackModeDisabled))
+
+	// assert
+	frames := xerr.Frames(callerOnlyErr)
+	if assertEqual(t, 1, len(frames)) {
+		assertEqual(t, "github.com/actforgood/xerr_test.testNewOptWithStackMode", frames[0].Function)
+	}
+	assertNil(t, xerr.Frames(disabledErr))
+	assertEqual(t, "something went bad", fmt.Sprintf("%+v", disabledErr))
+	assertEqual(t, frames, xerr.Frames(wrapErr)) // wrapped error's stack trace is kept.
+}
+
+func TestWrapOpt(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil error", func(t *testing.T) {
+		t.Parallel()
+
+		assertNil(t, xerr.WrapOpt(nil, "wrap", xerr.WithNoStack()))
+	})
+
+	t.Run("standard error", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		origErr := errors.New("some error")
+
+		// act
+		result := xerr.WrapOpt(origErr, "wrap", xerr.WithDepth(2))
+
+		// assert
+		assertEqual(t, "wrap: some error", result.Error())
+		assertTrue(t, errors.Is(result, origErr))
+		frames := xerr.Frames(result)
+		if assertEqual(t, 2, len(frames)) {
+			assertEqual(t, "github.com/actforgood/xerr_test.TestWrapOpt.func2", frames[0].Function)
+		}
+	})
+
+	t.Run("stack error", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		origErr := xerr.NewOpt("some error", xerr.WithDepth(1))
+
+		// act
+		result := xerr.WrapOpt(origErr, "wrap")
+
+		// assert
+		assertEqual(t, "wrap: some error", result.Error())
+		frames := xerr.Frames(result)
+		if assertEqual(t, 2, len(frames)) {
+			assertEqual(t, frames[0].Function, frames[1].Function)
+			assertTrue(t, frames[0].Line > frames[1].Line)
+		}
+	})
+
+	t.Run("no stack keeps wrapped error's stack", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		origErr := xerr.New("some error")
+
+		// act
+		result := xerr.WrapOpt(origErr, "wrap", xerr.WithNoStack())
+
+		// assert
+		assertEqual(t, "wrap: some error", result.Error())
+		assertEqual(t, xerr.Frames(origErr), xerr.Frames(result))
+	})
+
+	t.Run("no stack, standard error", func(t *testing.T) {
+		t.Parallel()
+
+		// act
+		result := xerr.WrapOpt(errors.New("some error"), "wrap", xerr.WithNoStack())
+
+		// assert
+		assertNil(t, xerr.Frames(result))
+	})
+}
+
+// newTestOptError is a helper creating an error,
+// which should not appear in the error's stack trace.
+func newTestOptError(msg string) error {
+	return xerr.NewOpt(msg, xerr.WithCallerSkip(1), xerr.WithCallerSkip(-1))
+}
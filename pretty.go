@@ -0,0 +1,96 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ANSI escape codes used by [Pretty].
+const (
+	ansiReset = "\x1b[0m"
+	ansiBold  = "\x1b[1m"
+	ansiDim   = "\x1b[2m"
+	ansiRed   = "\x1b[31m"
+	ansiCyan  = "\x1b[36m"
+)
+
+// Pretty returns a human readable rendering of an error, meant for
+// CLI tools crash output: its message followed by its stack trace frames,
+// with the message highlighted, main module frames accented and
+// standard library frames dimmed, using ANSI colors.
+// Colors are used only if the standard error is a terminal and
+// the NO_COLOR environment variable is not set (see [PrettyColored]
+// for explicitly enabling/disabling them).
+// Configured [SkipFrame], [FrameFnNameProcessor] and [FrameFileProcessor] are honored.
+// Returns empty string for a nil error.
+func Pretty(err error) string {
+	return PrettyColored(err, stderrSupportsColors())
+}
+
+// PrettyColored returns a human readable rendering of an error, like [Pretty] does,
+// with ANSI colors used or not, as requested.
+func PrettyColored(err error, colored bool) string {
+	if err == nil {
+		return ""
+	}
+
+	var sb strings.Builder
+	writeStyled(&sb, err.Error(), ansiBold+ansiRed, colored)
+	for _, f := range Frames(err) {
+		fnName, file, _ := getFrame(f.PC - 1) // as they are, with no processor applied.
+		var fnStyle, fileStyle string
+		switch {
+		case isMainModuleFrame(fnName):
+			fnStyle, fileStyle = ansiBold+ansiCyan, ansiCyan
+		case isStdFrame(fnName, file):
+			fnStyle, fileStyle = ansiDim, ansiDim
+		}
+		sb.WriteString("\n")
+		writeStyled(&sb, f.Function, fnStyle, colored)
+		sb.WriteString("\n\t")
+		writeStyled(&sb, f.File+":"+strconv.Itoa(f.Line), fileStyle, colored)
+	}
+
+	return sb.String()
+}
+
+// writeStyled writes given text, surrounded by given ANSI style, if colored is true.
+func writeStyled(sb *strings.Builder, text, style string, colored bool) {
+	if !colored || style == "" {
+		sb.WriteString(text)
+
+		return
+	}
+	sb.WriteString(style)
+	sb.WriteString(text)
+	sb.WriteString(ansiReset)
+}
+
+// isMainModuleFrame checks whether given function belongs to the main module
+// of the application (or is a main package function).
+func isMainModuleFrame(fnName string) bool {
+	if strings.HasPrefix(fnName, "main.") {
+		return true
+	}
+	mainModule := mainModulePath()
+
+	return mainModule != "" && isModuleFunction(fnName, mainModule)
+}
+
+// stderrSupportsColors checks whether the standard error is a terminal
+// and colors were not disabled through the NO_COLOR environment variable.
+var stderrSupportsColors = sync.OnceValue(func() bool {
+	if _, noColor := os.LookupEnv("NO_COLOR"); noColor {
+		return false
+	}
+	fi, err := os.Stderr.Stat()
+
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+})
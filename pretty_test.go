@@ -0,0 +1,100 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr_test
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/actforgood/xerr"
+)
+
+func TestPrettyColored(t *testing.T) {
+	t.Parallel()
+
+	t.Run("colored", testPrettyColoredColored)
+	t.Run("not colored", testPrettyColoredNotColored)
+	t.Run("error without stack", testPrettyColoredErrorWithoutStack)
+	t.Run("nil error", testPrettyColoredNilError)
+}
+
+func testPrettyColoredColored(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	err := xerr.New("something went bad")
+	regexes := []string{
+		"^\x1b\\[1m\x1b\\[31msomething went bad\x1b\\[0m\n",
+		"\n\x1b\\[1m\x1b\\[36mgithub\\.com/actforgood/xerr_test\\.testPrettyColoredColored\x1b\\[0m\n" +
+			"\t\x1b\\[36m.+pretty_test\\.go:\\d+\x1b\\[0m\n",
+		"\n\x1b\\[2mtesting\\.tRunner\x1b\\[0m\n\t\x1b\\[2m.+testing\\.go:\\d+\x1b\\[0m",
+	}
+
+	// act
+	result := xerr.PrettyColored(err, true)
+
+	// assert
+	for _, reg := range regexes {
+		matched, _ := regexp.MatchString(reg, result)
+		if !assertTrue(t, matched) {
+			t.Log("regex", reg, "result", result)
+		}
+	}
+}
+
+func testPrettyColoredNotColored(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	err := xerr.New("something went bad")
+
+	// act
+	result := xerr.PrettyColored(err, false)
+
+	// assert
+	assertFalse(t, strings.Contains(result, "\x1b["))
+	assertTrue(t, strings.HasPrefix(
+		result,
+		"something went bad\ngithub.com/actforgood/xerr_test.testPrettyColoredNotColored\n\t",
+	))
+}
+
+func testPrettyColoredErrorWithoutStack(t *testing.T) {
+	t.Parallel()
+
+	// act
+	result := xerr.PrettyColored(errors.New("some error"), true)
+
+	// assert
+	assertEqual(t, "\x1b[1m\x1b[31msome error\x1b[0m", result)
+}
+
+func testPrettyColoredNilError(t *testing.T) {
+	t.Parallel()
+
+	// act
+	result := xerr.PrettyColored(nil, true)
+
+	// assert
+	assertEqual(t, "", result)
+}
+
+func TestPretty(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	err := xerr.New("something went bad")
+
+	// act
+	result := xerr.Pretty(err)
+
+	// assert
+	// colors depend on the standard error being a terminal or not.
+	assertTrue(t, result == xerr.PrettyColored(err, false) || result == xerr.PrettyColored(err, true))
+	assertTrue(t, strings.Contains(result, "something went bad"))
+}
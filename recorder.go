@@ -0,0 +1,206 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr
+
+import (
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RetentionMode defines how a [Recorder] keeps the errors it records.
+type RetentionMode int
+
+const (
+	// RetainErrors keeps the live error values.
+	RetainErrors RetentionMode = iota
+	// RetainCompact keeps only a compact, serialized form of errors
+	// (fingerprint, message, top frames), so that retained errors
+	// can't pin large wrapped payloads (request bodies, buffers) in memory.
+	RetainCompact
+)
+
+// defaultRecorderTopFrames is the default number of frames kept
+// for an error recorded in [RetainCompact] mode.
+const defaultRecorderTopFrames = 5
+
+// flightRecorder is the globally configured recorder of created errors, see [SetFlightRecorder].
+// If nil, created errors are not recorded.
+var flightRecorder *Recorder
+
+// RecordedError is an error kept by a [Recorder].
+type RecordedError struct {
+	// Time is the moment the error was recorded.
+	Time time.Time
+	// Err is the recorded error. It is nil in [RetainCompact] mode.
+	Err error
+	// Message is the error's message.
+	Message string
+	// Fingerprint is the error's fingerprint, see [Fingerprint].
+	Fingerprint string
+	// TopFrames are the first frames of the error's stack trace,
+	// in the "<function> <file>:<line>" format.
+	// They are filled only in [RetainCompact] mode.
+	TopFrames []string
+}
+
+// Recorder keeps, in memory, a ring buffer of the most recent errors.
+// Its APIs are concurrent safe.
+type Recorder struct {
+	entries   []RecordedError
+	next      int
+	full      bool
+	mode      RetentionMode
+	topFrames int
+	mu        sync.Mutex
+}
+
+// RecorderOption defines optional function for configuring a [Recorder].
+type RecorderOption func(*Recorder)
+
+// WithRetentionMode configures the way a [Recorder] keeps the errors.
+// By default, [RetainErrors] is used.
+func WithRetentionMode(mode RetentionMode) RecorderOption {
+	return func(r *Recorder) {
+		r.mode = mode
+	}
+}
+
+// WithTopFrames configures the number of frames a [Recorder]
+// keeps for an error in [RetainCompact] mode. Defaults to 5.
+func WithTopFrames(n int) RecorderOption {
+	return func(r *Recorder) {
+		if n >= 0 {
+			r.topFrames = n
+		}
+	}
+}
+
+// NewRecorder instantiates a new [Recorder] which keeps the last size recorded errors.
+// If size is not positive, 1 is used.
+func NewRecorder(size int, opts ...RecorderOption) *Recorder {
+	if size <= 0 {
+		size = 1
+	}
+	r := &Recorder{
+		entries:   make([]RecordedError, size),
+		mode:      RetainErrors,
+		topFrames: defaultRecorderTopFrames,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
+}
+
+// Report records the given error, evicting the oldest one if the buffer is full.
+// A nil error is ignored.
+// It implements [Reporter].
+func (r *Recorder) Report(err error) {
+	if err == nil {
+		return
+	}
+
+	entry := RecordedError{
+		Time:        time.Now(),
+		Message:     err.Error(),
+		Fingerprint: Fingerprint(err),
+	}
+	if r.mode == RetainCompact {
+		entry.TopFrames = topFrames(err, r.topFrames)
+	} else {
+		entry.Err = err
+	}
+
+	r.mu.Lock()
+	r.entries[r.next] = entry
+	r.next++
+	if r.next == len(r.entries) {
+		r.next = 0
+		r.full = true
+	}
+	r.mu.Unlock()
+}
+
+// Recent returns the recorded errors, from the oldest to the newest one.
+func (r *Recorder) Recent() []RecordedError {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.full {
+		recent := make([]RecordedError, r.next)
+		copy(recent, r.entries[:r.next])
+
+		return recent
+	}
+
+	recent := make([]RecordedError, 0, len(r.entries))
+	recent = append(recent, r.entries[r.next:]...)
+	recent = append(recent, r.entries[:r.next]...)
+
+	return recent
+}
+
+// topFrames returns the first n frames of err's stack trace,
+// in the "<function> <file>:<line>" format.
+func topFrames(err error, n int) []string {
+	frames := Frames(err)
+	if len(frames) > n {
+		frames = frames[:n]
+	}
+	if len(frames) == 0 {
+		return nil
+	}
+
+	return formatFrames(frames)
+}
+
+// formatFrames returns the given frames in the "<function> <file>:<line>" format.
+func formatFrames(frames []Frame) []string {
+	result := make([]string, len(frames))
+	for idx, f := range frames {
+		result[idx] = f.Function + " " + f.File + ":" + strconv.FormatInt(int64(f.Line), 10)
+	}
+
+	return result
+}
+
+// SetFlightRecorder configures a [Recorder] every created error with stack trace
+// ([New], [Errorf], [Wrap], [Wrapf], and the other constructors) is recorded into,
+// at creation, so that the errors which recently happened in a live process can be dumped,
+// with [RecentErrors], for example during an incident.
+// Only the first error with stack trace of a chain is recorded, wrapping it
+// again does not record it again, so that one failure does not fill the recorder.
+// Pass nil to stop recording errors (default).
+// You will call it usually somewhere in the bootstrap process of your
+// application. For example:
+//
+//	// myapp/bootstrap.go
+//	func init() {
+//		xerr.SetFlightRecorder(xerr.NewRecorder(100, xerr.WithRetentionMode(xerr.RetainCompact)))
+//	}
+func SetFlightRecorder(r *Recorder) {
+	flightRecorder = r
+}
+
+// RecentErrors returns the errors recorded by the configured flight recorder
+// (see [SetFlightRecorder]), from the oldest to the newest one.
+// Returns nil if there is no flight recorder configured.
+func RecentErrors() []RecordedError {
+	if r := flightRecorder; r != nil {
+		return r.Recent()
+	}
+
+	return nil
+}
+
+// recordCreated records given, just created, error, if a flight recorder is configured.
+func recordCreated(err error) {
+	if r := flightRecorder; r != nil {
+		r.Report(err)
+	}
+}
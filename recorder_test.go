@@ -0,0 +1,158 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr_test
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/actforgood/xerr"
+)
+
+func TestRecorder(t *testing.T) {
+	t.Parallel()
+
+	t.Run("retain errors", testRecorderRetainErrors)
+	t.Run("retain compact", testRecorderRetainCompact)
+	t.Run("ring buffer", testRecorderRingBuffer)
+	t.Run("concurrency", testRecorderConcurrency)
+}
+
+func testRecorderRetainErrors(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		subject = xerr.NewRecorder(3)
+		err1    = xerr.New("first")
+		err2    = errors.New("second")
+	)
+
+	// act
+	subject.Report(err1)
+	subject.Report(nil)
+	subject.Report(err2)
+	result := subject.Recent()
+
+	// assert
+	if assertEqual(t, 2, len(result)) {
+		assertEqual(t, err1, result[0].Err)
+		assertEqual(t, "first", result[0].Message)
+		assertEqual(t, xerr.Fingerprint(err1), result[0].Fingerprint)
+		assertNil(t, result[0].TopFrames)
+		assertEqual(t, err2, result[1].Err)
+		assertEqual(t, "second", result[1].Message)
+		assertFalse(t, result[0].Time.After(result[1].Time))
+	}
+}
+
+func testRecorderRetainCompact(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		subject = xerr.NewRecorder(
+			3,
+			xerr.WithRetentionMode(xerr.RetainCompact),
+			xerr.WithTopFrames(1),
+		)
+		err1 = xerr.New("first")
+		err2 = errors.New("second")
+	)
+
+	// act
+	subject.Report(err1)
+	subject.Report(err2)
+	result := subject.Recent()
+
+	// assert
+	if assertEqual(t, 2, len(result)) {
+		assertNil(t, result[0].Err)
+		assertEqual(t, "first", result[0].Message)
+		assertEqual(t, xerr.Fingerprint(err1), result[0].Fingerprint)
+		if assertEqual(t, 1, len(result[0].TopFrames)) {
+			assertTrue(t, strings.HasPrefix(
+				result[0].TopFrames[0],
+				"github.com/actforgood/xerr_test.testRecorderRetainCompact ",
+			))
+			assertTrue(t, strings.Contains(result[0].TopFrames[0], "recorder_test.go:"))
+		}
+		assertNil(t, result[1].Err)
+		assertEqual(t, "second", result[1].Message)
+		assertNil(t, result[1].TopFrames)
+	}
+}
+
+func testRecorderRingBuffer(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	subject := xerr.NewRecorder(3)
+
+	// act
+	for i := 1; i <= 5; i++ {
+		subject.Report(errors.New(strconv.Itoa(i)))
+	}
+	result := subject.Recent()
+
+	// assert
+	if assertEqual(t, 3, len(result)) {
+		assertEqual(t, "3", result[0].Message)
+		assertEqual(t, "4", result[1].Message)
+		assertEqual(t, "5", result[2].Message)
+	}
+}
+
+func testRecorderConcurrency(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		subject      = xerr.NewRecorder(10, xerr.WithRetentionMode(xerr.RetainCompact))
+		goroutinesNo = 50
+		wg           sync.WaitGroup
+	)
+
+	// act
+	for i := 0; i < goroutinesNo; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			subject.Report(xerr.New("concurrent"))
+			_ = subject.Recent()
+		}()
+	}
+	wg.Wait()
+
+	// assert
+	assertEqual(t, 10, len(subject.Recent()))
+}
+
+func TestSetFlightRecorder(t *testing.T) {
+	// test is not parallel as it changes global configuration.
+
+	// arrange
+	xerr.SetFlightRecorder(xerr.NewRecorder(2))
+
+	// act
+	err1 := xerr.New("first")
+	err2 := xerr.WrapOpt(errors.New("second"), "could not call API", xerr.WithOnError(func(error) {}))
+	_ = errors.New("std error") // not recorded
+	_ = xerr.Wrap(err1, "wrap") // not recorded, as err1 already was
+	result := xerr.RecentErrors()
+	xerr.SetFlightRecorder(nil)
+	_ = xerr.New("third") // not recorded
+
+	// assert
+	if assertEqual(t, 2, len(result)) {
+		assertEqual(t, err1, result[0].Err)
+		assertEqual(t, err2, result[1].Err)
+	}
+	assertNil(t, xerr.RecentErrors())
+}
@@ -0,0 +1,88 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr
+
+import (
+	"fmt"
+	"runtime"
+	"time"
+)
+
+// panicFnName is the runtime function a panic call stack goes through.
+const panicFnName = "runtime.gopanic"
+
+// Recover converts a recovered panic value into an error with stack trace.
+// It is meant to be called with recover()'s result, inside a deferred function.
+// The stack trace is captured at the panic site.
+// If the recovered value is an error, it is wrapped, and can be checked
+// with [errors.Is] / [errors.As], otherwise it is part of the message.
+// Returns nil if recovered value is nil (there was no panic).
+//
+// Example:
+//
+//	defer func() {
+//		if err := xerr.Recover(recover()); err != nil {
+//			// handle err
+//		}
+//	}()
+func Recover(recovered any) error {
+	if recovered == nil {
+		return nil
+	}
+
+	sErr := &stackError{
+		stackPCs:  getPanicCallStack(maxStackFrames),
+		createdAt: time.Now(),
+	}
+	if err, ok := recovered.(error); ok {
+		sErr.origErr = err
+		sErr.msg = "panic"
+	} else {
+		sErr.msg = fmt.Sprintf("panic: %v", recovered)
+	}
+
+	return created(sErr)
+}
+
+// SafeGo runs fn in a new goroutine, converting an eventual panic
+// into an error with stack trace (see [Recover]).
+// The returned channel receives the error, if fn panicked, and is closed after fn returns,
+// so that receiving from it blocks until fn is done and yields nil if no panic occurred.
+func SafeGo(fn func()) <-chan error {
+	errCh := make(chan error, 1)
+	go func() {
+		defer close(errCh)
+		defer func() {
+			if err := Recover(recover()); err != nil {
+				errCh <- err
+			}
+		}()
+		fn()
+	}()
+
+	return errCh
+}
+
+// getPanicCallStack returns a slice of program counters of function invocations
+// on the calling goroutine's stack, starting from the panic site.
+// If the goroutine is not panicking, the stack starts from the caller of [Recover].
+func getPanicCallStack(maxDepth int) []uintptr {
+	pcs := make([]uintptr, maxDepth+16)
+	n := runtime.Callers(3, pcs)
+	pcs = pcs[:n]
+	for idx, pc := range pcs {
+		if fn := runtime.FuncForPC(pc - 1); fn != nil && fn.Name() == panicFnName {
+			pcs = pcs[idx+1:]
+
+			break
+		}
+	}
+	if len(pcs) > maxDepth {
+		pcs = pcs[:maxDepth]
+	}
+
+	return pcs
+}
@@ -0,0 +1,133 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr_test
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/actforgood/xerr"
+)
+
+func TestRecover(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no panic", testRecoverNoPanic)
+	t.Run("panic with value", testRecoverPanicWithValue)
+	t.Run("panic with error", testRecoverPanicWithError)
+}
+
+func testRecoverNoPanic(t *testing.T) {
+	t.Parallel()
+
+	// act
+	result := xerr.Recover(nil)
+
+	// assert
+	assertNil(t, result)
+}
+
+func testRecoverPanicWithValue(t *testing.T) {
+	t.Parallel()
+
+	// act
+	result := recoverFrom(func() {
+		panicWith("something went bad")
+	})
+
+	// assert
+	if assertNotNil(t, result) {
+		assertEqual(t, "panic: something went bad", result.Error())
+		frames := xerr.Frames(result)
+		if assertTrue(t, len(frames) > 1) {
+			assertEqual(t, "github.com/actforgood/xerr_test.panicWith", frames[0].Function)
+			matched, _ := regexp.MatchString(`^github\.com/actforgood/xerr_test\.testRecoverPanicWithValue\.func\d+$`, frames[1].Function)
+			assertTrue(t, matched)
+		}
+	}
+}
+
+func testRecoverPanicWithError(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	panicErr := errors.New("some error")
+
+	// act
+	result := recoverFrom(func() {
+		panicWith(panicErr)
+	})
+
+	// assert
+	if assertNotNil(t, result) {
+		assertEqual(t, "panic: some error", result.Error())
+		assertTrue(t, errors.Is(result, panicErr))
+		frames := xerr.Frames(result)
+		if assertTrue(t, len(frames) > 0) {
+			assertEqual(t, "github.com/actforgood/xerr_test.panicWith", frames[0].Function)
+		}
+	}
+}
+
+func TestSafeGo(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no panic", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		called := false
+
+		// act
+		errCh := xerr.SafeGo(func() {
+			called = true
+		})
+		result := <-errCh
+
+		// assert
+		assertNil(t, result)
+		assertTrue(t, called)
+		_, open := <-errCh
+		assertFalse(t, open)
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		t.Parallel()
+
+		// act
+		result := <-xerr.SafeGo(func() {
+			panicWith(fmt.Sprintf("value %d", 100))
+		})
+
+		// assert
+		if assertNotNil(t, result) {
+			assertEqual(t, "panic: value 100", result.Error())
+			frames := xerr.Frames(result)
+			if assertTrue(t, len(frames) > 0) {
+				assertEqual(t, "github.com/actforgood/xerr_test.panicWith", frames[0].Function)
+			}
+		}
+	})
+}
+
+// recoverFrom calls fn and returns the error of the recovered panic.
+func recoverFrom(fn func()) (err error) {
+	defer func() {
+		err = xerr.Recover(recover())
+	}()
+	fn()
+
+	return nil
+}
+
+// panicWith panics with given value.
+//
+//go:noinline
+func panicWith(v any) {
+	panic(v)
+}
@@ -0,0 +1,137 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr
+
+import (
+	"reflect"
+	"sync"
+)
+
+// ErrorInfo holds the metadata of a sentinel error registered in a [Registry].
+type ErrorInfo struct {
+	// Code is the error's code.
+	Code Code
+	// Kind is the error's kind.
+	Kind Kind
+	// HTTPStatus is the HTTP status code the error maps to (0 if not set).
+	HTTPStatus int
+	// GRPCCode is the gRPC status code the error maps to
+	// (the numeric value of google.golang.org/grpc/codes.Code, 0 (OK) if not set).
+	GRPCCode uint32
+	// DocURL is the URL of the error's documentation.
+	DocURL string
+}
+
+// Registry holds sentinel errors with their metadata, centralizing
+// the mapping of application errors to codes, kinds, transport statuses, etc.
+// Its APIs are concurrent safe.
+//
+// Example:
+//
+//	var (
+//		errs        = xerr.NewRegistry()
+//		ErrNotFound = errs.Register(errors.New("user not found"), xerr.ErrorInfo{
+//			Code:       "USR-0001",
+//			Kind:       xerr.KindNotFound,
+//			HTTPStatus: http.StatusNotFound,
+//			GRPCCode:   uint32(codes.NotFound),
+//			DocURL:     "https://docs.example.com/errors/USR-0001",
+//		})
+//	)
+//
+//	// somewhere in a transport layer:
+//	if info, found := errs.Lookup(err); found {
+//		w.WriteHeader(info.HTTPStatus)
+//	}
+type Registry struct {
+	// entries holds registered sentinel errors, in registration order.
+	entries []registryEntry
+	// infoByErr holds the metadata of comparable sentinel errors, for fast lookup.
+	infoByErr map[error]ErrorInfo
+	mu        sync.RWMutex
+}
+
+// registryEntry is a registered sentinel error, with its metadata.
+type registryEntry struct {
+	sentinel error
+	info     ErrorInfo
+}
+
+// NewRegistry instantiates a new, empty, [Registry].
+func NewRegistry() *Registry {
+	return &Registry{
+		infoByErr: make(map[error]ErrorInfo),
+	}
+}
+
+// Register adds the sentinel error with its metadata to the registry,
+// and returns the sentinel error (so that it can be declared and registered
+// at once). Registering an already registered sentinel error overwrites its metadata.
+// Note: a sentinel error which is not comparable (with ==) is matched only by errors
+// having a method Is(error) bool. A nil sentinel error is ignored.
+func (r *Registry) Register(sentinel error, info ErrorInfo) error {
+	if sentinel == nil {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if reflect.TypeOf(sentinel).Comparable() {
+		if _, found := r.infoByErr[sentinel]; found {
+			for idx := range r.entries {
+				if r.entries[idx].sentinel == sentinel {
+					r.entries[idx].info = info
+
+					break
+				}
+			}
+			r.infoByErr[sentinel] = info
+
+			return sentinel
+		}
+		r.infoByErr[sentinel] = info
+	}
+	r.entries = append(r.entries, registryEntry{sentinel: sentinel, info: info})
+
+	return sentinel
+}
+
+// Lookup returns the metadata of the registered sentinel error found in
+// err's chain (the outermost one, if there are many), and true,
+// or empty metadata and false, if there is no registered error in err's chain.
+// An error in the chain matches a sentinel error if it is equal to it,
+// or if it has a method Is(error) bool such that Is(sentinel) returns true,
+// like for [errors.Is].
+func (r *Registry) Lookup(err error) (ErrorInfo, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var (
+		info  ErrorInfo
+		found bool
+	)
+	walkChain(err, func(e error) bool {
+		if reflect.TypeOf(e).Comparable() {
+			if info, found = r.infoByErr[e]; found {
+				return false
+			}
+		}
+		if x, ok := e.(interface{ Is(error) bool }); ok {
+			for _, entry := range r.entries {
+				if x.Is(entry.sentinel) {
+					info, found = entry.info, true
+
+					return false
+				}
+			}
+		}
+
+		return true
+	})
+
+	return info, found
+}
@@ -0,0 +1,155 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/actforgood/xerr"
+)
+
+func TestRegistry(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		subject      = xerr.NewRegistry()
+		notFoundInfo = xerr.ErrorInfo{
+			Code:       "USR-0001",
+			Kind:       xerr.KindNotFound,
+			HTTPStatus: http.StatusNotFound,
+			GRPCCode:   5,
+			DocURL:     "https://docs.example.com/errors/USR-0001",
+		}
+		conflictInfo = xerr.ErrorInfo{
+			Code:       "USR-0002",
+			Kind:       xerr.KindConflict,
+			HTTPStatus: http.StatusConflict,
+		}
+		errNotFound   = subject.Register(errors.New("user not found"), xerr.ErrorInfo{Code: "old"})
+		errConflict   = subject.Register(xerr.Const("user already exists"), conflictInfo)
+		errUnknown    = errors.New("unknown")
+		errNonComp    = subject.Register(nonComparableErr{msgs: []string{"non comparable"}}, conflictInfo)
+		errNilIgnored = subject.Register(nil, notFoundInfo)
+	)
+	_ = subject.Register(errNotFound, notFoundInfo) // overwrite.
+
+	tests := [...]struct {
+		name          string
+		inputErr      error
+		expectedInfo  xerr.ErrorInfo
+		expectedFound bool
+	}{
+		{
+			name:          "sentinel error",
+			inputErr:      errNotFound,
+			expectedInfo:  notFoundInfo,
+			expectedFound: true,
+		},
+		{
+			name:          "wrapped sentinel error",
+			inputErr:      xerr.Wrap(fmt.Errorf("get user: %w", errNotFound), "handle request"),
+			expectedInfo:  notFoundInfo,
+			expectedFound: true,
+		},
+		{
+			name:          "const sentinel error",
+			inputErr:      xerr.Wrap(xerr.Const("user already exists"), "create user"),
+			expectedInfo:  conflictInfo,
+			expectedFound: true,
+		},
+		{
+			name:          "marked error",
+			inputErr:      xerr.MarkAs(errUnknown, errNotFound),
+			expectedInfo:  notFoundInfo,
+			expectedFound: true,
+		},
+		{
+			name:          "outermost sentinel error wins",
+			inputErr:      xerr.MarkAs(fmt.Errorf("%w", errConflict), errNotFound),
+			expectedInfo:  notFoundInfo,
+			expectedFound: true,
+		},
+		{
+			name:          "multi error",
+			inputErr:      xerr.NewMultiError().Add(errUnknown).Add(errConflict),
+			expectedInfo:  conflictInfo,
+			expectedFound: true,
+		},
+		{
+			name:          "not registered error",
+			inputErr:      xerr.Wrap(errUnknown, "handle request"),
+			expectedInfo:  xerr.ErrorInfo{},
+			expectedFound: false,
+		},
+		{
+			name:          "non comparable sentinel error, not matched by equality",
+			inputErr:      errNonComp,
+			expectedInfo:  xerr.ErrorInfo{},
+			expectedFound: false,
+		},
+		{
+			name:          "nil error",
+			inputErr:      nil,
+			expectedInfo:  xerr.ErrorInfo{},
+			expectedFound: false,
+		},
+	}
+
+	assertNil(t, errNilIgnored)
+	for _, testData := range tests {
+		test := testData // capture range variable
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			// act
+			info, found := subject.Lookup(test.inputErr)
+
+			// assert
+			assertEqual(t, test.expectedFound, found)
+			assertEqual(t, test.expectedInfo, info)
+		})
+	}
+}
+
+func TestRegistry_concurrency(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		subject = xerr.NewRegistry()
+		wg      sync.WaitGroup
+	)
+
+	// act & assert
+	for i := 0; i < 20; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			_ = subject.Register(xerr.Const(fmt.Sprintf("err %d", i)), xerr.ErrorInfo{Code: "E"})
+		}(i)
+		go func(i int) {
+			defer wg.Done()
+			_, _ = subject.Lookup(xerr.Const(fmt.Sprintf("err %d", i)))
+		}(i)
+	}
+	wg.Wait()
+	_, found := subject.Lookup(xerr.Const("err 19"))
+	assertTrue(t, found)
+}
+
+// nonComparableErr is an error which cannot be compared with ==.
+type nonComparableErr struct {
+	msgs []string
+}
+
+func (err nonComparableErr) Error() string {
+	return err.msgs[0]
+}
@@ -0,0 +1,116 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr
+
+import (
+	"fmt"
+	"io"
+)
+
+// remoteError is an error decoded from its JSON form, see [FromJSON].
+// It holds the stack trace frames the error had in the process it was encoded in.
+type remoteError struct {
+	msg    string
+	frames []Frame
+}
+
+// Error returns the error's message.
+// Implements std error interface.
+func (err *remoteError) Error() string {
+	return err.msg
+}
+
+// Format implements [fmt.Formatter].
+// The extended format (%+v) includes the remote stack trace, in a "remote stack" section.
+func (err *remoteError) Format(f fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if f.Flag('+') {
+			_, _ = io.WriteString(f, err.msg)
+			err.writeStack(f)
+
+			return
+		}
+
+		fallthrough
+	case 's':
+		_, _ = io.WriteString(f, err.msg)
+	}
+}
+
+// StackFrames returns the remote stack trace frames.
+// Implements [StackTracer].
+// Note: the frames do not have their program counter set,
+// so they are not reused by wrapping errors.
+func (err *remoteError) StackFrames() []Frame {
+	return err.frames
+}
+
+// writeStack writes the "remote stack" section, if the error has frames.
+func (err *remoteError) writeStack(w io.Writer) {
+	if len(err.frames) == 0 {
+		return
+	}
+
+	_, _ = io.WriteString(w, "\nremote stack:")
+	for _, f := range err.frames {
+		writeFrame(w, f.Function, f.File, f.Line)
+	}
+}
+
+// FromJSON decodes an error from its JSON form (see [Serializer.Marshal]),
+// produced, for example, by another service, or read from logs.
+// The decoded error has the same message, and its severity, code, fields and ID restored,
+// so that [SeverityOf] / [CodeOf] / [Fields] / [ID] work identically on it.
+// Its original (remote) stack trace frames are returned by [Frames] / [RemoteFrames],
+// and are written in the extended format (%+v) as a "remote stack" section,
+// also when the decoded error gets wrapped.
+// JSON null is decoded as a nil error.
+// The second returned value is the eventual decoding error.
+//
+// Example:
+//
+//	err, decodeErr := xerr.FromJSON(body)
+//	if decodeErr == nil {
+//		fmt.Printf("%+v", xerr.Wrap(err, "could not call user service"))
+//	}
+func FromJSON(data []byte) (error, error) {
+	return defaultSerializer.Unmarshal(data)
+}
+
+// RemoteFrames returns the remote stack trace frames of an error decoded
+// with [FromJSON], the outermost one found in its chain.
+// Returns nil if there is no such error.
+func RemoteFrames(err error) []Frame {
+	if rErr := remoteErrorOf(err); rErr != nil {
+		return rErr.frames
+	}
+
+	return nil
+}
+
+// remoteErrorOf returns the outermost remote error, having a stack trace,
+// found in err's chain, if any.
+// A [MultiError] ends the search, as its errors are unrelated.
+func remoteErrorOf(err error) *remoteError {
+	var result *remoteError
+	walkChain(err, func(e error) bool {
+		switch x := e.(type) {
+		case *remoteError:
+			if len(x.frames) > 0 {
+				result = x
+
+				return false
+			}
+		case *MultiError:
+			return false
+		}
+
+		return true
+	})
+
+	return result
+}
@@ -0,0 +1,108 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr_test
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/actforgood/xerr"
+)
+
+func TestFromJSON(t *testing.T) {
+	t.Parallel()
+
+	t.Run("remote error is inspectable", testFromJSONInspectable)
+	t.Run("remote stack is printed", testFromJSONRemoteStackIsPrinted)
+	t.Run("null and invalid JSON", testFromJSONNullAndInvalid)
+}
+
+func testFromJSONInspectable(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	origErr := xerr.WithFields(
+		xerr.Wrap(xerr.NewCode("NOT_FOUND", "user not found"), "could not get user"),
+		xerr.F("user_id", "42"),
+	)
+	data, err := xerr.NewSerializer().Marshal(origErr)
+	assertNil(t, err)
+
+	// act
+	resultErr, err := xerr.FromJSON(data)
+
+	// assert
+	assertNil(t, err)
+	if assertNotNil(t, resultErr) {
+		assertEqual(t, origErr.Error(), resultErr.Error())
+		assertEqual(t, xerr.Code("NOT_FOUND"), xerr.CodeOf(resultErr))
+		assertEqual(t, map[string]any{"user_id": "42"}, xerr.Fields(resultErr))
+		frames := xerr.Frames(resultErr)
+		if assertEqual(t, len(xerr.Frames(origErr)), len(frames)) {
+			assertEqual(t, "github.com/actforgood/xerr_test.testFromJSONInspectable", frames[0].Function)
+			assertEqual(t, uintptr(0), frames[0].PC)
+		}
+		wrappedErr := xerr.Wrap(resultErr, "could not call user service")
+		assertEqual(t, frames, xerr.RemoteFrames(wrappedErr))
+		assertEqual(t, "github.com/actforgood/xerr_test.testFromJSONInspectable", xerr.Frames(wrappedErr)[0].Function)
+
+		// re-encoded as it was.
+		reData, err := xerr.NewSerializer().Marshal(resultErr)
+		assertNil(t, err)
+		assertEqual(t, string(data), string(reData))
+	}
+	assertNil(t, xerr.RemoteFrames(origErr))
+	assertNil(t, xerr.RemoteFrames(nil))
+}
+
+func testFromJSONRemoteStackIsPrinted(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	data := []byte(`{"message":"user not found","severity":"error","stack":[` +
+		`{"function":"main.getUser","file":"/app/user.go","line":42},` +
+		`{"function":"main.main","file":"/app/main.go","line":7}]}`)
+	remoteStack := "\nremote stack:\nmain.getUser\n\t/app/user.go:42\nmain.main\n\t/app/main.go:7"
+
+	// act
+	resultErr, err := xerr.FromJSON(data)
+
+	// assert
+	assertNil(t, err)
+	assertEqual(t, "user not found"+remoteStack, fmt.Sprintf("%+v", resultErr))
+	assertEqual(t, "user not found", fmt.Sprintf("%v", resultErr))
+	assertEqual(t, "user not found", fmt.Sprintf("%s", resultErr))
+
+	// act - wrapped locally
+	wrappedMsg := fmt.Sprintf("%+v", xerr.Wrap(resultErr, "could not call user service"))
+
+	// assert
+	matched, _ := regexp.MatchString(
+		`^could not call user service: user not found\n`+
+			`github\.com/actforgood/xerr_test\.testFromJSONRemoteStackIsPrinted\n\t.+remote_error_test\.go:\d+\n`,
+		wrappedMsg,
+	)
+	if !assertTrue(t, matched) {
+		t.Log(wrappedMsg)
+	}
+	assertTrue(t, strings.HasSuffix(wrappedMsg, remoteStack))
+}
+
+func testFromJSONNullAndInvalid(t *testing.T) {
+	t.Parallel()
+
+	// act
+	nilErr, err := xerr.FromJSON([]byte("null"))
+	resultErr, decodeErr := xerr.FromJSON([]byte("{"))
+
+	// assert
+	assertNil(t, nilErr)
+	assertNil(t, err)
+	assertNil(t, resultErr)
+	assertNotNil(t, decodeErr)
+}
@@ -0,0 +1,234 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"html/template"
+	"strconv"
+	"strings"
+)
+
+// renderOptions holds the configuration of [RenderHTML] / [RenderMarkdown].
+type renderOptions struct {
+	frameLink func(f Frame) string
+}
+
+// RenderOption defines optional function for configuring
+// [RenderHTML] / [RenderMarkdown].
+type RenderOption func(*renderOptions)
+
+// WithFrameLink configures the function returning the URL a stack trace
+// frame is linked to (empty URL means no link). By default, frames are not linked.
+//
+// Example:
+//
+//	xerr.RenderMarkdown(err, xerr.WithFrameLink(func(f xerr.Frame) string {
+//		return "https://github.com/myorg/myapp/blob/main/" + f.File + "#L" + strconv.Itoa(f.Line)
+//	}))
+func WithFrameLink(fn func(f Frame) string) RenderOption {
+	return func(opts *renderOptions) {
+		opts.frameLink = fn
+	}
+}
+
+// newRenderOptions returns the render configuration with given options applied.
+func newRenderOptions(opts []RenderOption) renderOptions {
+	var result renderOptions
+	for _, opt := range opts {
+		opt(&result)
+	}
+
+	return result
+}
+
+// link returns the URL of given frame, or empty string.
+func (o renderOptions) link(f Frame) string {
+	if o.frameLink == nil {
+		return ""
+	}
+
+	return o.frameLink(f)
+}
+
+// RenderHTML returns an HTML fragment describing an error, meant for debug web pages:
+// its message, followed by collapsible sections for its causes, fields,
+// stack trace and, in case of a [MultiError], for each of its errors.
+// Elements have "xerr-*" CSS classes, so that they can be styled.
+// Configured [SkipFrame], [FrameFnNameProcessor] and [FrameFileProcessor] are honored.
+// Returns empty fragment for a nil error.
+func RenderHTML(err error, opts ...RenderOption) template.HTML {
+	if err == nil {
+		return ""
+	}
+
+	var sb strings.Builder
+	renderHTML(&sb, err, newRenderOptions(opts))
+
+	return template.HTML(sb.String()) // content is escaped.
+}
+
+// renderHTML writes the HTML fragment describing given error.
+func renderHTML(sb *strings.Builder, err error, o renderOptions) {
+	sb.WriteString(`<div class="xerr"><p class="xerr-message">`)
+	sb.WriteString(html.EscapeString(err.Error()))
+	sb.WriteString(`</p>`)
+
+	if causes := causeMessages(err); len(causes) > 0 {
+		sb.WriteString(`<details class="xerr-causes"><summary>Causes (`)
+		sb.WriteString(strconv.Itoa(len(causes)))
+		sb.WriteString(`)</summary><ol>`)
+		for _, cause := range causes {
+			sb.WriteString(`<li>`)
+			sb.WriteString(html.EscapeString(cause))
+			sb.WriteString(`</li>`)
+		}
+		sb.WriteString(`</ol></details>`)
+	}
+
+	if fields := FieldsOf(err); len(fields) > 0 {
+		sb.WriteString(`<details class="xerr-fields"><summary>Fields</summary><dl>`)
+		for _, field := range fields {
+			sb.WriteString(`<dt>`)
+			sb.WriteString(html.EscapeString(field.Key))
+			sb.WriteString(`</dt><dd>`)
+			sb.WriteString(html.EscapeString(fmt.Sprint(field.Value)))
+			sb.WriteString(`</dd>`)
+		}
+		sb.WriteString(`</dl></details>`)
+	}
+
+	if frames := Frames(err); len(frames) > 0 {
+		sb.WriteString(`<details class="xerr-stack" open><summary>Stack trace</summary><ol>`)
+		for _, f := range frames {
+			sb.WriteString(`<li><code>`)
+			sb.WriteString(html.EscapeString(f.Function))
+			sb.WriteString(`</code><br>`)
+			location := html.EscapeString(f.File + ":" + strconv.Itoa(f.Line))
+			if url := o.link(f); url != "" {
+				sb.WriteString(`<a href="`)
+				sb.WriteString(html.EscapeString(url))
+				sb.WriteString(`">`)
+				sb.WriteString(location)
+				sb.WriteString(`</a>`)
+			} else {
+				sb.WriteString(location)
+			}
+			sb.WriteString(`</li>`)
+		}
+		sb.WriteString(`</ol></details>`)
+	}
+
+	var mErr *MultiError
+	if errors.As(err, &mErr) && mErr.ErrOrNil() != nil {
+		errs := mErr.Errors()
+		sb.WriteString(`<details class="xerr-errors" open><summary>Errors (`)
+		sb.WriteString(strconv.Itoa(len(errs)))
+		sb.WriteString(`)</summary>`)
+		for _, e := range errs {
+			renderHTML(sb, e, o)
+		}
+		sb.WriteString(`</details>`)
+	}
+
+	sb.WriteString(`</div>`)
+}
+
+// RenderMarkdown returns a Markdown document describing an error, meant for
+// auto-filed issue reports: its message, followed by collapsible sections for
+// its causes, fields, stack trace and, in case of a [MultiError], for each of its errors.
+// Configured [SkipFrame], [FrameFnNameProcessor] and [FrameFileProcessor] are honored.
+// Returns empty string for a nil error.
+func RenderMarkdown(err error, opts ...RenderOption) string {
+	if err == nil {
+		return ""
+	}
+
+	var sb strings.Builder
+	renderMarkdown(&sb, err, newRenderOptions(opts))
+
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+// renderMarkdown writes the Markdown document describing given error.
+func renderMarkdown(sb *strings.Builder, err error, o renderOptions) {
+	sb.WriteString("**")
+	sb.WriteString(markdownEscape(err.Error()))
+	sb.WriteString("**\n\n")
+
+	if causes := causeMessages(err); len(causes) > 0 {
+		sb.WriteString("<details>\n<summary>Causes (")
+		sb.WriteString(strconv.Itoa(len(causes)))
+		sb.WriteString(")</summary>\n\n")
+		for idx, cause := range causes {
+			sb.WriteString(strconv.Itoa(idx + 1))
+			sb.WriteString(". ")
+			sb.WriteString(markdownEscape(cause))
+			sb.WriteString("\n")
+		}
+		sb.WriteString("\n</details>\n\n")
+	}
+
+	if fields := FieldsOf(err); len(fields) > 0 {
+		sb.WriteString("<details>\n<summary>Fields</summary>\n\n")
+		for _, field := range fields {
+			sb.WriteString("- `")
+			sb.WriteString(field.Key)
+			sb.WriteString("`: ")
+			sb.WriteString(markdownEscape(fmt.Sprint(field.Value)))
+			sb.WriteString("\n")
+		}
+		sb.WriteString("\n</details>\n\n")
+	}
+
+	if frames := Frames(err); len(frames) > 0 {
+		sb.WriteString("<details open>\n<summary>Stack trace</summary>\n\n")
+		for idx, f := range frames {
+			sb.WriteString(strconv.Itoa(idx + 1))
+			sb.WriteString(". `")
+			sb.WriteString(f.Function)
+			sb.WriteString("` ")
+			location := f.File + ":" + strconv.Itoa(f.Line)
+			if url := o.link(f); url != "" {
+				sb.WriteString("[")
+				sb.WriteString(markdownEscape(location))
+				sb.WriteString("](")
+				sb.WriteString(url)
+				sb.WriteString(")")
+			} else {
+				sb.WriteString("`")
+				sb.WriteString(location)
+				sb.WriteString("`")
+			}
+			sb.WriteString("\n")
+		}
+		sb.WriteString("\n</details>\n\n")
+	}
+
+	var mErr *MultiError
+	if errors.As(err, &mErr) && mErr.ErrOrNil() != nil {
+		for idx, e := range mErr.Errors() {
+			sb.WriteString("<details>\n<summary>Error ")
+			sb.WriteString(strconv.Itoa(idx + 1))
+			sb.WriteString("</summary>\n\n")
+			renderMarkdown(sb, e, o)
+			sb.WriteString("</details>\n\n")
+		}
+	}
+}
+
+// markdownEscaper escapes Markdown special characters.
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", `*`, `\*`, `_`, `\_`, `[`, `\[`, `]`, `\]`,
+	`<`, `&lt;`, `>`, `&gt;`, `#`, `\#`, "\n", " ",
+)
+
+// markdownEscape returns given text with Markdown special characters escaped.
+func markdownEscape(text string) string {
+	return markdownEscaper.Replace(text)
+}
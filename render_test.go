@@ -0,0 +1,167 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr_test
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/actforgood/xerr"
+)
+
+func TestRenderHTML(t *testing.T) {
+	t.Parallel()
+
+	t.Run("error with stack", testRenderHTMLErrorWithStack)
+	t.Run("multi error", testRenderHTMLMultiError)
+	t.Run("nil error", testRenderHTMLNilError)
+}
+
+func testRenderHTMLErrorWithStack(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	origErr := xerr.New("<file> not found")
+	err := xerr.WithFields(
+		xerr.WrapOpt(origErr, "reading config", xerr.WithFrameFileProcessor(xerr.BaseNameOnly)),
+		xerr.F("path", "/etc/app.yml"),
+	)
+	link := func(f xerr.Frame) string {
+		return "https://example.com/" + f.File + "?a=1&b=" + strconv.Itoa(f.Line)
+	}
+	regexes := []string{
+		`^<div class="xerr"><p class="xerr-message">reading config: &lt;file&gt; not found</p>`,
+		`<details class="xerr-causes"><summary>Causes \(1\)</summary><ol><li>&lt;file&gt; not found</li></ol></details>`,
+		`<details class="xerr-fields"><summary>Fields</summary><dl><dt>path</dt><dd>/etc/app.yml</dd></dl></details>`,
+		`<details class="xerr-stack" open><summary>Stack trace</summary><ol>` +
+			`<li><code>github.com/actforgood/xerr_test.testRenderHTMLErrorWithStack</code><br>` +
+			`<a href="https://example.com/render_test.go\?a=1&amp;b=\d+">render_test.go:\d+</a></li>`,
+		`</ol></details></div>$`,
+	}
+
+	// act
+	result := xerr.RenderHTML(err, xerr.WithFrameLink(link))
+
+	// assert
+	for _, reg := range regexes {
+		matched, _ := regexp.MatchString(reg, string(result))
+		if !assertTrue(t, matched) {
+			t.Log("regex", reg, "result", result)
+		}
+	}
+	assertFalse(t, strings.Contains(string(xerr.RenderHTML(err)), "<a href"))
+}
+
+func testRenderHTMLMultiError(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	err := xerr.NewMultiError().Add(errors.New("err 1")).Add(errors.New("err 2"))
+
+	// act
+	result := xerr.RenderHTML(err)
+
+	// assert
+	assertEqual(
+		t,
+		`<div class="xerr"><p class="xerr-message">err 1`+"\n"+`err 2</p>`+
+			`<details class="xerr-errors" open><summary>Errors (2)</summary>`+
+			`<div class="xerr"><p class="xerr-message">err 1</p></div>`+
+			`<div class="xerr"><p class="xerr-message">err 2</p></div>`+
+			`</details></div>`,
+		string(result),
+	)
+}
+
+func testRenderHTMLNilError(t *testing.T) {
+	t.Parallel()
+
+	// act
+	result := xerr.RenderHTML(nil)
+
+	// assert
+	assertEqual(t, "", string(result))
+}
+
+func TestRenderMarkdown(t *testing.T) {
+	t.Parallel()
+
+	t.Run("error with stack", testRenderMarkdownErrorWithStack)
+	t.Run("multi error", testRenderMarkdownMultiError)
+	t.Run("nil error", testRenderMarkdownNilError)
+}
+
+func testRenderMarkdownErrorWithStack(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	origErr := xerr.New("*file* not found")
+	err := xerr.WithFields(
+		xerr.WrapOpt(origErr, "reading config", xerr.WithFrameFileProcessor(xerr.BaseNameOnly)),
+		xerr.F("path", "/etc/app.yml"),
+	)
+	link := func(f xerr.Frame) string {
+		return "https://example.com/" + f.File + "#L" + strconv.Itoa(f.Line)
+	}
+	regexes := []string{
+		`^\*\*reading config: \\\*file\\\* not found\*\*\n\n`,
+		"<details>\n<summary>Causes \\(1\\)</summary>\n\n1\\. \\\\\\*file\\\\\\* not found\n\n</details>\n\n",
+		"<details>\n<summary>Fields</summary>\n\n- `path`: /etc/app\\.yml\n\n</details>\n\n",
+		"<details open>\n<summary>Stack trace</summary>\n\n" +
+			"1\\. `github\\.com/actforgood/xerr_test\\.testRenderMarkdownErrorWithStack` " +
+			"\\[render\\\\_test\\.go:\\d+\\]\\(https://example\\.com/render_test\\.go#L\\d+\\)\n" +
+			"2\\. `github\\.com/actforgood/xerr_test\\.testRenderMarkdownErrorWithStack` \\[.+\\]\\(.+\\)\n" +
+			"3\\. `testing\\.tRunner` \\[.+\\]\\(.+\\)\n",
+		"\n\n</details>$",
+	}
+
+	// act
+	result := xerr.RenderMarkdown(err, xerr.WithFrameLink(link))
+
+	// assert
+	for _, reg := range regexes {
+		matched, _ := regexp.MatchString(reg, result)
+		if !assertTrue(t, matched) {
+			t.Log("regex", reg, "result", result)
+		}
+	}
+	assertTrue(t, strings.Contains(
+		xerr.RenderMarkdown(err),
+		"1. `github.com/actforgood/xerr_test.testRenderMarkdownErrorWithStack` `render_test.go:",
+	))
+}
+
+func testRenderMarkdownMultiError(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	err := xerr.NewMultiError().Add(errors.New("err 1")).Add(errors.New("err_2"))
+
+	// act
+	result := xerr.RenderMarkdown(err)
+
+	// assert
+	assertEqual(
+		t,
+		"**err 1 err\\_2**\n\n"+
+			"<details>\n<summary>Error 1</summary>\n\n**err 1**\n\n</details>\n\n"+
+			"<details>\n<summary>Error 2</summary>\n\n**err\\_2**\n\n</details>",
+		result,
+	)
+}
+
+func testRenderMarkdownNilError(t *testing.T) {
+	t.Parallel()
+
+	// act
+	result := xerr.RenderMarkdown(nil)
+
+	// assert
+	assertEqual(t, "", result)
+}
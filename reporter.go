@@ -0,0 +1,66 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr
+
+import (
+	"fmt"
+	"io"
+	"sync"
+)
+
+// Reporter reports errors somewhere (a log, an error tracker, etc.).
+type Reporter interface {
+	// Report reports the given error.
+	Report(err error)
+}
+
+// Flusher is an optional interface a [Reporter] can implement
+// if it reports errors asynchronously / buffered.
+type Flusher interface {
+	// Flush blocks until all the reported errors were delivered.
+	Flush()
+}
+
+// ReporterFunc is an adapter to allow the use of an ordinary function as a [Reporter].
+type ReporterFunc func(err error)
+
+// Report calls fn(err).
+func (fn ReporterFunc) Report(err error) {
+	fn(err)
+}
+
+// WriterReporter is a [Reporter] which writes the error,
+// in its extended form (%+v, including stack trace), to an [io.Writer].
+// Its APIs are concurrent safe.
+type WriterReporter struct {
+	w  io.Writer
+	mu sync.Mutex
+}
+
+// NewWriterReporter instantiates a new [WriterReporter] which writes to w.
+func NewWriterReporter(w io.Writer) *WriterReporter {
+	return &WriterReporter{w: w}
+}
+
+// Report writes the error, followed by a new line.
+func (r *WriterReporter) Report(err error) {
+	r.mu.Lock()
+	_, _ = fmt.Fprintf(r.w, "%+v\n", err)
+	r.mu.Unlock()
+}
+
+// report reports err to all given reporters, flushing those which are
+// Flushers afterwards.
+func report(err error, reporters []Reporter) {
+	for _, reporter := range reporters {
+		reporter.Report(err)
+	}
+	for _, reporter := range reporters {
+		if flusher, ok := reporter.(Flusher); ok {
+			flusher.Flush()
+		}
+	}
+}
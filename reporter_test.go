@@ -0,0 +1,51 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr_test
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+
+	"github.com/actforgood/xerr"
+)
+
+func TestWriterReporter(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		buf      bytes.Buffer
+		subject  = xerr.NewWriterReporter(&buf)
+		stackReg = `^something went bad\ngithub\.com/actforgood/xerr_test\.TestWriterReporter\n\t.+reporter_test\.go:\d+\n(.|\n)+\n$`
+	)
+
+	// act
+	subject.Report(xerr.New("something went bad"))
+
+	// assert
+	matched, _ := regexp.MatchString(stackReg, buf.String())
+	if !assertTrue(t, matched) {
+		t.Log("regex", stackReg, "output", buf.String())
+	}
+}
+
+func TestReporterFunc(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		reported error
+		subject  = xerr.ReporterFunc(func(err error) { reported = err })
+		inputErr = xerr.New("something went bad")
+	)
+
+	// act
+	subject.Report(inputErr)
+
+	// assert
+	assertEqual(t, inputErr, reported)
+}
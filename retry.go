@@ -0,0 +1,187 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr
+
+import (
+	"context"
+	"reflect"
+	"time"
+)
+
+// retryAfterKey is the annotation key under which an error's retry-after hint is stored.
+type retryAfterKey struct{}
+
+// WithRetryAfter returns an error annotating err with a backoff hint,
+// the duration after which the failed operation may be retried.
+// A negative duration is treated as 0.
+// If err is nil, WithRetryAfter returns nil.
+func WithRetryAfter(err error, d time.Duration) error {
+	if err == nil {
+		return nil
+	}
+	if d < 0 {
+		d = 0
+	}
+
+	return withValue(err, retryAfterKey{}, d)
+}
+
+// RetryAfter returns the backoff hint attached to an error,
+// the outermost one found in its chain, and whether it was found.
+func RetryAfter(err error) (time.Duration, bool) {
+	if d, found := lookupValue(err, retryAfterKey{}); found {
+		return d.(time.Duration), true
+	}
+
+	return 0, false
+}
+
+// Retry calls fn until it succeeds, or it fails with an error which is not retryable
+// (see [IsRetryable]), or it was called maxAttempts times (if maxAttempts > 0), or ctx is done.
+// Between attempts, it waits for the backoff hint of the failed attempt's error (see [RetryAfter]),
+// or for given backoff, if the error has no hint.
+// The last attempt's error is returned. If ctx got done while waiting, it is wrapped
+// with [WrapContext], so that [IsCanceled] / [IsDeadline] report accordingly.
+//
+// Example:
+//
+//	err := xerr.Retry(ctx, 3, 100*time.Millisecond, func(ctx context.Context) error {
+//		return client.SendEmail(ctx, msg)
+//	})
+func Retry(ctx context.Context, maxAttempts int, backoff time.Duration, fn func(ctx context.Context) error) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := fn(ctx)
+		if err == nil || !IsRetryable(err) || (maxAttempts > 0 && attempt >= maxAttempts) {
+			return err
+		}
+
+		wait := backoff
+		if d, found := RetryAfter(err); found {
+			wait = d
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+
+			return WrapContext(ctx, err, "")
+		case <-timer.C:
+		}
+	}
+}
+
+// retryableKey is the annotation key under which an error's retryability is stored.
+type retryableKey struct{}
+
+// MarkRetryable returns an error annotating err as retryable,
+// see [IsRetryable]. The mark survives wrapping.
+// If err is nil, MarkRetryable returns nil.
+func MarkRetryable(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return withValue(err, retryableKey{}, true)
+}
+
+// MarkPermanent returns an error annotating err as not retryable,
+// see [IsRetryable]. The mark survives wrapping.
+// If err is nil, MarkPermanent returns nil.
+func MarkPermanent(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return withValue(err, retryableKey{}, false)
+}
+
+// gRPC status codes that denote a retryable failure.
+// They are hard-coded in order to not depend on gRPC module.
+const (
+	grpcCodeResourceExhausted = 8
+	grpcCodeUnavailable       = 14
+)
+
+// IsRetryable checks whether the operation which failed with given error
+// can be retried. The error's chain is inspected from the outermost error
+// inwards, and the first error expressing an opinion decides:
+//   - an error marked with [MarkRetryable] / [MarkPermanent];
+//   - an error with a backoff hint (see [WithRetryAfter]) is retryable;
+//   - an error implementing Retryable() bool;
+//   - an error implementing Timeout() bool (like [net.Error]), if it returns true;
+//   - an error implementing Temporary() bool;
+//   - a gRPC status error (implementing GRPCStatus()), which is retryable
+//     if its code is Unavailable or ResourceExhausted.
+//
+// Returns false if no opinion was found.
+func IsRetryable(err error) bool {
+	var retryable bool
+	walkChain(err, func(e error) bool {
+		var decided bool
+		retryable, decided = retryableOpinion(e)
+
+		return !decided
+	})
+
+	return retryable
+}
+
+// retryableOpinion returns the opinion of given error (not its chain)
+// about being retryable, if it has one.
+func retryableOpinion(err error) (retryable, decided bool) {
+	if vErr, ok := err.(*valueError); ok {
+		switch vErr.key {
+		case retryableKey{}:
+			return vErr.val.(bool), true
+		case retryAfterKey{}:
+			return true, true
+		}
+	}
+	if x, ok := err.(interface{ Retryable() bool }); ok {
+		return x.Retryable(), true
+	}
+	if x, ok := err.(interface{ Timeout() bool }); ok && x.Timeout() {
+		return true, true
+	}
+	if x, ok := err.(interface{ Temporary() bool }); ok {
+		return x.Temporary(), true
+	}
+	if code, ok := grpcCode(err); ok {
+		return code == grpcCodeUnavailable || code == grpcCodeResourceExhausted, true
+	}
+
+	return false, false
+}
+
+// grpcCode returns the gRPC status code of an error implementing
+// GRPCStatus() *status.Status, without depending on gRPC module.
+func grpcCode(err error) (uint64, bool) {
+	method := reflect.ValueOf(err).MethodByName("GRPCStatus")
+	if !method.IsValid() || method.Type().NumIn() != 0 || method.Type().NumOut() != 1 {
+		return 0, false
+	}
+	st := method.Call(nil)[0]
+	if st.Kind() == reflect.Ptr && st.IsNil() {
+		return 0, false
+	}
+	codeMethod := st.MethodByName("Code")
+	if !codeMethod.IsValid() || codeMethod.Type().NumIn() != 0 || codeMethod.Type().NumOut() != 1 {
+		return 0, false
+	}
+	code := codeMethod.Call(nil)[0]
+	switch code.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return code.Uint(), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return uint64(code.Int()), true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,351 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/actforgood/xerr"
+)
+
+func TestRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		subject = xerr.RetryAfter
+		stdErr  = errors.New("some standard error")
+		tests   = [...]struct {
+			name          string
+			inputErr      error
+			expectedDur   time.Duration
+			expectedFound bool
+		}{
+			{
+				name:          "nil error",
+				inputErr:      nil,
+				expectedDur:   0,
+				expectedFound: false,
+			},
+			{
+				name:          "error without retry-after",
+				inputErr:      stdErr,
+				expectedDur:   0,
+				expectedFound: false,
+			},
+			{
+				name:          "error with retry-after",
+				inputErr:      xerr.WithRetryAfter(stdErr, 3*time.Second),
+				expectedDur:   3 * time.Second,
+				expectedFound: true,
+			},
+			{
+				name:          "error with negative retry-after",
+				inputErr:      xerr.WithRetryAfter(stdErr, -time.Second),
+				expectedDur:   0,
+				expectedFound: true,
+			},
+			{
+				name: "wrapped error with retry-after",
+				inputErr: fmt.Errorf(
+					"wrap: %w",
+					xerr.Wrap(xerr.WithRetryAfter(stdErr, time.Minute), "wrap"),
+				),
+				expectedDur:   time.Minute,
+				expectedFound: true,
+			},
+			{
+				name:          "error with multiple retry-after, expect outermost",
+				inputErr:      xerr.WithRetryAfter(xerr.WithRetryAfter(stdErr, time.Minute), time.Second),
+				expectedDur:   time.Second,
+				expectedFound: true,
+			},
+		}
+	)
+
+	for _, testData := range tests {
+		test := testData // capture range variable
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			// act
+			resultDur, resultFound := subject(test.inputErr)
+
+			// assert
+			assertEqual(t, test.expectedDur, resultDur)
+			assertEqual(t, test.expectedFound, resultFound)
+		})
+	}
+
+	assertNil(t, xerr.WithRetryAfter(nil, time.Second))
+}
+
+type retryableErr bool
+
+func (retryableErr) Error() string     { return "retryable error" }
+func (e retryableErr) Retryable() bool { return bool(e) }
+
+type temporaryErr bool
+
+func (temporaryErr) Error() string     { return "temporary error" }
+func (e temporaryErr) Temporary() bool { return bool(e) }
+
+type timeoutErr bool
+
+func (timeoutErr) Error() string   { return "timeout error" }
+func (e timeoutErr) Timeout() bool { return bool(e) }
+
+// grpcCodeMock mimics google.golang.org/grpc/codes.Code.
+type grpcCodeMock uint32
+
+// grpcStatusMock mimics google.golang.org/grpc/status.Status.
+type grpcStatusMock struct{ code grpcCodeMock }
+
+func (st *grpcStatusMock) Code() grpcCodeMock { return st.code }
+
+type grpcStatusErr struct{ st *grpcStatusMock }
+
+func (grpcStatusErr) Error() string                 { return "grpc error" }
+func (e grpcStatusErr) GRPCStatus() *grpcStatusMock { return e.st }
+
+func TestIsRetryable(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		subject = xerr.IsRetryable
+		stdErr  = errors.New("some standard error")
+		tests   = [...]struct {
+			name     string
+			inputErr error
+			expected bool
+		}{
+			{
+				name:     "nil error",
+				inputErr: nil,
+				expected: false,
+			},
+			{
+				name:     "standard error",
+				inputErr: stdErr,
+				expected: false,
+			},
+			{
+				name:     "error with retry-after",
+				inputErr: xerr.Wrap(xerr.WithRetryAfter(stdErr, time.Second), "wrap"),
+				expected: true,
+			},
+			{
+				name:     "marked retryable",
+				inputErr: fmt.Errorf("wrap: %w", xerr.Wrap(xerr.MarkRetryable(stdErr), "wrap")),
+				expected: true,
+			},
+			{
+				name:     "marked permanent",
+				inputErr: xerr.Wrap(xerr.MarkPermanent(timeoutErr(true)), "wrap"),
+				expected: false,
+			},
+			{
+				name:     "marked permanent, outermost mark wins",
+				inputErr: xerr.MarkPermanent(xerr.MarkRetryable(stdErr)),
+				expected: false,
+			},
+			{
+				name:     "marked retryable, outermost mark wins",
+				inputErr: xerr.MarkRetryable(xerr.WithRetryAfter(xerr.MarkPermanent(stdErr), time.Second)),
+				expected: true,
+			},
+			{
+				name:     "Retryable() true",
+				inputErr: xerr.Wrap(retryableErr(true), "wrap"),
+				expected: true,
+			},
+			{
+				name:     "Retryable() false",
+				inputErr: retryableErr(false),
+				expected: false,
+			},
+			{
+				name:     "Temporary() true",
+				inputErr: fmt.Errorf("wrap: %w", temporaryErr(true)),
+				expected: true,
+			},
+			{
+				name:     "Temporary() false",
+				inputErr: temporaryErr(false),
+				expected: false,
+			},
+			{
+				name:     "Timeout() true",
+				inputErr: timeoutErr(true),
+				expected: true,
+			},
+			{
+				name:     "Timeout() false, no opinion",
+				inputErr: timeoutErr(false),
+				expected: false,
+			},
+			{
+				name:     "net timeout error",
+				inputErr: &net.OpError{Op: "dial", Net: "tcp", Err: os.ErrDeadlineExceeded},
+				expected: true,
+			},
+			{
+				name:     "gRPC Unavailable",
+				inputErr: grpcStatusErr{st: &grpcStatusMock{code: 14}},
+				expected: true,
+			},
+			{
+				name:     "gRPC ResourceExhausted",
+				inputErr: xerr.Wrap(grpcStatusErr{st: &grpcStatusMock{code: 8}}, "wrap"),
+				expected: true,
+			},
+			{
+				name:     "gRPC InvalidArgument",
+				inputErr: grpcStatusErr{st: &grpcStatusMock{code: 3}},
+				expected: false,
+			},
+			{
+				name:     "gRPC nil status",
+				inputErr: grpcStatusErr{},
+				expected: false,
+			},
+			{
+				name:     "outermost opinion wins",
+				inputErr: xerr.WithRetryAfter(retryableErr(false), time.Second),
+				expected: true,
+			},
+			{
+				name:     "MultiError, first opinion wins",
+				inputErr: xerr.NewMultiError().Add(stdErr, retryableErr(true), retryableErr(false)),
+				expected: true,
+			},
+		}
+	)
+
+	for _, testData := range tests {
+		test := testData // capture range variable
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			// act
+			result := subject(test.inputErr)
+
+			// assert
+			assertEqual(t, test.expected, result)
+		})
+	}
+
+	assertNil(t, xerr.MarkRetryable(nil))
+	assertNil(t, xerr.MarkPermanent(nil))
+}
+
+func TestRetry(t *testing.T) {
+	t.Parallel()
+
+	t.Run("backoff hint is honored", testRetryHonorsRetryAfter)
+	t.Run("not retryable error is returned at once", testRetryNotRetryable)
+	t.Run("max attempts", testRetryMaxAttempts)
+	t.Run("context done while waiting", testRetryContextDone)
+}
+
+func testRetryHonorsRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		attempts int
+		start    = time.Now()
+	)
+
+	// act
+	err := xerr.Retry(context.Background(), 0, time.Hour, func(context.Context) error {
+		attempts++
+		if attempts < 3 {
+			return xerr.WithRetryAfter(errors.New("rate limited"), 5*time.Millisecond)
+		}
+
+		return nil
+	})
+
+	// assert
+	assertNil(t, err)
+	assertEqual(t, 3, attempts)
+	elapsed := time.Since(start)
+	assertTrue(t, elapsed >= 10*time.Millisecond && elapsed < time.Hour)
+}
+
+func testRetryNotRetryable(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		attempts  int
+		permanent = xerr.MarkPermanent(errors.New("invalid input"))
+	)
+
+	// act
+	err := xerr.Retry(context.Background(), 5, time.Hour, func(context.Context) error {
+		attempts++
+
+		return permanent
+	})
+
+	// assert
+	assertEqual(t, permanent, err)
+	assertEqual(t, 1, attempts)
+}
+
+func testRetryMaxAttempts(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var attempts int
+
+	// act
+	err := xerr.Retry(context.Background(), 3, time.Millisecond, func(context.Context) error {
+		attempts++
+
+		return xerr.MarkRetryable(fmt.Errorf("attempt %d failed", attempts))
+	})
+
+	// assert
+	assertEqual(t, 3, attempts)
+	assertEqual(t, "attempt 3 failed", err.Error())
+}
+
+func testRetryContextDone(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		attempts    int
+		origErr     = xerr.WithRetryAfter(errors.New("unavailable"), time.Hour)
+		ctx, cancel = context.WithTimeout(context.Background(), 5*time.Millisecond)
+	)
+	defer cancel()
+
+	// act
+	err := xerr.Retry(ctx, 0, 0, func(context.Context) error {
+		attempts++
+
+		return origErr
+	})
+
+	// assert
+	assertEqual(t, 1, attempts)
+	assertTrue(t, errors.Is(err, origErr))
+	assertTrue(t, xerr.IsDeadline(err))
+	d, found := xerr.RetryAfter(err)
+	assertTrue(t, found)
+	assertEqual(t, time.Hour, d)
+}
@@ -0,0 +1,273 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// defaultSerializer is the [Serializer] used by package level APIs.
+var defaultSerializer = NewSerializer()
+
+// Serializer converts errors into a machine readable (JSON) form.
+// The zero value is ready to use, with stack traces included for all errors.
+type Serializer struct {
+	stackMinSeverity Severity
+}
+
+// SerializerOption defines optional function for configuring a [Serializer].
+type SerializerOption func(*Serializer)
+
+// WithStackMinSeverity configures the [Serializer] to include stack traces
+// only for errors with a severity at or above given one.
+// Errors with lower severity are serialized without stack trace.
+//
+// Example:
+//
+//	// stacks for Error/Fatal, messages only for Warn and lower.
+//	s := xerr.NewSerializer(xerr.WithStackMinSeverity(xerr.SeverityError))
+func WithStackMinSeverity(sev Severity) SerializerOption {
+	return func(s *Serializer) {
+		s.stackMinSeverity = sev
+	}
+}
+
+// NewSerializer instantiates a new [Serializer] configured with given options.
+func NewSerializer(opts ...SerializerOption) *Serializer {
+	s := new(Serializer)
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Marshal returns the JSON encoding of an error.
+// The JSON object has the following keys (some of them may be missing):
+//
+//	message   the error's message.
+//	severity  the error's severity.
+//	code      the error's code.
+//	field     the invalid field, if the error is a *[FieldViolation].
+//	name      the error's name, if it was stored with [MultiError.AddNamed].
+//	id        the error's unique ID, see [SetErrorIDGenerator].
+//	build     the error's build info, as a {version, revision, hostname, pid} object, see [WithBuildInfo].
+//	fields    the error's fields.
+//	stack     the error's stack trace, as a list of {function, file, line} objects.
+//	errors    the errors stored, if the error is a [MultiError].
+//
+// A nil error is encoded as JSON null.
+func (s *Serializer) Marshal(err error) ([]byte, error) {
+	if err == nil {
+		return []byte("null"), nil
+	}
+
+	return json.Marshal(s.serialize(err))
+}
+
+// Unmarshal decodes an error previously encoded with [Serializer.Marshal],
+// for example by another service.
+// The decoded error has the same message, and its severity, code, fields, ID and build info
+// restored, so that [SeverityOf] / [CodeOf] / [Fields] / [ID] / [BuildInfoOf] work identically on it,
+// and its stack trace frames, as remote ones (see [FromJSON]).
+// An encoded [MultiError] is decoded as a [MultiError].
+// JSON null is decoded as a nil error.
+// The second returned value is the eventual decoding error.
+func (s *Serializer) Unmarshal(data []byte) (error, error) {
+	var jErr *jsonError
+	if err := json.Unmarshal(data, &jErr); err != nil {
+		return nil, err
+	}
+	if jErr == nil {
+		return nil, nil
+	}
+
+	return s.deserialize(*jErr), nil
+}
+
+// serialize converts an error into its JSON model.
+func (s *Serializer) serialize(err error) jsonError {
+	sev := SeverityOf(err)
+	jErr := jsonError{
+		Message:  err.Error(),
+		Severity: sev.String(),
+		Code:     string(CodeOf(err)),
+	}
+
+	var (
+		sErr *stackError
+		rErr *remoteError
+		mErr *MultiError
+	)
+	// walk the chain until a MultiError is encountered, if any,
+	// its stored errors will be serialized individually.
+	for e, layer := err, 0; e != nil && layer < maxChainDepth; layer++ {
+		jErr.Fields = collectFields(jErr.Fields, e)
+		switch x := e.(type) {
+		case *stackError:
+			if sErr == nil && len(x.stackPCs) > 0 {
+				sErr = x
+			}
+			if jErr.ID == "" {
+				jErr.ID = x.id
+			}
+			if jErr.Build == nil {
+				jErr.Build = x.build
+			}
+		case *valueError:
+			if id, ok := x.val.(string); ok && x.key == (errorIDKey{}) && jErr.ID == "" {
+				jErr.ID = id
+			}
+			if info, ok := x.val.(*BuildInfo); ok && x.key == (buildInfoKey{}) && jErr.Build == nil {
+				jErr.Build = info
+			}
+		case *remoteError:
+			if rErr == nil && len(x.frames) > 0 {
+				rErr = x
+			}
+		case *MultiError:
+			mErr = x
+		case *FieldViolation:
+			jErr.Field = x.Field
+		case *namedError:
+			if jErr.Name == "" {
+				jErr.Name = x.name
+			}
+		}
+		if mErr != nil {
+			break
+		}
+		unwrapper, ok := e.(interface{ Unwrap() error })
+		if !ok {
+			break
+		}
+		e = unwrapper.Unwrap()
+	}
+
+	if sev >= s.stackMinSeverity {
+		var frames []Frame
+		if sErr != nil {
+			frames = sErr.frames()
+		} else if rErr != nil { // a decoded error, re-encoded as it was.
+			frames = rErr.frames
+		}
+		for _, f := range frames {
+			jErr.Stack = append(jErr.Stack, jsonFrame{
+				Function: f.Function,
+				File:     f.File,
+				Line:     f.Line,
+			})
+		}
+	}
+
+	if mErr != nil {
+		for _, e := range mErr.Errors() {
+			jErr.Errors = append(jErr.Errors, s.serialize(e))
+		}
+	}
+
+	return jErr
+}
+
+// deserialize converts an error's JSON model into an error.
+func (s *Serializer) deserialize(jErr jsonError) error {
+	if jErr.Name != "" {
+		name := jErr.Name
+		jErr.Name = ""
+		jErr.Message = strings.TrimPrefix(jErr.Message, name+": ")
+
+		return &namedError{name: name, err: s.deserialize(jErr)}
+	}
+
+	var err error
+	if len(jErr.Errors) > 0 {
+		mErr := newMultiError()
+		for _, jSubErr := range jErr.Errors {
+			_ = mErr.Add(s.deserialize(jSubErr))
+		}
+		err = mErr
+	} else if jErr.Field != "" {
+		err = &FieldViolation{
+			Field:       jErr.Field,
+			Description: strings.TrimPrefix(jErr.Message, jErr.Field+": "),
+		}
+	} else {
+		rErr := &remoteError{msg: jErr.Message}
+		if len(jErr.Stack) > 0 {
+			rErr.frames = make([]Frame, len(jErr.Stack))
+			for idx, f := range jErr.Stack {
+				rErr.frames[idx] = Frame{Function: f.Function, File: f.File, Line: f.Line}
+			}
+		}
+		err = rErr
+	}
+
+	if sev, ok := parseSeverity(jErr.Severity); ok && sev != SeverityError {
+		err = WithSeverity(err, sev)
+	}
+	if jErr.Code != "" {
+		err = WithCode(err, Code(jErr.Code))
+	}
+	if jErr.ID != "" {
+		err = withValue(err, errorIDKey{}, jErr.ID)
+	}
+	if jErr.Build != nil {
+		err = withValue(err, buildInfoKey{}, jErr.Build)
+	}
+
+	return withFields(err, jErr.Fields)
+}
+
+// JSONFormat returns a [fmt.Formatter] which writes the JSON form of an error
+// (see [Serializer.Marshal]), regardless of the verb used.
+// It is useful for opting into machine readable error output at existing
+// printf-style logging call sites:
+//
+//	log.Printf("could not handle request: %v", xerr.JSONFormat(err))
+func JSONFormat(err error) fmt.Formatter {
+	return jsonFormatter{err: err, s: defaultSerializer}
+}
+
+// jsonFormatter writes the JSON form of an error.
+type jsonFormatter struct {
+	err error
+	s   *Serializer
+}
+
+// Format implements [fmt.Formatter].
+func (jf jsonFormatter) Format(f fmt.State, _ rune) {
+	data, err := jf.s.Marshal(jf.err)
+	if err != nil { // fields may hold values that cannot be JSON encoded.
+		data, _ = json.Marshal(jsonError{
+			Message:  jf.err.Error(),
+			Severity: SeverityOf(jf.err).String(),
+		})
+	}
+	_, _ = f.Write(data)
+}
+
+// jsonError is the JSON model of an error.
+type jsonError struct {
+	Message  string         `json:"message"`
+	Severity string         `json:"severity"`
+	Code     string         `json:"code,omitempty"`
+	Field    string         `json:"field,omitempty"`
+	Name     string         `json:"name,omitempty"`
+	ID       string         `json:"id,omitempty"`
+	Build    *BuildInfo     `json:"build,omitempty"`
+	Fields   map[string]any `json:"fields,omitempty"`
+	Stack    []jsonFrame    `json:"stack,omitempty"`
+	Errors   []jsonError    `json:"errors,omitempty"`
+}
+
+// jsonFrame is the JSON model of a stack trace frame.
+type jsonFrame struct {
+	Function string `json:"function"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+}
@@ -0,0 +1,310 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/actforgood/xerr"
+)
+
+func TestSerializer_Marshal(t *testing.T) {
+	t.Run("stack included for all severities by default", testSerializerMarshalDefault)
+	t.Run("stack included only from min severity", testSerializerMarshalStackMinSeverity)
+	t.Run("MultiError", testSerializerMarshalMultiError)
+	t.Run("nil error", testSerializerMarshalNilError)
+}
+
+func testSerializerMarshalDefault(t *testing.T) {
+	// arrange
+	var (
+		subject  = xerr.NewSerializer()
+		inputErr = xerr.WithSeverity(xerr.New("something went bad"), xerr.SeverityDebug)
+		result   map[string]any
+	)
+
+	// act
+	data, err := subject.Marshal(inputErr)
+
+	// assert
+	assertNil(t, err)
+	assertNil(t, json.Unmarshal(data, &result))
+	assertEqual(t, "something went bad", result["message"])
+	assertEqual(t, "debug", result["severity"])
+	stack, _ := result["stack"].([]any)
+	if assertTrue(t, len(stack) > 0) {
+		topFrame, _ := stack[0].(map[string]any)
+		assertEqual(t, "github.com/actforgood/xerr_test.testSerializerMarshalDefault", topFrame["function"])
+		assertNotNil(t, topFrame["file"])
+		assertNotNil(t, topFrame["line"])
+	}
+}
+
+func testSerializerMarshalStackMinSeverity(t *testing.T) {
+	// arrange
+	var (
+		subject = xerr.NewSerializer(xerr.WithStackMinSeverity(xerr.SeverityError))
+		tests   = [...]struct {
+			name          string
+			inputErr      error
+			expectedStack bool
+		}{
+			{
+				name:          "warn error, no stack",
+				inputErr:      xerr.WithSeverity(xerr.New("warn"), xerr.SeverityWarn),
+				expectedStack: false,
+			},
+			{
+				name:          "default error severity, with stack",
+				inputErr:      xerr.New("error"),
+				expectedStack: true,
+			},
+			{
+				name:          "fatal error, with stack",
+				inputErr:      xerr.WithSeverity(xerr.New("fatal"), xerr.SeverityFatal),
+				expectedStack: true,
+			},
+			{
+				name:          "standard error, no stack",
+				inputErr:      errors.New("std"),
+				expectedStack: false,
+			},
+		}
+	)
+
+	for _, testData := range tests {
+		test := testData // capture range variable
+		t.Run(test.name, func(t *testing.T) {
+			var result map[string]any
+
+			// act
+			data, err := subject.Marshal(test.inputErr)
+
+			// assert
+			assertNil(t, err)
+			assertNil(t, json.Unmarshal(data, &result))
+			_, hasStack := result["stack"]
+			assertEqual(t, test.expectedStack, hasStack)
+		})
+	}
+}
+
+func testSerializerMarshalMultiError(t *testing.T) {
+	// arrange
+	var (
+		subject  = xerr.NewSerializer(xerr.WithStackMinSeverity(xerr.SeverityError))
+		inputErr = xerr.NewMultiError().Add(
+			xerr.WithSeverity(xerr.New("warn"), xerr.SeverityWarn),
+			xerr.New("error"),
+		)
+		result struct {
+			Message string `json:"message"`
+			Errors  []struct {
+				Message  string `json:"message"`
+				Severity string `json:"severity"`
+				Stack    []any  `json:"stack"`
+			} `json:"errors"`
+		}
+	)
+
+	// act
+	data, err := subject.Marshal(inputErr)
+
+	// assert
+	assertNil(t, err)
+	assertNil(t, json.Unmarshal(data, &result))
+	assertEqual(t, "warn\nerror", result.Message)
+	if assertEqual(t, 2, len(result.Errors)) {
+		assertEqual(t, "warn", result.Errors[0].Message)
+		assertEqual(t, "warn", result.Errors[0].Severity)
+		assertEqual(t, 0, len(result.Errors[0].Stack))
+		assertEqual(t, "error", result.Errors[1].Message)
+		assertEqual(t, "error", result.Errors[1].Severity)
+		assertTrue(t, len(result.Errors[1].Stack) > 0)
+	}
+}
+
+func testSerializerMarshalNilError(t *testing.T) {
+	// arrange
+	subject := xerr.NewSerializer()
+
+	// act
+	data, err := subject.Marshal(nil)
+
+	// assert
+	assertNil(t, err)
+	assertEqual(t, "null", string(data))
+}
+
+func TestSerializer_Unmarshal(t *testing.T) {
+	t.Run("round trip", testSerializerUnmarshalRoundTrip)
+	t.Run("MultiError round trip", testSerializerUnmarshalMultiErrorRoundTrip)
+	t.Run("named errors round trip", testSerializerUnmarshalNamedRoundTrip)
+	t.Run("null", testSerializerUnmarshalNull)
+	t.Run("invalid JSON", testSerializerUnmarshalInvalidJSON)
+}
+
+func testSerializerUnmarshalRoundTrip(t *testing.T) {
+	// arrange
+	var (
+		subject = xerr.NewSerializer()
+		ctx     = context.WithValue(context.Background(), ctxTestKey("request_id"), "req-123")
+		origErr = xerr.WithCode(
+			xerr.WithSeverity(
+				xerr.WrapCtx(ctx, errors.New("some standard error"), "something went bad"),
+				xerr.SeverityWarn,
+			),
+			"E001",
+		)
+	)
+	data, err := subject.Marshal(origErr)
+	assertNil(t, err)
+
+	// act
+	resultErr, err := subject.Unmarshal(data)
+
+	// assert
+	assertNil(t, err)
+	if assertNotNil(t, resultErr) {
+		assertEqual(t, origErr.Error(), resultErr.Error())
+		assertEqual(t, xerr.SeverityWarn, xerr.SeverityOf(resultErr))
+		assertEqual(t, xerr.Code("E001"), xerr.CodeOf(resultErr))
+		assertEqual(t, map[string]any{"request_id": "req-123"}, xerr.Fields(resultErr))
+	}
+}
+
+func testSerializerUnmarshalMultiErrorRoundTrip(t *testing.T) {
+	// arrange
+	var (
+		subject = xerr.NewSerializer()
+		ctx     = context.WithValue(context.Background(), ctxTestKey("tenant"), "acme")
+		origErr = xerr.NewMultiError().Add(
+			xerr.WithSeverity(errors.New("warn"), xerr.SeverityWarn),
+			xerr.NewCtx(ctx, "error"),
+		)
+	)
+	data, err := subject.Marshal(origErr)
+	assertNil(t, err)
+
+	// act
+	resultErr, err := subject.Unmarshal(data)
+
+	// assert
+	assertNil(t, err)
+	var mErr *xerr.MultiError
+	if assertTrue(t, errors.As(resultErr, &mErr)) {
+		assertEqual(t, origErr.Error(), mErr.Error())
+		errs := mErr.Errors()
+		if assertEqual(t, 2, len(errs)) {
+			assertEqual(t, xerr.SeverityWarn, xerr.SeverityOf(errs[0]))
+			assertEqual(t, xerr.SeverityError, xerr.SeverityOf(errs[1]))
+			assertEqual(t, map[string]any{"tenant": "acme"}, xerr.Fields(errs[1]))
+		}
+	}
+}
+
+func testSerializerUnmarshalNamedRoundTrip(t *testing.T) {
+	// arrange
+	var (
+		subject = xerr.NewSerializer()
+		origErr = xerr.NewMultiError().
+			AddNamed("users.csv", xerr.WithCode(errors.New("bad header"), "E_HEADER")).
+			Add(errors.New("unnamed"))
+		jsonResult struct {
+			Errors []struct {
+				Name    string `json:"name"`
+				Message string `json:"message"`
+			} `json:"errors"`
+		}
+	)
+	data, err := subject.Marshal(origErr)
+	assertNil(t, err)
+	assertNil(t, json.Unmarshal(data, &jsonResult))
+	if assertEqual(t, 2, len(jsonResult.Errors)) {
+		assertEqual(t, "users.csv", jsonResult.Errors[0].Name)
+		assertEqual(t, "users.csv: bad header", jsonResult.Errors[0].Message)
+		assertEqual(t, "", jsonResult.Errors[1].Name)
+	}
+
+	// act
+	resultErr, err := subject.Unmarshal(data)
+
+	// assert
+	assertNil(t, err)
+	var mErr *xerr.MultiError
+	if assertTrue(t, errors.As(resultErr, &mErr)) {
+		assertEqual(t, origErr.Error(), mErr.Error())
+		errs := mErr.Errors()
+		if assertEqual(t, 2, len(errs)) {
+			assertEqual(t, "users.csv", xerr.NameOf(errs[0]))
+			assertEqual(t, xerr.Code("E_HEADER"), xerr.CodeOf(errs[0]))
+			assertEqual(t, "", xerr.NameOf(errs[1]))
+		}
+	}
+}
+
+func testSerializerUnmarshalNull(t *testing.T) {
+	// arrange
+	subject := xerr.NewSerializer()
+
+	// act
+	resultErr, err := subject.Unmarshal([]byte("null"))
+
+	// assert
+	assertNil(t, err)
+	assertNil(t, resultErr)
+}
+
+func testSerializerUnmarshalInvalidJSON(t *testing.T) {
+	// arrange
+	subject := xerr.NewSerializer()
+
+	// act
+	resultErr, err := subject.Unmarshal([]byte("{invalid"))
+
+	// assert
+	assertNotNil(t, err)
+	assertNil(t, resultErr)
+}
+
+func TestJSONFormat(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		ctx       = context.WithValue(context.Background(), ctxTestKey("request_id"), "req-json")
+		inputErr  = xerr.WrapCtx(ctx, errors.New("some standard error"), "something went bad")
+		badFields = xerr.WrapCtx(
+			context.WithValue(context.Background(), ctxTestKey("raw"), make(chan int)),
+			errors.New("some standard error"),
+			"something went bad",
+		)
+	)
+
+	for _, format := range [...]string{"%v", "%+v", "%s"} {
+		// act
+		result := fmt.Sprintf(format, xerr.JSONFormat(inputErr))
+
+		// assert
+		var decoded map[string]any
+		if assertNil(t, json.Unmarshal([]byte(result), &decoded)) {
+			assertEqual(t, "something went bad: some standard error", decoded["message"])
+			assertEqual(t, map[string]any{"request_id": "req-json"}, decoded["fields"])
+			assertNotNil(t, decoded["stack"])
+		}
+	}
+
+	// act
+	result := fmt.Sprintf("%v", xerr.JSONFormat(badFields))
+
+	// assert
+	assertEqual(t, `{"message":"something went bad: some standard error","severity":"error"}`, result)
+	assertEqual(t, "null", fmt.Sprintf("%v", xerr.JSONFormat(nil)))
+}
@@ -0,0 +1,73 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr
+
+import "strconv"
+
+// Severity describes how serious an error is.
+type Severity int
+
+// Severities, in ascending order.
+const (
+	SeverityDebug Severity = iota
+	SeverityInfo
+	SeverityWarn
+	SeverityError
+	SeverityFatal
+)
+
+// String returns the severity's name.
+// Implements [fmt.Stringer].
+func (sev Severity) String() string {
+	switch sev {
+	case SeverityDebug:
+		return "debug"
+	case SeverityInfo:
+		return "info"
+	case SeverityWarn:
+		return "warn"
+	case SeverityError:
+		return "error"
+	case SeverityFatal:
+		return "fatal"
+	default:
+		return "severity(" + strconv.FormatInt(int64(sev), 10) + ")"
+	}
+}
+
+// parseSeverity returns the severity with given name.
+func parseSeverity(name string) (Severity, bool) {
+	for sev := SeverityDebug; sev <= SeverityFatal; sev++ {
+		if sev.String() == name {
+			return sev, true
+		}
+	}
+
+	return 0, false
+}
+
+// severityKey is the annotation key under which an error's severity is stored.
+type severityKey struct{}
+
+// WithSeverity returns an error annotating err with given severity.
+// If err is nil, WithSeverity returns nil.
+func WithSeverity(err error, sev Severity) error {
+	if err == nil {
+		return nil
+	}
+
+	return withValue(err, severityKey{}, sev)
+}
+
+// SeverityOf returns the severity of an error, the outermost one
+// found in its chain. If no severity was set, [SeverityError] is returned.
+func SeverityOf(err error) Severity {
+	if sev, found := lookupValue(err, severityKey{}); found {
+		return sev.(Severity)
+	}
+
+	return SeverityError
+}
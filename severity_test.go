@@ -0,0 +1,98 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/actforgood/xerr"
+)
+
+func TestSeverityOf(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		subject = xerr.SeverityOf
+		stdErr  = errors.New("some standard error")
+		tests   = [...]struct {
+			name     string
+			inputErr error
+			expected xerr.Severity
+		}{
+			{
+				name:     "nil error, expect default",
+				inputErr: nil,
+				expected: xerr.SeverityError,
+			},
+			{
+				name:     "error without severity, expect default",
+				inputErr: stdErr,
+				expected: xerr.SeverityError,
+			},
+			{
+				name:     "error with severity",
+				inputErr: xerr.WithSeverity(stdErr, xerr.SeverityWarn),
+				expected: xerr.SeverityWarn,
+			},
+			{
+				name:     "wrapped error with severity",
+				inputErr: fmt.Errorf("wrap: %w", xerr.Wrap(xerr.WithSeverity(stdErr, xerr.SeverityFatal), "wrap")),
+				expected: xerr.SeverityFatal,
+			},
+			{
+				name:     "error with multiple severities, expect outermost",
+				inputErr: xerr.WithSeverity(xerr.WithSeverity(stdErr, xerr.SeverityFatal), xerr.SeverityInfo),
+				expected: xerr.SeverityInfo,
+			},
+		}
+	)
+
+	for _, testData := range tests {
+		test := testData // capture range variable
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			// act
+			result := subject(test.inputErr)
+
+			// assert
+			assertEqual(t, test.expected, result)
+		})
+	}
+}
+
+func TestWithSeverity(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		subject = xerr.WithSeverity
+		origErr = xerr.New("some error with stack")
+	)
+
+	// act
+	resultErr := subject(origErr, xerr.SeverityWarn)
+
+	// assert
+	assertEqual(t, origErr.Error(), resultErr.Error())
+	assertEqual(t, fmt.Sprintf("%+v", origErr), fmt.Sprintf("%+v", resultErr))
+	assertTrue(t, errors.Is(resultErr, origErr))
+	assertNil(t, subject(nil, xerr.SeverityWarn))
+}
+
+func TestSeverity_String(t *testing.T) {
+	t.Parallel()
+
+	assertEqual(t, "debug", xerr.SeverityDebug.String())
+	assertEqual(t, "info", xerr.SeverityInfo.String())
+	assertEqual(t, "warn", xerr.SeverityWarn.String())
+	assertEqual(t, "error", xerr.SeverityError.String())
+	assertEqual(t, "fatal", xerr.SeverityFatal.String())
+	assertEqual(t, "severity(10)", xerr.Severity(10).String())
+}
@@ -0,0 +1,87 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr
+
+import "sync/atomic"
+
+// minWrapHeadroom is the minimum number of wrapping frames
+// a [stackBuffer] has room for, in front of the wrapped stack trace.
+const minWrapHeadroom = 4
+
+// stackBuffer is a program counters buffer, shared by the stack traces of
+// an error and of the errors (repeatedly) wrapping it, each of them being a suffix of it.
+// The buffer has room in front of the wrapped stack trace, so that
+// the frame of a wrapping call gets prepended with no allocation / copy,
+// and wrapping an error N times is O(N).
+type stackBuffer struct {
+	// pcs holds the program counters, the stack traces are suffixes of it.
+	pcs []uintptr
+	// headroom is the number of wrapping frames the buffer was allocated room for.
+	headroom int
+	// top is the index of the outermost frame written in pcs.
+	top atomic.Int64
+}
+
+// newStackBuffer returns a buffer holding the given wrapping call's frame,
+// followed by the wrapped stack trace, having room in front of them
+// for the given number of wrapping frames.
+func newStackBuffer(pc uintptr, stackPCs []uintptr, headroom int) *stackBuffer {
+	buf := &stackBuffer{
+		pcs:      make([]uintptr, headroom+1+len(stackPCs)),
+		headroom: headroom,
+	}
+	buf.pcs[headroom] = pc
+	copy(buf.pcs[headroom+1:], stackPCs)
+	buf.top.Store(int64(headroom))
+
+	return buf
+}
+
+// prepend returns the given stack trace, which must be a suffix of this buffer,
+// preceded by the given wrapping call's frame, using the buffer's room.
+// It fails if there is no room left, or if the stack trace was already
+// prepended a frame (by another wrapping error of the same error).
+func (buf *stackBuffer) prepend(pc uintptr, stackPCs []uintptr) ([]uintptr, bool) {
+	start := len(buf.pcs) - len(stackPCs)
+	if start == 0 || !buf.top.CompareAndSwap(int64(start), int64(start-1)) {
+		return nil, false
+	}
+	buf.pcs[start-1] = pc
+
+	return buf.pcs[start-1:], true
+}
+
+// wrapStack returns the stack trace, and the buffer holding it, if any, of an error
+// wrapping an error having the given stack trace (and buffer, if any),
+// which consists of the given wrapping call's frame followed by the wrapped stack trace.
+// The first wrapping of an error which does not wrap another error itself
+// (most errors get wrapped at most once) gets an exact sized stack trace, with no buffer.
+// Otherwise (the error is being wrapped repeatedly), a new buffer is allocated if the
+// wrapped stack trace has none, if its one has no room left (with twice its room,
+// so that wrapping an error N times does O(log N) allocations of buffers),
+// or if it was already prepended a frame.
+func wrapStack(pc uintptr, stackPCs []uintptr, buf *stackBuffer, wrapping bool) ([]uintptr, *stackBuffer) {
+	if buf == nil && !wrapping {
+		wrapPCs := make([]uintptr, 1+len(stackPCs))
+		wrapPCs[0] = pc
+		copy(wrapPCs[1:], stackPCs)
+
+		return wrapPCs, nil
+	}
+
+	headroom := minWrapHeadroom
+	if buf != nil {
+		if wrapPCs, ok := buf.prepend(pc, stackPCs); ok {
+			return wrapPCs, buf
+		}
+		if len(buf.pcs) == len(stackPCs) { // no room left.
+			headroom = max(headroom, 2*buf.headroom)
+		}
+	}
+	buf = newStackBuffer(pc, stackPCs, headroom)
+
+	return buf.pcs[headroom:], buf
+}
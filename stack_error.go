@@ -8,21 +8,46 @@ package xerr
 import (
 	"fmt"
 	"io"
+	"reflect"
 	"runtime"
 	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // maxStackFrames is the maximum depth of callstack.
 const maxStackFrames = 32
 
+// sampledOutCallStack is the (empty, but not nil) callstack of an error
+// whose stack trace was sampled out, see [SetStackSampling].
+var sampledOutCallStack = make([]uintptr, 0)
+
 // stackError is an error enriched with callstack.
 type stackError struct {
 	// origErr is the original error, if this error wraps another one.
 	origErr error
 	// stackPCs holds the callstack program counters.
+	// It is empty, but not nil, if the stack trace was sampled out, see [SetStackSampling].
 	stackPCs []uintptr
+	// stackBuf is the buffer stackPCs is a suffix of, shared with the errors wrapping this one, if any.
+	stackBuf *stackBuffer
 	// msg is this error's message.
 	msg string
+	// createdAt is the moment this error was created.
+	createdAt time.Time
+	// skipFrame overrides the globally configured [SkipFrame], if not nil.
+	skipFrame SkipFrame
+	// fnNameProcessor overrides the globally configured [FrameFnNameProcessor], if not nil.
+	fnNameProcessor FrameFnNameProcessor
+	// fileProcessor overrides the globally configured [FrameFileProcessor], if not nil.
+	fileProcessor FrameFileProcessor
+	// errMsg memoizes the error's message, see [stackError.Error].
+	errMsg atomic.Pointer[string]
+	// id is the error's unique ID, see [SetErrorIDGenerator].
+	id string
+	// build is the build info embedded into this error, see [WithBuildInfo].
+	build *BuildInfo
 }
 
 // Error returns the error's message.
@@ -30,15 +55,26 @@ type stackError struct {
 //
 // The returned value has the form <stackError.msg>: <stackError.origErr.Error()>,
 // any of the 2 parts may be missing.
-func (err stackError) Error() string {
-	message := err.msg
-	if err.origErr != nil {
-		if message == "" {
-			message = err.origErr.Error()
-		} else {
-			message += ": " + err.origErr.Error()
-		}
+// The message is memoized, if the wrapped chain's messages never change, that is,
+// it is made only of this package's (immutable) errors and standard library's ones,
+// not of a [MultiError], to which errors can be added anytime, or of foreign errors.
+// An error which does not wrap another one returns its own message, with no allocation.
+func (err *stackError) Error() string {
+	if err.origErr == nil {
+		return err.msg
+	}
+	if message := err.errMsg.Load(); message != nil {
+		return *message
+	}
+
+	message := err.origErr.Error()
+	if err.msg != "" {
+		message = err.msg + ": " + message
+	}
+	if !hasImmutableMessage(err.origErr) {
+		return message
 	}
+	err.errMsg.Store(&message)
 
 	return message
 }
@@ -50,17 +86,25 @@ func (err stackError) Error() string {
 //	      printed recursively.
 //	%v    same behaviour as %s.
 //	%+v   extended format. Each frame of the error's call stack will
-//	      be printed in detail.
-func (err stackError) Format(f fmt.State, verb rune) {
+//	      be printed in detail. The error's ID, if any, is written
+//	      after the message, like "(error id: ab12cd)", followed by
+//	      the build info, if any (see [WithBuildInfo]).
+func (err *stackError) Format(f fmt.State, verb rune) {
 	switch verb {
 	case 'v':
 		if f.Flag('+') {
+			if stackFormat == StackFormatLayered {
+				err.writeLayers(f)
+
+				return
+			}
 			err.writeMsg(f)
-			for _, pc := range err.stackPCs {
-				fnName, file, line := getFrame(pc - 1)
-				if !skipFrame(fnName, file) {
-					writeFrame(f, fnName, file, line)
-				}
+			err.writeID(f)
+			writeBuildInfo(f, err.build)
+			err.writeStack(f, err.stackPCs, stackFormat)
+			err.writeSampledOut(f)
+			if rErr := remoteErrorOf(err.origErr); rErr != nil {
+				rErr.writeStack(f)
 			}
 
 			return
@@ -72,9 +116,181 @@ func (err stackError) Format(f fmt.State, verb rune) {
 	}
 }
 
+// writeSampledOut writes a note about the error's stack trace being sampled out,
+// if that is the case, see [SetStackSampling].
+func (err *stackError) writeSampledOut(w io.Writer) {
+	if err.stackPCs != nil && len(err.stackPCs) == 0 {
+		_, _ = io.WriteString(w, "\nstack omitted (sampled)")
+	}
+}
+
+// writeStack writes the given stack trace frames (the error's ones, or a part of them),
+// in the given [StackFormat], honoring this error's configuration.
+func (err *stackError) writeStack(w io.Writer, stackPCs []uintptr, format StackFormat) {
+	customWriteFrame, maxFrames := frameWriter, maxPrintFrames
+	var annotations map[int]string
+	if format == StackFormatAnnotated && customWriteFrame == nil {
+		annotations = err.frameAnnotations()
+	}
+	skip := err.frameSkipper()
+	processFnName, processFile := err.frameFnNameProcessor(), err.frameFileProcessor()
+	written, notWritten := 0, 0
+	for idx, pc := range stackPCs {
+		fnName, file, line := getFrame(pc - 1)
+		if skip(fnName, file) {
+			continue
+		}
+		if maxFrames > 0 && written == maxFrames {
+			notWritten++
+
+			continue
+		}
+		if processFnName != nil {
+			fnName = processFnName(fnName)
+		}
+		if processFile != nil {
+			file = processFile(file)
+		}
+		switch {
+		case customWriteFrame != nil:
+			customWriteFrame(w, Frame{Function: fnName, File: file, Line: line, PC: pc})
+		case format == StackFormatInline:
+			writeInlineFrame(w, fnName, file, line, written == 0)
+		default:
+			writeFrame(w, fnName, file, line)
+		}
+		if msg, found := annotations[idx]; found {
+			_, _ = io.WriteString(w, "  — ")
+			_, _ = io.WriteString(w, strconv.Quote(msg))
+		}
+		written++
+	}
+	inline := format == StackFormatInline && customWriteFrame == nil
+	if notWritten > 0 {
+		if inline {
+			_, _ = io.WriteString(w, " <- … ")
+		} else {
+			_, _ = io.WriteString(w, "\n… ")
+		}
+		_, _ = io.WriteString(w, strconv.FormatInt(int64(notWritten), 10))
+		_, _ = io.WriteString(w, " more")
+	}
+	if inline && written > 0 {
+		_, _ = io.WriteString(w, "]")
+	}
+}
+
+// writeLayers writes the error's message followed by the frames captured
+// by this error only, then, for each stack error it wraps, the wrapped error's
+// message followed by the frames captured by it, like:
+//
+//	reading config
+//	github.com/actforgood/xerr_test.ReadConfig
+//		/Users/bogdan/work/go/xerr/config.go:42
+//	caused by: opening file
+//	github.com/actforgood/xerr_test.OpenFile
+//		/Users/bogdan/work/go/xerr/file.go:13
+//	main.main
+//		/Users/bogdan/work/go/xerr/main.go:7
+//	caused by: file not found
+//
+// Errors which do not have a stack trace, wrapped by this package's errors
+// (like a standard library error), have only their message written.
+func (err *stackError) writeLayers(w io.Writer) {
+	if err.msg == "" {
+		err.writeMsg(w)
+	} else {
+		_, _ = io.WriteString(w, err.msg)
+	}
+	err.writeID(w)
+	writeBuildInfo(w, err.build)
+	for layer := err; layer != nil; {
+		next, leaf := nextLayer(layer.origErr)
+		stackPCs := layer.stackPCs
+		if next != nil && isSuffix(next.stackPCs, stackPCs) {
+			stackPCs = stackPCs[:len(stackPCs)-len(next.stackPCs)]
+		}
+		layer.writeStack(w, stackPCs, StackFormatDefault)
+		layer.writeSampledOut(w)
+
+		switch {
+		case next != nil && next.msg != "":
+			_, _ = io.WriteString(w, "\ncaused by: ")
+			_, _ = io.WriteString(w, next.msg)
+		case leaf != nil:
+			_, _ = io.WriteString(w, "\ncaused by: ")
+			_, _ = io.WriteString(w, leaf.Error())
+		}
+		layer = next
+	}
+	if rErr := remoteErrorOf(err.origErr); rErr != nil {
+		rErr.writeStack(w)
+	}
+}
+
+// nextLayer returns the first stack error found in given error's chain, if any,
+// otherwise the innermost error of the chain (leaf).
+// A [MultiError] ends the search, being returned as leaf.
+func nextLayer(err error) (next *stackError, leaf error) {
+	for e, layer := err, 0; e != nil && layer < maxChainDepth; layer++ {
+		switch x := e.(type) {
+		case *stackError:
+			return x, nil
+		case *MultiError:
+			return nil, x
+		}
+		unwrapper, ok := e.(interface{ Unwrap() error })
+		if !ok {
+			return nil, e
+		}
+		if inner := unwrapper.Unwrap(); inner != nil {
+			e = inner
+		} else {
+			return nil, e
+		}
+	}
+
+	return nil, nil
+}
+
+// frameSkipper returns the [SkipFrame] to be applied on this error's stack trace.
+func (err *stackError) frameSkipper() SkipFrame {
+	if err.skipFrame != nil {
+		return err.skipFrame
+	}
+
+	return skipFrame
+}
+
+// frameFnNameProcessor returns the [FrameFnNameProcessor] to be applied
+// on this error's stack trace (can be nil).
+func (err *stackError) frameFnNameProcessor() FrameFnNameProcessor {
+	if err.fnNameProcessor != nil {
+		return err.fnNameProcessor
+	}
+
+	return frameFnNameProcessor
+}
+
+// frameFileProcessor returns the [FrameFileProcessor] to be applied
+// on this error's stack trace (can be nil).
+func (err *stackError) frameFileProcessor() FrameFileProcessor {
+	if err.fileProcessor != nil {
+		return err.fileProcessor
+	}
+
+	return frameFileProcessor
+}
+
+// frames returns this error's stack trace frames,
+// honoring the configured [SkipFrame], [FrameFnNameProcessor] and [FrameFileProcessor].
+func (err *stackError) frames() []Frame {
+	return resolveFrames(err.stackPCs, err.frameSkipper(), err.frameFnNameProcessor(), err.frameFileProcessor())
+}
+
 // writeMsg writes the error message.
 // Used this instead of directly io.WriteString(w, err.Error()) to save some extra memory allocation.
-func (err stackError) writeMsg(w io.Writer) {
+func (err *stackError) writeMsg(w io.Writer) {
 	_, _ = io.WriteString(w, err.msg)
 	if err.origErr != nil {
 		if err.msg != "" {
@@ -84,87 +300,455 @@ func (err stackError) writeMsg(w io.Writer) {
 	}
 }
 
+// writeID writes the error's ID, if any.
+func (err *stackError) writeID(w io.Writer) {
+	if err.id != "" {
+		_, _ = io.WriteString(w, " (error id: ")
+		_, _ = io.WriteString(w, err.id)
+		_, _ = io.WriteString(w, ")")
+	}
+}
+
+// frameAnnotations returns the messages of this error and of the
+// stack errors it wraps, indexed by the position of the frame captured
+// when each of them was created, in this error's stack trace.
+func (err *stackError) frameAnnotations() map[int]string {
+	annotations := make(map[int]string)
+	if err.msg != "" {
+		annotations[0] = err.msg
+	}
+
+	for e, layer := err.origErr, 0; e != nil && layer < maxChainDepth; layer++ {
+		if sErr, ok := e.(*stackError); ok && sErr.msg != "" && isSuffix(sErr.stackPCs, err.stackPCs) {
+			idx := len(err.stackPCs) - len(sErr.stackPCs)
+			if _, found := annotations[idx]; !found {
+				annotations[idx] = sErr.msg
+			}
+		}
+		if _, ok := e.(*MultiError); ok {
+			break
+		}
+		unwrapper, ok := e.(interface{ Unwrap() error })
+		if !ok {
+			break
+		}
+		e = unwrapper.Unwrap()
+	}
+
+	return annotations
+}
+
+// isSuffix checks whether the given program counters are the ending of stackPCs.
+func isSuffix(suffix, stackPCs []uintptr) bool {
+	if len(suffix) == 0 || len(suffix) > len(stackPCs) {
+		return false
+	}
+	offset := len(stackPCs) - len(suffix)
+	for idx, pc := range suffix {
+		if stackPCs[offset+idx] != pc {
+			return false
+		}
+	}
+
+	return true
+}
+
 // Unwrap returns original error (can be nil).
 // It implements [errors.Is] / [errors.As] APIs.
-func (err stackError) Unwrap() error {
+func (err *stackError) Unwrap() error {
 	return err.origErr
 }
 
+// created notifies the configured [SetOnError] hook, if any,
+// about the creation of given error, which is returned.
+// The error gets stamped with an ID, if configured (see [SetErrorIDGenerator]),
+// recorded and logged, if configured (see [SetFlightRecorder], [SetAutoLog]),
+// if it is not a wrap of an already created error.
+func created(err error) error {
+	stampID(err)
+	if onError != nil {
+		onError(err)
+	}
+	if (flightRecorder != nil || autoLog != nil) && isRootCreation(err) {
+		recordCreated(err)
+		logCreated(err)
+	}
+
+	return err
+}
+
+// isRootCreation tells whether given, just created, error is the first error
+// with stack trace of its chain, as opposed to a wrap of such an error.
+func isRootCreation(err error) bool {
+	var stackErrs int
+	walkStackChain(err, func(e error) bool {
+		if _, ok := e.(*stackError); ok {
+			stackErrs++
+		}
+
+		return stackErrs < 2
+	})
+
+	return stackErrs < 2
+}
+
 // New returns an error with the supplied message.
 // New also records the stack trace at the point it was called.
 func New(msg string) error {
-	return &stackError{
-		msg:      msg,
-		stackPCs: getCallStack(maxStackFrames),
-	}
+	return created(&stackError{
+		msg:       msg,
+		stackPCs:  getCallStack(maxStackFrames),
+		createdAt: time.Now(),
+	})
 }
 
 // Errorf formats according to a format specifier and returns the string
 // as a value that satisfies error.
 // Errorf also records the stack trace at the point it was called.
 func Errorf(format string, args ...interface{}) error {
-	return &stackError{
-		msg:      fmt.Sprintf(format, args...),
-		stackPCs: getCallStack(maxStackFrames),
-	}
+	return created(&stackError{
+		msg:       fmt.Sprintf(format, args...),
+		stackPCs:  getCallStack(maxStackFrames),
+		createdAt: time.Now(),
+	})
 }
 
 // Wrap returns an error annotating err with a stack trace
 // at the point Wrap is called, and the supplied message.
 // If err is nil, Wrap returns nil.
-// If err is another stack trace aware error, the final stack trace will
-// consists of original error's stack trace + 1 trace of current Wrap call.
+// If err is (or wraps) another stack trace aware error (see [StackTracer]),
+// the final stack trace will consists of original error's stack trace
+// + 1 trace of current Wrap call.
 func Wrap(err error, msg string) error {
 	if err == nil {
 		return nil
 	}
 
-	var stackPCs []uintptr
-	if sErr, ok := err.(*stackError); ok {
-		stackPCs = append(getCallStack(1), sErr.stackPCs...)
-	} else {
-		stackPCs = getCallStack(maxStackFrames)
+	wErr := &stackError{
+		origErr:   err,
+		msg:       msg,
+		createdAt: time.Now(),
 	}
+	wErr.stackPCs, wErr.stackBuf = wrapCallStack(err, 0, maxStackFrames)
 
-	return &stackError{
-		origErr:  err,
-		msg:      msg,
-		stackPCs: stackPCs,
-	}
+	return created(wErr)
 }
 
 // Wrapf returns an error annotating err with a stack trace
 // at the point Wrapf is called, and the message formatted according to a
 // format specifier.
 // If err is nil, Wrapf returns nil.
-// If err is another stack trace aware error, the final stack trace will
-// consists of original error's stack trace + 1 trace of current Wrapf call.
+// If err is (or wraps) another stack trace aware error (see [StackTracer]),
+// the final stack trace will consists of original error's stack trace
+// + 1 trace of current Wrapf call.
 func Wrapf(err error, format string, args ...interface{}) error {
 	if err == nil {
 		return nil
 	}
 
-	var stackPCs []uintptr
-	if sErr, ok := err.(*stackError); ok {
-		stackPCs = append(getCallStack(1), sErr.stackPCs...)
-	} else {
-		stackPCs = getCallStack(maxStackFrames)
+	wErr := &stackError{
+		origErr:   err,
+		msg:       fmt.Sprintf(format, args...),
+		createdAt: time.Now(),
+	}
+	wErr.stackPCs, wErr.stackBuf = wrapCallStack(err, 0, maxStackFrames)
+
+	return created(wErr)
+}
+
+// NewSkip returns an error with the supplied message, like [New] does,
+// skipping additionally the given number of callers when recording the stack trace.
+// It is useful for helper functions wrapping this package, which should not
+// appear as the origin of the error (skip 1 elides the helper itself).
+// Negative skip is treated as 0.
+// See also [WithCallerSkip].
+func NewSkip(skip int, msg string) error {
+	return created(&stackError{
+		msg:       msg,
+		stackPCs:  getCallStackSkip(max(skip, 0), maxStackFrames),
+		createdAt: time.Now(),
+	})
+}
+
+// WrapSkip returns an error annotating err with the supplied message, like [Wrap] does,
+// skipping additionally the given number of callers when recording the stack trace.
+// If err is nil, WrapSkip returns nil.
+// Negative skip is treated as 0.
+// See also [WithCallerSkip].
+func WrapSkip(skip int, err error, msg string) error {
+	if err == nil {
+		return nil
 	}
 
-	return &stackError{
-		origErr:  err,
-		msg:      fmt.Sprintf(format, args...),
-		stackPCs: stackPCs,
+	wErr := &stackError{
+		origErr:   err,
+		msg:       msg,
+		createdAt: time.Now(),
+	}
+	wErr.stackPCs, wErr.stackBuf = wrapCallStack(err, max(skip, 0), maxStackFrames)
+
+	return created(wErr)
+}
+
+// Frames returns the stack trace frames of an error, the ones of the
+// outermost error with stack trace found in its chain.
+// If there is no such error created by this package, the ones of the
+// outermost foreign [StackTracer] are returned.
+// The errors stored in a [MultiError] are not looked into, each has its own stack trace.
+// Configured [SkipFrame], [FrameFnNameProcessor] and [FrameFileProcessor] are honored,
+// the same way they are for the extended (%+v) output.
+// Returns nil if there is no stack trace.
+func Frames(err error) []Frame {
+	if sErr := stackErrorOf(err); sErr != nil {
+		return sErr.frames()
+	}
+	if st := stackTracerOf(err); st != nil {
+		return processFrames(st.StackFrames())
 	}
+
+	return nil
+}
+
+// stackTracerOf returns the outermost foreign [StackTracer] having a stack trace
+// found in err's chain, if any.
+// The errors stored in a [MultiError] are not looked into, see [walkStackChain].
+func stackTracerOf(err error) StackTracer {
+	var result StackTracer
+	walkStackChain(err, func(e error) bool {
+		if _, ok := e.(*stackError); ok {
+			return true
+		}
+		if st, ok := e.(StackTracer); ok && len(st.StackFrames()) > 0 {
+			result = st
+
+			return false
+		}
+
+		return true
+	})
+
+	return result
+}
+
+// processFrames returns given frames, honoring the globally configured
+// [SkipFrame], [FrameFnNameProcessor] and [FrameFileProcessor].
+func processFrames(frames []Frame) []Frame {
+	result := make([]Frame, 0, len(frames))
+	for _, f := range frames {
+		if skipFrame(f.Function, f.File) {
+			continue
+		}
+		if frameFnNameProcessor != nil {
+			f.Function = frameFnNameProcessor(f.Function)
+		}
+		if frameFileProcessor != nil {
+			f.File = frameFileProcessor(f.File)
+		}
+		result = append(result, f)
+	}
+
+	return result
 }
 
 // getCallStack return a slice of program counters of function invocations
 // on the calling goroutine's stack.
 func getCallStack(maxDepth int) []uintptr {
-	pcs := make([]uintptr, maxDepth)
-	n := runtime.Callers(3, pcs)
+	return getCallStackSkip(1, maxDepth)
+}
+
+// wrapCallStack returns the stack trace of an error wrapping err, and the buffer holding it, if any.
+// If err is (or wraps) a stack trace aware error, the stack trace consists of its stack trace
+// + 1 trace of the wrapping function call, otherwise the call stack is captured.
+// The given number of callers of the wrapping function is skipped additionally.
+// The configured [StackMode] is honored.
+func wrapCallStack(err error, skip, maxDepth int) ([]uintptr, *stackBuffer) {
+	return wrapCallStackDepth(err, skip+1, stackMode.depth(maxDepth))
+}
+
+// wrapCallStackDepth returns the stack trace of an error wrapping err, like [wrapCallStack] does,
+// capturing at most depth frames, with no [StackMode] applied.
+// If depth is 0, err's stack trace is kept, if any.
+func wrapCallStackDepth(err error, skip, depth int) ([]uintptr, *stackBuffer) {
+	stackPCs, buf, wrapping := existingStack(err)
+	switch {
+	case depth <= 0:
+		return stackPCs, buf
+	case len(stackPCs) > 0:
+		var pc [1]uintptr
+		if captureCallers(skip+1, pc[:]) == 0 {
+			return stackPCs, buf
+		}
+
+		return wrapStack(pc[0], stackPCs, buf, wrapping)
+	default:
+		return callStack(skip+1, depth), nil
+	}
+}
+
+// existingCallStack returns the stack trace of the outermost stack trace aware error
+// found in err's chain, if any.
+// A [MultiError] ends the search, as its errors have their own, unrelated, stack traces.
+func existingCallStack(err error) []uintptr {
+	stackPCs, _, _ := existingStack(err)
+
+	return stackPCs
+}
+
+// existingStack returns the stack trace of the outermost stack trace aware error
+// found in err's chain, if any, like [existingCallStack] does,
+// the buffer holding it, if it is an error created by this package,
+// and whether that error wraps another error itself (case in which it is likely
+// being wrapped repeatedly, see [wrapStack]).
+func existingStack(err error) ([]uintptr, *stackBuffer, bool) {
+	for e, layer := err, 0; e != nil && layer < maxChainDepth; layer++ {
+		switch x := e.(type) {
+		case *stackError:
+			if len(x.stackPCs) > 0 {
+				return x.stackPCs, x.stackBuf, x.origErr != nil
+			}
+		case StackTracer:
+			if stackPCs := framesPCs(x.StackFrames()); len(stackPCs) > 0 {
+				return stackPCs, nil, false
+			}
+		default:
+			if stackPCs := pkgErrorsCallStack(e); len(stackPCs) > 0 {
+				return stackPCs, nil, false
+			}
+		}
+		unwrapper, ok := e.(interface{ Unwrap() error })
+		if !ok {
+			break
+		}
+		e = unwrapper.Unwrap()
+	}
+
+	return nil, nil, false
+}
+
+// walkStackChain calls fn for err and the errors it wraps, from the outermost one inwards,
+// until fn returns false. Like [existingStack] does, only single unwrap errors are followed,
+// the errors stored in a [MultiError] (or joined with [errors.Join]) are not looked into,
+// as their stack traces are not the one of the error wrapping them.
+func walkStackChain(err error, fn func(e error) bool) {
+	for e, layer := err, 0; e != nil && layer < maxChainDepth; layer++ {
+		if !fn(e) {
+			return
+		}
+		unwrapper, ok := e.(interface{ Unwrap() error })
+		if !ok {
+			return
+		}
+		e = unwrapper.Unwrap()
+	}
+}
+
+// pkgErrorsCallStack returns the stack trace of an error implementing
+// StackTrace() errors.StackTrace, like the ones from github.com/pkg/errors,
+// without depending on that module.
+// The stack trace is expected to be a slice of program counters.
+func pkgErrorsCallStack(err error) []uintptr {
+	method := reflect.ValueOf(err).MethodByName("StackTrace")
+	if !method.IsValid() || method.Type().NumIn() != 0 || method.Type().NumOut() != 1 {
+		return nil
+	}
+	stackType := method.Type().Out(0)
+	if stackType.Kind() != reflect.Slice || stackType.Elem().Kind() != reflect.Uintptr {
+		return nil
+	}
+	stack := method.Call(nil)[0]
+	stackPCs := make([]uintptr, stack.Len())
+	for idx := range stackPCs {
+		stackPCs[idx] = uintptr(stack.Index(idx).Uint())
+	}
+
+	return stackPCs
+}
+
+// framesPCs returns the program counters of given frames.
+// Returns nil if any of the frames misses its program counter.
+func framesPCs(frames []Frame) []uintptr {
+	stackPCs := make([]uintptr, len(frames))
+	for idx, f := range frames {
+		if f.PC == 0 {
+			return nil
+		}
+		stackPCs[idx] = f.PC
+	}
+
+	return stackPCs
+}
+
+// getCallStackSkip return a slice of program counters of function invocations
+// on the calling goroutine's stack, skipping additionally the given number of callers.
+// Helper functions frames (see [MarkHelper]) at the top of the stack are skipped, too.
+// The configured [StackMode] is honored, and the stack trace may be sampled out, see [SetStackSampling].
+func getCallStackSkip(skip, maxDepth int) []uintptr {
+	return callStack(skip+1, stackMode.depth(maxDepth))
+}
+
+// callStack returns a slice of program counters of function invocations, at most depth ones,
+// like [getCallStackSkip] does, with no [StackMode] applied.
+// Returns nil if depth is 0.
+func callStack(skip, depth int) []uintptr {
+	if depth <= 0 {
+		return nil
+	}
+	if depth > 1 && isStackSampledOut() {
+		return sampledOutCallStack
+	}
 
-	return pcs[:n]
+	buf := getPCsBuffer(depth)
+	defer putPCsBuffer(buf)
+
+	pcs := (*buf)[:depth]
+	n := captureCallers(skip+1, pcs)
+	if n == 0 {
+		return nil
+	}
+
+	// the error keeps only the captured program counters, not the whole buffer.
+	stackPCs := make([]uintptr, n)
+	copy(stackPCs, pcs[:n])
+
+	return stackPCs
+}
+
+// captureCallers fills the given slice with the program counters of function invocations
+// on the calling goroutine's stack, skipping additionally the given number of callers,
+// and the helper functions frames (see [MarkHelper]) at the top of the stack.
+// Returns the number of program counters written.
+func captureCallers(skip int, pcs []uintptr) int {
+	n := runtime.Callers(3+skip, pcs)
+	for helpersCnt := leadingHelperFrames(pcs[:n]); helpersCnt > 0; helpersCnt = leadingHelperFrames(pcs[:n]) {
+		skip += helpersCnt
+		n = runtime.Callers(3+skip, pcs)
+	}
+
+	return n
+}
+
+// pcsBufferPool is a pool of program counters buffers, used to capture stack traces.
+var pcsBufferPool = sync.Pool{
+	New: func() any {
+		buf := make([]uintptr, maxStackFrames)
+
+		return &buf
+	},
+}
+
+// getPCsBuffer returns a program counters buffer, able to hold at least depth program counters.
+func getPCsBuffer(depth int) *[]uintptr {
+	buf := pcsBufferPool.Get().(*[]uintptr)
+	if cap(*buf) < depth {
+		*buf = make([]uintptr, depth)
+	}
+
+	return buf
+}
+
+// putPCsBuffer puts back into the pool the given program counters buffer.
+func putPCsBuffer(buf *[]uintptr) {
+	pcsBufferPool.Put(buf)
 }
 
 // writeFrame writes the given frame to the specified writer.
@@ -180,24 +764,145 @@ func getCallStack(maxDepth int) []uintptr {
 //	  /Users/bogdan/work/go/xerr/errors_test.go:68
 func writeFrame(w io.Writer, fnName string, file string, line int) {
 	_, _ = io.WriteString(w, "\n")
-	if frameFnNameProcessor != nil {
-		_, _ = io.WriteString(w, frameFnNameProcessor(fnName))
+	_, _ = io.WriteString(w, fnName)
+	_, _ = io.WriteString(w, "\n\t")
+	_, _ = io.WriteString(w, file)
+	_, _ = io.WriteString(w, ":")
+	_, _ = io.WriteString(w, strconv.FormatInt(int64(line), 10))
+}
+
+// writeInlineFrame writes the given frame to the specified writer, on a single line.
+//
+// The format in which is written is " [<functionName>(<file>:<line>)" for the first frame,
+// and " <- <functionName>(<file>:<line>)" for the next frames.
+//
+// Example:
+//
+//	[github.com/actforgood/xerr_test.TestX(/Users/bogdan/work/go/xerr/errors_test.go:68)
+func writeInlineFrame(w io.Writer, fnName string, file string, line int, first bool) {
+	if first {
+		_, _ = io.WriteString(w, " [")
 	} else {
-		_, _ = io.WriteString(w, fnName)
+		_, _ = io.WriteString(w, " <- ")
 	}
-	_, _ = io.WriteString(w, "\n\t")
+	_, _ = io.WriteString(w, fnName)
+	_, _ = io.WriteString(w, "(")
 	_, _ = io.WriteString(w, file)
 	_, _ = io.WriteString(w, ":")
 	_, _ = io.WriteString(w, strconv.FormatInt(int64(line), 10))
+	_, _ = io.WriteString(w, ")")
+}
+
+// StackTracer is an error which exposes its stack trace.
+// Errors created by this package implement it, and errors from other packages
+// can implement it too, in order to have their stack trace reused, instead of
+// recaptured, when they get wrapped by [Wrap] / [Wrapf], and recognized
+// by [Frames] / [Fingerprint].
+// Note: frames must have their program counter set in order to be reused.
+type StackTracer interface {
+	// StackFrames returns the stack trace frames, innermost call first.
+	StackFrames() []Frame
+}
+
+var _ StackTracer = (*stackError)(nil)
+
+// StackFrames returns this error's stack trace frames,
+// as captured, with no configuration applied.
+// Implements [StackTracer].
+func (err *stackError) StackFrames() []Frame {
+	return rawFrames(err.stackPCs)
+}
+
+// StackTrace returns this error's stack trace program counters, innermost call first.
+// It has the same shape as github.com/pkg/errors' StackTrace() method (a slice
+// of program counters), so that tools extracting stack traces from pkg/errors
+// errors by reflection, recognize this error's stack trace, too.
+func (err *stackError) StackTrace() []uintptr {
+	stackPCs := make([]uintptr, len(err.stackPCs))
+	copy(stackPCs, err.stackPCs)
+
+	return stackPCs
+}
+
+// Frame is a stack trace frame.
+type Frame struct {
+	// Function is the fully qualified function name.
+	Function string
+	// File is the file's full path.
+	File string
+	// Line is the line number in the file.
+	Line int
+	// PC is the program counter, as captured from the call stack.
+	PC uintptr
+}
+
+// resolveFrames returns the frames of given program counters,
+// honoring the given [SkipFrame], [FrameFnNameProcessor] and [FrameFileProcessor]
+// (the last two can be nil).
+func resolveFrames(
+	stackPCs []uintptr,
+	skip SkipFrame,
+	processFnName FrameFnNameProcessor,
+	processFile FrameFileProcessor,
+) []Frame {
+	frames := make([]Frame, 0, len(stackPCs))
+	for _, pc := range stackPCs {
+		fnName, file, line := getFrame(pc - 1)
+		if skip(fnName, file) {
+			continue
+		}
+		if processFnName != nil {
+			fnName = processFnName(fnName)
+		}
+		if processFile != nil {
+			file = processFile(file)
+		}
+		frames = append(frames, Frame{Function: fnName, File: file, Line: line, PC: pc})
+	}
+
+	return frames
+}
+
+// rawFrames returns the frames of given program counters,
+// as they are, with no configuration applied.
+func rawFrames(stackPCs []uintptr) []Frame {
+	frames := make([]Frame, len(stackPCs))
+	for idx, pc := range stackPCs {
+		fnName, file, line := getFrame(pc - 1)
+		frames[idx] = Frame{Function: fnName, File: file, Line: line, PC: pc}
+	}
+
+	return frames
+}
+
+// cachedFrame holds the resolved symbol information of a program counter.
+type cachedFrame struct {
+	fnName string
+	file   string
+	line   int
 }
 
+// framesCache holds resolved frames, keyed by program counter.
+// Program counters are process-wide stable and their number is bounded by
+// the code size, so entries are never evicted.
+var framesCache sync.Map
+
 // getFrame returns function, file and line for a program counter.
+// Results are cached, so that formatting errors sharing call paths
+// does not resolve same symbols repeatedly.
 func getFrame(pc uintptr) (fnName string, file string, line int) {
+	if cached, found := framesCache.Load(pc); found {
+		frame := cached.(*cachedFrame)
+
+		return frame.fnName, frame.file, frame.line
+	}
+
 	fn := runtime.FuncForPC(pc)
 	if fn != nil {
 		fnName = fn.Name()
 		file, line = fn.FileLine(pc)
 	}
+	framesCache.Store(pc, &cachedFrame{fnName: fnName, file: file, line: line})
 
 	return
 }
@@ -6,14 +6,30 @@
 package xerr
 
 import (
+	"encoding/json"
+	"go/build"
+	"io"
+	"math/rand"
 	"os"
+	"path"
+	"path/filepath"
 	"runtime"
+	"runtime/debug"
+	"strconv"
 	"strings"
+	"sync"
 )
 
 var (
 	skipFrame            SkipFrame = AllowFrame
 	frameFnNameProcessor FrameFnNameProcessor
+	frameFileProcessor   FrameFileProcessor
+	frameWriter          FrameWriter
+	maxPrintFrames       int
+	stackFormat          = StackFormatDefault
+	onError              func(err error)
+	stackSampling        = 1.0
+	stackMode            = StackModeFull
 )
 
 // SetSkipFrame configures the function this package uses
@@ -53,6 +69,133 @@ func SkipFrameGoRootSrcPath(next SkipFrame) SkipFrame {
 	}
 }
 
+// SkipFrameTesting is a chained function which blacklists
+// frames of the "testing" package functions, like "testing.tRunner".
+func SkipFrameTesting(next SkipFrame) SkipFrame {
+	return skipFrameIf(next, func(fnName, _ string) bool {
+		return strings.HasPrefix(fnName, "testing.")
+	})
+}
+
+// SkipFrameRuntime is a chained function which blacklists
+// frames of the "runtime" package functions, like "runtime.goexit", "runtime.main".
+func SkipFrameRuntime(next SkipFrame) SkipFrame {
+	return skipFrameIf(next, func(fnName, _ string) bool {
+		return strings.HasPrefix(fnName, "runtime.")
+	})
+}
+
+// SkipFrameVendor is a chained function which blacklists
+// frames with files from a "vendor" directory.
+func SkipFrameVendor(next SkipFrame) SkipFrame {
+	return skipFrameIf(next, func(_, file string) bool {
+		return strings.Contains(file, "/vendor/")
+	})
+}
+
+// SkipFrameModCache is a chained function which blacklists
+// frames with files from the module cache ("GOPATH/pkg/mod"),
+// in other words, frames of third party modules.
+func SkipFrameModCache(next SkipFrame) SkipFrame {
+	return skipFrameIf(next, func(_, file string) bool {
+		return strings.Contains(file, "/pkg/mod/")
+	})
+}
+
+// SkipFramePrefix returns a chained function which blacklists
+// frames with the function name or the file starting with any of given prefixes.
+//
+// Example:
+//
+//	xerr.SetSkipFrame(xerr.SkipFramePrefix("github.com/gin-gonic/")(xerr.AllowFrame))
+func SkipFramePrefix(prefixes ...string) SkipFrameChain {
+	return func(next SkipFrame) SkipFrame {
+		return skipFrameIf(next, func(fnName, file string) bool {
+			for _, prefix := range prefixes {
+				if strings.HasPrefix(fnName, prefix) || strings.HasPrefix(file, prefix) {
+					return true
+				}
+			}
+
+			return false
+		})
+	}
+}
+
+// SkipFrameNonModule returns a chained function which blacklists frames
+// not belonging to the main module of the application (the one
+// reported by [debug.ReadBuildInfo]), or to any of given extra module paths.
+// In other words, it keeps only "my code" frames, removing runtime,
+// standard library and third party modules frames.
+// Frames of the main package functions (like "main.main") are kept, too.
+// If the main module cannot be determined, no frame is blacklisted by it.
+//
+// Example:
+//
+//	xerr.SetSkipFrame(xerr.SkipFrameNonModule("github.com/myorg/mylib")(xerr.AllowFrame))
+func SkipFrameNonModule(extraModules ...string) SkipFrameChain {
+	var modules []string
+	if mainModule := mainModulePath(); mainModule != "" {
+		modules = append(modules, mainModule)
+	}
+	if len(modules) > 0 {
+		modules = append(modules, extraModules...)
+	}
+
+	return func(next SkipFrame) SkipFrame {
+		if len(modules) == 0 {
+			return next
+		}
+
+		return skipFrameIf(next, func(fnName, _ string) bool {
+			if strings.HasPrefix(fnName, "main.") {
+				return false
+			}
+			for _, module := range modules {
+				if isModuleFunction(fnName, module) {
+					return false
+				}
+			}
+
+			return true
+		})
+	}
+}
+
+// mainModulePath returns the main module's path, or empty string if it cannot be determined.
+var mainModulePath = sync.OnceValue(func() string {
+	if buildInfo, ok := debug.ReadBuildInfo(); ok {
+		return buildInfo.Main.Path
+	}
+
+	return ""
+})
+
+// isModuleFunction checks whether given fully qualified function name
+// belongs to a package of given module (external test packages included).
+func isModuleFunction(fnName, module string) bool {
+	if !strings.HasPrefix(fnName, module) {
+		return false
+	}
+	rest := fnName[len(module):]
+
+	return strings.HasPrefix(rest, "/") ||
+		strings.HasPrefix(rest, ".") ||
+		strings.HasPrefix(rest, "_test.")
+}
+
+// skipFrameIf returns a [SkipFrame] which blacklists frames satisfying
+// given condition, and passes the responsibility to next skip frame otherwise.
+func skipFrameIf(next SkipFrame, cond func(fnName, file string) bool) SkipFrame {
+	return func(fnName, file string) bool {
+		if cond(fnName, file) {
+			return true
+		}
+
+		return next(fnName, file)
+	}
+}
+
 // AllowFrame is a [SkipFrame] which whitelists any given frame.
 // It can be used as the default/first [SkipFrame] in a chained
 // responsibility configuration.
@@ -115,3 +258,325 @@ func NoDomainFunctionName(fnName string) string {
 func SetFrameFnNameProcessor(fn FrameFnNameProcessor) {
 	frameFnNameProcessor = fn
 }
+
+// FrameFileProcessor is an alias for a function that can
+// manipulate the file path from a stack trace frame.
+// You can apply customizations upon file path output this way,
+// for example to not expose absolute paths of the build machine.
+type FrameFileProcessor func(file string) string
+
+// BaseNameOnly is a [FrameFileProcessor] which returns only the file's name,
+// removing the directory part.
+// Example: "/Users/bogdan/work/go/xerr/errors_test.go" => "errors_test.go" .
+func BaseNameOnly(file string) string {
+	if lastSlashPos := strings.LastIndex(file, "/"); lastSlashPos >= 0 {
+		file = file[lastSlashPos+1:]
+	}
+
+	return file
+}
+
+// TrimGOPATH is a [FrameFileProcessor] which removes the GOPATH part
+// (including "pkg/mod" or "src") from a file path.
+// Example: "/Users/bogdan/go/pkg/mod/github.com/actforgood/xerr@v1.0.0/errors.go"
+// => "github.com/actforgood/xerr@v1.0.0/errors.go" .
+// Files outside GOPATH are returned as they are.
+func TrimGOPATH(file string) string {
+	for _, prefix := range goPathPrefixes() {
+		if strings.HasPrefix(file, prefix) {
+			return file[len(prefix):]
+		}
+	}
+
+	return file
+}
+
+// goPathPrefixes returns the "pkg/mod" and "src" directories of each GOPATH entry.
+var goPathPrefixes = sync.OnceValue(func() []string {
+	var prefixes []string
+	for _, goPath := range filepath.SplitList(build.Default.GOPATH) {
+		goPath = strings.TrimSuffix(filepath.ToSlash(goPath), "/")
+		if goPath != "" {
+			prefixes = append(prefixes, goPath+"/pkg/mod/", goPath+"/src/")
+		}
+	}
+
+	return prefixes
+})
+
+// RelativeToModuleRoot is a [FrameFileProcessor] which returns the file path
+// relative to the root of the module the file belongs to (the closest directory
+// containing a go.mod file). Files from the module cache are returned relative
+// to it, so that module path and version are kept.
+// Example: "/Users/bogdan/work/go/xerr/errors_test.go" => "errors_test.go",
+// "/Users/bogdan/go/pkg/mod/github.com/actforgood/xerr@v1.0.0/errors.go"
+// => "github.com/actforgood/xerr@v1.0.0/errors.go" .
+// The module root is looked up on the file system (and cached per directory),
+// files whose module root cannot be found, like in the case of a binary running
+// on another machine than the one it was built on, are returned as they are.
+// Consider building with -trimpath flag for the latter scenario.
+func RelativeToModuleRoot(file string) string {
+	if modPos := strings.LastIndex(file, "/pkg/mod/"); modPos >= 0 {
+		return file[modPos+len("/pkg/mod/"):]
+	}
+	if root := moduleRoot(path.Dir(file)); root != "" {
+		return strings.TrimPrefix(file[len(root):], "/")
+	}
+
+	return file
+}
+
+// moduleRoots caches the module root of a directory, keyed by directory.
+var moduleRoots sync.Map
+
+// moduleRoot returns the closest directory, starting with the given one and
+// going up, which contains a go.mod file. Returns empty string if none is found.
+func moduleRoot(dir string) string {
+	if !path.IsAbs(dir) && filepath.VolumeName(dir) == "" {
+		return "" // not a file system path (ex: built with -trimpath).
+	}
+	if root, found := moduleRoots.Load(dir); found {
+		return root.(string)
+	}
+
+	var root string
+	if _, err := os.Stat(filepath.Join(filepath.FromSlash(dir), "go.mod")); err == nil {
+		root = dir
+	} else if parent := path.Dir(dir); parent != dir {
+		root = moduleRoot(parent)
+	}
+	moduleRoots.Store(dir, root)
+
+	return root
+}
+
+// SetFrameFileProcessor configures the function this package uses
+// in order to manipulate the file path from a stack trace frame.
+// You will call it usually somewhere in the bootstrap process of your
+// application. For example:
+//
+//	// myapp/bootstrap.go
+//	func init() {
+//		xerr.SetFrameFileProcessor(xerr.RelativeToModuleRoot)
+//	}
+func SetFrameFileProcessor(fn FrameFileProcessor) {
+	frameFileProcessor = fn
+}
+
+// StackFormat defines the way a stack trace is rendered
+// in the extended (%+v) output of an error.
+type StackFormat int
+
+const (
+	// StackFormatDefault renders the error's message followed by the frames.
+	StackFormatDefault StackFormat = iota
+	// StackFormatAnnotated renders, in addition to [StackFormatDefault],
+	// each Wrap's message next to the frame captured at that Wrap, like:
+	//
+	//	github.com/actforgood/xerr_test.ReadConfig
+	//		/Users/bogdan/work/go/xerr/config.go:42  — "reading config"
+	StackFormatAnnotated
+	// StackFormatInline renders the error's message followed by the frames
+	// on a single line, for log aggregators treating newlines as records separators, like:
+	//
+	//	reading config [main.ReadConfig(config.go:42) <- main.main(main.go:7)]
+	StackFormatInline
+	// StackFormatLayered renders each wrapping error's message followed by
+	// only the frames it contributed to the stack trace, like:
+	//
+	//	reading config
+	//	github.com/actforgood/xerr_test.ReadConfig
+	//		/Users/bogdan/work/go/xerr/config.go:42
+	//	caused by: file not found
+	//	github.com/actforgood/xerr_test.OpenFile
+	//		/Users/bogdan/work/go/xerr/file.go:13
+	//	main.main
+	//		/Users/bogdan/work/go/xerr/main.go:7
+	StackFormatLayered
+)
+
+// SetStackFormat configures the way stack traces are rendered.
+// You will call it usually somewhere in the bootstrap process of your
+// application. For example:
+//
+//	// myapp/bootstrap.go
+//	func init() {
+//		xerr.SetStackFormat(xerr.StackFormatAnnotated)
+//	}
+func SetStackFormat(format StackFormat) {
+	stackFormat = format
+}
+
+// FrameWriter is an alias for a function that writes a stack trace frame
+// in the extended (%+v) output of an error.
+// The frame has the configured [FrameFnNameProcessor] / [FrameFileProcessor] applied.
+// The writer is responsible for separating frames (for example, by prefixing
+// each frame with a new line).
+type FrameWriter func(w io.Writer, f Frame)
+
+// SetFrameWriter configures the function this package uses
+// in order to write a stack trace frame, replacing the default layout:
+//
+//	<functionName>
+//		<file>:<line>
+//
+// A configured [FrameWriter] takes precedence over the [StackFormat] frames
+// layout (and [StackFormatAnnotated] annotations are not rendered).
+// Pass nil to restore the default layout.
+// You will call it usually somewhere in the bootstrap process of your
+// application. For example:
+//
+//	// myapp/bootstrap.go
+//	func init() {
+//		xerr.SetFrameWriter(xerr.LogfmtFrameWriter)
+//	}
+func SetFrameWriter(fn FrameWriter) {
+	frameWriter = fn
+}
+
+// LogfmtFrameWriter is a [FrameWriter] which writes each frame on a new line,
+// in logfmt format, like:
+//
+//	function=github.com/actforgood/xerr_test.TestX file=/Users/bogdan/work/go/xerr/errors_test.go line=68
+func LogfmtFrameWriter(w io.Writer, f Frame) {
+	_, _ = io.WriteString(w, "\nfunction=")
+	_, _ = io.WriteString(w, logfmtValue(f.Function))
+	_, _ = io.WriteString(w, " file=")
+	_, _ = io.WriteString(w, logfmtValue(f.File))
+	_, _ = io.WriteString(w, " line=")
+	_, _ = io.WriteString(w, strconv.FormatInt(int64(f.Line), 10))
+}
+
+// logfmtValue returns given value, quoted if it contains characters
+// which are not allowed in an unquoted logfmt value.
+func logfmtValue(value string) string {
+	if value == "" || strings.ContainsAny(value, " =\"\t\n\r\\") {
+		return strconv.Quote(value)
+	}
+
+	return value
+}
+
+// JSONLinesFrameWriter is a [FrameWriter] which writes each frame on a new line,
+// as a JSON object, like:
+//
+//	{"function":"github.com/actforgood/xerr_test.TestX","file":"/Users/bogdan/work/go/xerr/errors_test.go","line":68}
+func JSONLinesFrameWriter(w io.Writer, f Frame) {
+	data, _ := json.Marshal(jsonFrame{
+		Function: f.Function,
+		File:     f.File,
+		Line:     f.Line,
+	})
+	_, _ = io.WriteString(w, "\n")
+	_, _ = w.Write(data)
+}
+
+// SetMaxPrintFrames configures the maximum number of frames rendered in the
+// extended (%+v) output of an error, the remaining frames being summarized
+// by a trailing "… N more" marker.
+// Only frames which are not skipped (see [SetSkipFrame]) are taken into account.
+// It does not affect the number of captured frames, nor [Frames].
+// Non-positive values mean no limit (default).
+// You will call it usually somewhere in the bootstrap process of your
+// application. For example:
+//
+//	// myapp/bootstrap.go
+//	func init() {
+//		xerr.SetMaxPrintFrames(10)
+//	}
+func SetMaxPrintFrames(n int) {
+	maxPrintFrames = n
+}
+
+// StackMode defines the way the stack trace of an error is captured.
+type StackMode int
+
+const (
+	// StackModeFull captures the whole stack trace (up to 32 frames, by default).
+	StackModeFull StackMode = iota
+	// StackModeCallerOnly captures only the frame of the call site
+	// (the caller of [New], [Wrap], ...), which is dramatically cheaper.
+	StackModeCallerOnly
+	// StackModeDisabled does not capture any stack trace, making [New], [Wrap], ...
+	// behave like [errors.New] / [fmt.Errorf] do, performance wise.
+	// A wrapped stack trace aware error's stack trace is kept.
+	StackModeDisabled
+)
+
+// depth returns the number of frames to be captured in this mode,
+// out of the given maximum number of frames.
+func (mode StackMode) depth(maxDepth int) int {
+	switch mode {
+	case StackModeCallerOnly:
+		return min(maxDepth, 1)
+	case StackModeDisabled:
+		return 0
+	default:
+		return maxDepth
+	}
+}
+
+// SetStackMode configures the way the stack traces of created errors are captured,
+// see [StackMode]. Defaults to [StackModeFull].
+// It can be overridden per error, with [WithStackMode].
+// Recovered panics' stack traces (see [Recover]) are always fully captured.
+// It lets the same code run cheaply in production, and verbosely in staging.
+// You will call it usually somewhere in the bootstrap process of your
+// application. For example:
+//
+//	// myapp/bootstrap.go
+//	func init() {
+//		if os.Getenv("APP_ENV") == "production" {
+//			xerr.SetStackMode(xerr.StackModeCallerOnly)
+//		}
+//	}
+func SetStackMode(mode StackMode) {
+	stackMode = mode
+}
+
+// SetStackSampling configures the probability, between 0 and 1, with which
+// a created error gets its stack trace captured. Defaults to 1 (all errors
+// have their stack trace captured). Values outside the [0, 1] interval are clamped.
+// Under error storms on hot paths, only a fraction of errors pay the cost
+// of capturing the stack trace, while the rest carry only their message,
+// and have a "stack omitted (sampled)" note written instead of their stack trace,
+// in the extended (%+v) format.
+// Wrapping an error which already has a stack trace is not sampled, as it
+// captures only the wrapping call's frame. Recovered panics are not sampled either.
+// You will call it usually somewhere in the bootstrap process of your
+// application. For example:
+//
+//	// myapp/bootstrap.go
+//	func init() {
+//		xerr.SetStackSampling(0.1)
+//	}
+func SetStackSampling(p float64) {
+	stackSampling = min(max(p, 0), 1)
+}
+
+// isStackSampledOut decides whether the stack trace of an error being created
+// should not be captured, according to the configured stack sampling.
+func isStackSampledOut() bool {
+	p := stackSampling
+
+	return p < 1 && rand.Float64() >= p
+}
+
+// SetOnError configures a hook invoked whenever this package creates an error
+// with stack trace ([New], [Errorf], [Wrap], [Wrapf], and the other constructors),
+// with the created error as parameter. It can be used to centrally count,
+// sample, or report errors. Pass nil to unset it (default).
+// The hook should be fast and safe for concurrent use, as it is called synchronously
+// on the path of error creation.
+// You will call it usually somewhere in the bootstrap process of your
+// application. For example:
+//
+//	// myapp/bootstrap.go
+//	func init() {
+//		xerr.SetOnError(func(err error) {
+//			errorsCounter.WithLabelValues(string(xerr.CodeOf(err))).Inc()
+//		})
+//	}
+func SetOnError(fn func(err error)) {
+	onError = fn
+}
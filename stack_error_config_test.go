@@ -6,8 +6,16 @@
 package xerr_test
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/build"
+	"io"
 	"os"
+	"path/filepath"
 	"runtime"
+	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/actforgood/xerr"
@@ -85,6 +93,203 @@ func TestSkipFrameGoRootSrcPath(t *testing.T) {
 	assertEqual(t, 1, nextCallsCnt)
 }
 
+func TestSkipFrameChains(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	tests := [...]struct {
+		name        string
+		subject     xerr.SkipFrameChain
+		inputFnName string
+		inputFile   string
+		expected    bool
+	}{
+		{
+			name:        "testing, testing function, expect true",
+			subject:     xerr.SkipFrameTesting,
+			inputFnName: "testing.tRunner",
+			inputFile:   "/usr/local/go/src/testing/testing.go",
+			expected:    true,
+		},
+		{
+			name:        "testing, other function, expect false",
+			subject:     xerr.SkipFrameTesting,
+			inputFnName: "github.com/actforgood/xerr_test.TestX",
+			inputFile:   "/foo/xerr/errors_test.go",
+			expected:    false,
+		},
+		{
+			name:        "runtime, runtime function, expect true",
+			subject:     xerr.SkipFrameRuntime,
+			inputFnName: "runtime.goexit",
+			inputFile:   "/usr/local/go/src/runtime/asm_amd64.s",
+			expected:    true,
+		},
+		{
+			name:        "runtime, runtime sub-package function, expect false",
+			subject:     xerr.SkipFrameRuntime,
+			inputFnName: "runtime/debug.Stack",
+			inputFile:   "/usr/local/go/src/runtime/debug/stack.go",
+			expected:    false,
+		},
+		{
+			name:        "vendor, vendor file, expect true",
+			subject:     xerr.SkipFrameVendor,
+			inputFnName: "github.com/foo/bar.Baz",
+			inputFile:   "/foo/myapp/vendor/github.com/foo/bar/baz.go",
+			expected:    true,
+		},
+		{
+			name:        "vendor, application file, expect false",
+			subject:     xerr.SkipFrameVendor,
+			inputFnName: "github.com/foo/myapp.Baz",
+			inputFile:   "/foo/myapp/baz.go",
+			expected:    false,
+		},
+		{
+			name:        "mod cache, module cache file, expect true",
+			subject:     xerr.SkipFrameModCache,
+			inputFnName: "github.com/foo/bar.Baz",
+			inputFile:   "/home/gopher/go/pkg/mod/github.com/foo/bar@v1.0.0/baz.go",
+			expected:    true,
+		},
+		{
+			name:        "mod cache, application file, expect false",
+			subject:     xerr.SkipFrameModCache,
+			inputFnName: "github.com/foo/myapp.Baz",
+			inputFile:   "/foo/myapp/baz.go",
+			expected:    false,
+		},
+		{
+			name:        "prefix, function name prefix, expect true",
+			subject:     xerr.SkipFramePrefix("github.com/gin-gonic/", "/foo/lib/"),
+			inputFnName: "github.com/gin-gonic/gin.(*Context).Next",
+			inputFile:   "/home/gopher/gin/context.go",
+			expected:    true,
+		},
+		{
+			name:        "prefix, file prefix, expect true",
+			subject:     xerr.SkipFramePrefix("github.com/gin-gonic/", "/foo/lib/"),
+			inputFnName: "lib.Baz",
+			inputFile:   "/foo/lib/baz.go",
+			expected:    true,
+		},
+		{
+			name:        "prefix, no prefix, expect false",
+			subject:     xerr.SkipFramePrefix("github.com/gin-gonic/", "/foo/lib/"),
+			inputFnName: "github.com/foo/myapp.Baz",
+			inputFile:   "/foo/myapp/baz.go",
+			expected:    false,
+		},
+		{
+			name:        "prefix, no prefixes, expect false",
+			subject:     xerr.SkipFramePrefix(),
+			inputFnName: "github.com/foo/myapp.Baz",
+			inputFile:   "/foo/myapp/baz.go",
+			expected:    false,
+		},
+	}
+
+	for _, testData := range tests {
+		test := testData // capture range variable
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			// arrange
+			nextCallsCnt := 0
+			next := func(_, _ string) bool {
+				nextCallsCnt++
+
+				return false
+			}
+
+			// act
+			result := test.subject(next)(test.inputFnName, test.inputFile)
+
+			// assert
+			assertEqual(t, test.expected, result)
+			if test.expected {
+				assertEqual(t, 0, nextCallsCnt)
+			} else {
+				assertEqual(t, 1, nextCallsCnt)
+			}
+		})
+	}
+}
+
+func TestSkipFrameNonModule(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		subject = xerr.SkipFrameNonModule("github.com/foo/lib")(xerr.AllowFrame)
+		tests   = [...]struct {
+			name        string
+			inputFnName string
+			expected    bool
+		}{
+			{
+				name:        "main module function, expect false",
+				inputFnName: "github.com/actforgood/xerr.New",
+				expected:    false,
+			},
+			{
+				name:        "main module sub-package function, expect false",
+				inputFnName: "github.com/actforgood/xerr/xerrhttp.ToProblem",
+				expected:    false,
+			},
+			{
+				name:        "main module external test package function, expect false",
+				inputFnName: "github.com/actforgood/xerr_test.TestX",
+				expected:    false,
+			},
+			{
+				name:        "main package function, expect false",
+				inputFnName: "main.main",
+				expected:    false,
+			},
+			{
+				name:        "extra module function, expect false",
+				inputFnName: "github.com/foo/lib/pkg.(*Client).Do",
+				expected:    false,
+			},
+			{
+				name:        "other module with main module as prefix, expect true",
+				inputFnName: "github.com/actforgood/xerrors.New",
+				expected:    true,
+			},
+			{
+				name:        "other module function, expect true",
+				inputFnName: "github.com/foo/other.Baz",
+				expected:    true,
+			},
+			{
+				name:        "standard library function, expect true",
+				inputFnName: "testing.tRunner",
+				expected:    true,
+			},
+			{
+				name:        "runtime function, expect true",
+				inputFnName: "runtime.goexit",
+				expected:    true,
+			},
+		}
+	)
+
+	for _, testData := range tests {
+		test := testData // capture range variable
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			// act
+			result := subject(test.inputFnName, "/foo/bar.go")
+
+			// assert
+			assertEqual(t, test.expected, result)
+		})
+	}
+}
+
 func TestShortFunctionName(t *testing.T) {
 	t.Parallel()
 
@@ -276,3 +481,449 @@ func TestNoDomainFunctionName(t *testing.T) {
 		})
 	}
 }
+
+func TestBaseNameOnly(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	subject := xerr.BaseNameOnly
+	tests := [...]struct {
+		name      string
+		inputFile string
+		expected  string
+	}{
+		{
+			name:      "empty, expect empty",
+			inputFile: "",
+			expected:  "",
+		},
+		{
+			name:      "file name, expect same string",
+			inputFile: "errors.go",
+			expected:  "errors.go",
+		},
+		{
+			name:      "absolute path, expect file name",
+			inputFile: "/Users/bogdan/work/go/xerr/errors.go",
+			expected:  "errors.go",
+		},
+		{
+			name:      "relative path, expect file name",
+			inputFile: "github.com/actforgood/xerr/errors.go",
+			expected:  "errors.go",
+		},
+	}
+
+	for _, testData := range tests {
+		test := testData // capture range variable
+		t.Run(test.name, func(t *testing.T) {
+			// act
+			result := subject(test.inputFile)
+
+			// assert
+			assertEqual(t, test.expected, result)
+		})
+	}
+}
+
+func TestTrimGOPATH(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		subject = xerr.TrimGOPATH
+		goPath  = filepath.ToSlash(filepath.SplitList(build.Default.GOPATH)[0])
+		tests   = [...]struct {
+			name      string
+			inputFile string
+			expected  string
+		}{
+			{
+				name:      "empty, expect empty",
+				inputFile: "",
+				expected:  "",
+			},
+			{
+				name:      "path outside GOPATH, expect same string",
+				inputFile: "/foo/bar/baz.go",
+				expected:  "/foo/bar/baz.go",
+			},
+			{
+				name:      "GOPATH/pkg/mod path, expect trimmed path",
+				inputFile: goPath + "/pkg/mod/github.com/actforgood/xerr@v1.0.0/errors.go",
+				expected:  "github.com/actforgood/xerr@v1.0.0/errors.go",
+			},
+			{
+				name:      "GOPATH/src path, expect trimmed path",
+				inputFile: goPath + "/src/github.com/actforgood/xerr/errors.go",
+				expected:  "github.com/actforgood/xerr/errors.go",
+			},
+			{
+				name:      "GOPATH/bin path, expect same string",
+				inputFile: goPath + "/bin/foo",
+				expected:  goPath + "/bin/foo",
+			},
+		}
+	)
+
+	for _, testData := range tests {
+		test := testData // capture range variable
+		t.Run(test.name, func(t *testing.T) {
+			// act
+			result := subject(test.inputFile)
+
+			// assert
+			assertEqual(t, test.expected, result)
+		})
+	}
+}
+
+func TestRelativeToModuleRoot(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		subject       = xerr.RelativeToModuleRoot
+		_, file, _, _ = runtime.Caller(0)
+		moduleRoot    = filepath.ToSlash(filepath.Dir(file))
+		tests         = [...]struct {
+			name      string
+			inputFile string
+			expected  string
+		}{
+			{
+				name:      "empty, expect empty",
+				inputFile: "",
+				expected:  "",
+			},
+			{
+				name:      "module root file, expect file name",
+				inputFile: file,
+				expected:  "stack_error_config_test.go",
+			},
+			{
+				name:      "nested package file, expect path relative to its module root",
+				inputFile: moduleRoot + "/xerrhttp/problem.go",
+				expected:  "xerrhttp/problem.go",
+			},
+			{
+				name:      "nested module file, expect path relative to its module root",
+				inputFile: moduleRoot + "/xerrgrpc/status.go",
+				expected:  "status.go",
+			},
+			{
+				name:      "module cache file, expect path relative to module cache",
+				inputFile: "/home/gopher/go/pkg/mod/github.com/actforgood/xerr@v1.0.0/errors.go",
+				expected:  "github.com/actforgood/xerr@v1.0.0/errors.go",
+			},
+			{
+				name:      "path without module, expect same string",
+				inputFile: "/foo/bar/baz.go",
+				expected:  "/foo/bar/baz.go",
+			},
+			{
+				name:      "trimmed path, expect same string",
+				inputFile: "github.com/actforgood/xerr/errors.go",
+				expected:  "github.com/actforgood/xerr/errors.go",
+			},
+		}
+	)
+
+	for _, testData := range tests {
+		test := testData // capture range variable
+		t.Run(test.name, func(t *testing.T) {
+			// act
+			result := subject(test.inputFile)
+
+			// assert
+			assertEqual(t, test.expected, result)
+		})
+	}
+}
+
+func TestSetFrameFileProcessor(t *testing.T) { // test is not parallel as it changes global configuration.
+	// arrange
+	xerr.SetFrameFileProcessor(xerr.RelativeToModuleRoot)
+	defer xerr.SetFrameFileProcessor(nil)
+
+	// act
+	err := xerr.New("something went bad")
+
+	// assert
+	frames := xerr.Frames(err)
+	if assertTrue(t, len(frames) > 1) {
+		assertEqual(t, "stack_error_config_test.go", frames[0].File)
+		assertEqual(t, "testing/testing.go", frames[1].File)
+	}
+	errMsgWithStack := fmt.Sprintf("%+v", err)
+	assertTrue(t, strings.Contains(errMsgWithStack, "\n\tstack_error_config_test.go:"))
+	assertTrue(t, strings.Contains(errMsgWithStack, "\n\ttesting/testing.go:"))
+}
+
+func TestLogfmtFrameWriter(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		subject = xerr.LogfmtFrameWriter
+		tests   = [...]struct {
+			name       string
+			inputFrame xerr.Frame
+			expected   string
+		}{
+			{
+				name: "simple values, expect unquoted values",
+				inputFrame: xerr.Frame{
+					Function: "github.com/actforgood/xerr_test.TestX",
+					File:     "/foo/xerr/errors_test.go",
+					Line:     68,
+				},
+				expected: "\nfunction=github.com/actforgood/xerr_test.TestX file=/foo/xerr/errors_test.go line=68",
+			},
+			{
+				name: "values with special chars, expect quoted values",
+				inputFrame: xerr.Frame{
+					Function: "",
+					File:     `C:\My Projects\errors_test.go`,
+					Line:     7,
+				},
+				expected: "\n" + `function="" file="C:\\My Projects\\errors_test.go" line=7`,
+			},
+		}
+	)
+
+	for _, testData := range tests {
+		test := testData // capture range variable
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			// arrange
+			var buf bytes.Buffer
+
+			// act
+			subject(&buf, test.inputFrame)
+
+			// assert
+			assertEqual(t, test.expected, buf.String())
+		})
+	}
+}
+
+func TestJSONLinesFrameWriter(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		subject = xerr.JSONLinesFrameWriter
+		buf     bytes.Buffer
+		frame   = xerr.Frame{
+			Function: "github.com/actforgood/xerr_test.TestX",
+			File:     "/foo/xerr/errors_test.go",
+			Line:     68,
+			PC:       123,
+		}
+	)
+
+	// act
+	subject(&buf, frame)
+	subject(&buf, frame)
+
+	// assert
+	line := `{"function":"github.com/actforgood/xerr_test.TestX","file":"/foo/xerr/errors_test.go","line":68}`
+	assertEqual(t, "\n"+line+"\n"+line, buf.String())
+}
+
+func TestSetFrameWriter(t *testing.T) { // test is not parallel as it changes global configuration.
+	// arrange
+	var writtenFrames []xerr.Frame
+	xerr.SetFrameWriter(func(w io.Writer, f xerr.Frame) {
+		writtenFrames = append(writtenFrames, f)
+		_, _ = io.WriteString(w, "\n"+f.Function)
+	})
+	defer xerr.SetFrameWriter(nil)
+	xerr.SetStackFormat(xerr.StackFormatAnnotated)
+	defer xerr.SetStackFormat(xerr.StackFormatDefault)
+	err := xerr.NewOpt(
+		"something went bad",
+		xerr.WithFrameFnNameProcessor(xerr.OnlyFunctionName),
+		xerr.WithFrameFileProcessor(xerr.BaseNameOnly),
+	)
+
+	// act
+	result := fmt.Sprintf("%+v", err)
+
+	// assert
+	assertTrue(t, strings.HasPrefix(result, "something went bad\nTestSetFrameWriter\ntRunner\n"))
+	assertFalse(t, strings.Contains(result, "  — "))
+	assertEqual(t, xerr.Frames(err), writtenFrames)
+	if assertTrue(t, len(writtenFrames) > 1) {
+		assertEqual(t, "stack_error_config_test.go", writtenFrames[0].File)
+	}
+}
+
+func TestSetMaxPrintFrames(t *testing.T) { // test is not parallel as it changes global configuration.
+	// arrange
+	xerr.SetMaxPrintFrames(1)
+	defer xerr.SetMaxPrintFrames(0)
+	err := xerr.NewOpt("something went bad", xerr.WithFrameFnNameProcessor(xerr.OnlyFunctionName))
+	framesCnt := len(xerr.Frames(err))
+
+	// act
+	result := fmt.Sprintf("%+v", err)
+
+	// assert
+	if assertTrue(t, framesCnt > 1) {
+		assertTrue(t, strings.HasPrefix(result, "something went bad\nTestSetMaxPrintFrames\n\t"))
+		assertTrue(t, strings.HasSuffix(result, "\n… "+strconv.Itoa(framesCnt-1)+" more"))
+		assertEqual(t, 3, strings.Count(result, "\n"))
+	}
+
+	// arrange
+	xerr.SetStackFormat(xerr.StackFormatInline)
+	defer xerr.SetStackFormat(xerr.StackFormatDefault)
+
+	// act
+	result = fmt.Sprintf("%+v", err)
+
+	// assert
+	assertTrue(t, strings.HasPrefix(result, "something went bad [TestSetMaxPrintFrames("))
+	assertTrue(t, strings.HasSuffix(result, ") <- … "+strconv.Itoa(framesCnt-1)+" more]"))
+
+	// arrange
+	xerr.SetMaxPrintFrames(framesCnt)
+
+	// act
+	result = fmt.Sprintf("%+v", err)
+
+	// assert
+	assertFalse(t, strings.Contains(result, "more"))
+}
+
+func TestSetOnError(t *testing.T) { // test is not parallel as it changes global configuration.
+	// arrange
+	var created []error
+	xerr.SetOnError(func(err error) {
+		created = append(created, err)
+	})
+	defer xerr.SetOnError(nil)
+	origErr := errors.New("some error")
+
+	// act
+	newErr := xerr.New("new")
+	errorfErr := xerr.Errorf("errorf %d", 1)
+	wrapErr := xerr.Wrap(origErr, "wrap")
+	wrapfErr := xerr.Wrapf(origErr, "wrapf %d", 1)
+	nilWrapErr := xerr.Wrap(nil, "wrap")
+	codeErr := xerr.NewCode("E001", "code")
+	kindErr := xerr.NotFound("user %d", 42)
+	_ = xerr.WithFields(origErr, xerr.F("key", "value")) // annotations are not creations.
+
+	// assert
+	assertNil(t, nilWrapErr)
+	assertEqual(t, []error{newErr, errorfErr, wrapErr, wrapfErr, codeErr, kindErr}, created)
+	assertEqual(t, xerr.Code("E001"), xerr.CodeOf(created[4]))
+	assertEqual(t, xerr.KindNotFound, xerr.KindOf(created[5]))
+}
+
+func TestSetOnError_withOptions(t *testing.T) { // test is not parallel as it changes global configuration.
+	// arrange
+	var globalCreated, optCreated []error
+	xerr.SetOnError(func(err error) {
+		globalCreated = append(globalCreated, err)
+	})
+	defer xerr.SetOnError(nil)
+	factory := xerr.NewFactory().With(xerr.F("component", "mylib"))
+	optFactory := xerr.NewFactory(xerr.WithOnError(func(err error) {
+		optCreated = append(optCreated, err)
+	}))
+
+	// act
+	optErr := xerr.NewOpt("new", xerr.WithOnError(func(err error) {
+		optCreated = append(optCreated, err)
+	}))
+	factoryErr := factory.New("new")
+	optFactoryErr := optFactory.Wrap(optErr, "wrap")
+
+	// assert
+	assertEqual(t, []error{factoryErr}, globalCreated)
+	assertEqual(t, map[string]any{"component": "mylib"}, xerr.Fields(globalCreated[0]))
+	assertEqual(t, []error{optErr, optFactoryErr}, optCreated)
+}
+
+func TestSetStackSampling(t *testing.T) { // test is not parallel as it changes global configuration.
+	// arrange
+	xerr.SetStackSampling(0)
+	defer xerr.SetStackSampling(1)
+	stdErr := errors.New("connection refused")
+
+	// act
+	err1 := xerr.New("something went bad")
+	err2 := xerr.Wrap(stdErr, "db query failed")
+	err3 := xerr.Wrap(err1, "could not get user")
+
+	// assert
+	assertEqual(t, "something went bad\nstack omitted (sampled)", fmt.Sprintf("%+v", err1))
+	assertEqual(t, "db query failed: connection refused\nstack omitted (sampled)", fmt.Sprintf("%+v", err2))
+	assertEqual(t, "could not get user: something went bad\nstack omitted (sampled)", fmt.Sprintf("%+v", err3))
+	assertNil(t, xerr.Frames(err1))
+	assertNil(t, xerr.Frames(err3))
+
+	// arrange
+	xerr.SetStackSampling(-1) // clamped to 0
+	xerr.SetStackFormat(xerr.StackFormatLayered)
+	defer xerr.SetStackFormat(xerr.StackFormatDefault)
+
+	// act
+	result := fmt.Sprintf("%+v", xerr.Wrap(xerr.New("something went bad"), "could not get user"))
+
+	// assert
+	assertEqual(
+		t,
+		"could not get user\nstack omitted (sampled)\ncaused by: something went bad\nstack omitted (sampled)",
+		result,
+	)
+
+	// arrange
+	xerr.SetStackSampling(2) // clamped to 1
+
+	// act
+	err4 := xerr.New("something went bad")
+
+	// assert
+	assertTrue(t, len(xerr.Frames(err4)) > 0)
+	assertFalse(t, strings.Contains(fmt.Sprintf("%+v", err4), "stack omitted"))
+}
+
+func TestSetStackMode(t *testing.T) { // test is not parallel as it changes global configuration.
+	// arrange
+	xerr.SetStackMode(xerr.StackModeCallerOnly)
+	defer xerr.SetStackMode(xerr.StackModeFull)
+
+	// act
+	err1 := xerr.New("something went bad")
+	err2 := xerr.Wrap(err1, "could not do that")
+	err3 := xerr.NewOpt("something went bad", xerr.WithStackMode(xerr.StackModeFull))
+
+	// assert
+	frames := xerr.Frames(err1)
+	if assertEqual(t, 1, len(frames)) {
+		assertEqual(t, "github.com/actforgood/xerr_test.TestSetStackMode", frames[0].Function)
+	}
+	assertEqual(t, 2, len(xerr.Frames(err2)))
+	assertTrue(t, len(xerr.Frames(err3)) > 1)
+
+	// arrange
+	xerr.SetStackMode(xerr.StackModeDisabled)
+
+	// act
+	err4 := xerr.New("something went bad")
+	err5 := xerr.Wrap(err1, "could not do that")
+	err6 := xerr.Wrap(errors.New("connection refused"), "db query failed")
+
+	// assert
+	assertNil(t, xerr.Frames(err4))
+	assertEqual(t, "something went bad", fmt.Sprintf("%+v", err4))
+	assertEqual(t, frames, xerr.Frames(err5)) // wrapped error's stack trace is kept.
+	assertNil(t, xerr.Frames(err6))
+	assertEqual(t, "db query failed: connection refused", fmt.Sprintf("%+v", err6))
+}
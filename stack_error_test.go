@@ -406,6 +406,465 @@ func TestNew_withGlobalConfigurationChanged(t *testing.T) {
 	}
 }
 
+func TestWrap_withStackFormatAnnotated(t *testing.T) {
+	// arrange
+	xerr.SetStackFormat(xerr.StackFormatAnnotated)
+	defer xerr.SetStackFormat(xerr.StackFormatDefault) // restore original global state
+	var (
+		origErr = xerr.New("file not found")
+		regexes = []string{
+			`^reading config: opening file: file not found\n`,
+			`github\.com/actforgood/xerr_test\.TestWrap_withStackFormatAnnotated\n\t.+stack_error_test\.go:\d+  — "reading config"\n`,
+			`github\.com/actforgood/xerr_test\.TestWrap_withStackFormatAnnotated\n\t.+stack_error_test\.go:\d+  — "opening file"\n`,
+			`github\.com/actforgood/xerr_test\.TestWrap_withStackFormatAnnotated\n\t.+stack_error_test\.go:\d+  — "file not found"\n`,
+			`testing.tRunner\n\t.+testing.go:\d+\n`,
+		}
+	)
+
+	// act
+	resultErr := xerr.Wrap(xerr.Wrap(origErr, "opening file"), "reading config")
+
+	// assert
+	errMsgWithStack := fmt.Sprintf("%+v", resultErr)
+	for _, reg := range regexes {
+		matched, _ := regexp.MatchString(reg, errMsgWithStack)
+		if !assertTrue(t, matched) {
+			t.Log("regex", reg, "errMsgWithStack", errMsgWithStack)
+		}
+	}
+	assertEqual(t, 3, strings.Count(errMsgWithStack, "  — "))
+	assertEqual(t, "reading config: opening file: file not found", fmt.Sprintf("%v", resultErr))
+}
+
+func TestWrap_withStackFormatInline(t *testing.T) {
+	// arrange
+	xerr.SetStackFormat(xerr.StackFormatInline)
+	defer xerr.SetStackFormat(xerr.StackFormatDefault) // restore original global state
+	var (
+		origErr = xerr.NewOpt("file not found", xerr.WithFrameFileProcessor(xerr.BaseNameOnly))
+		regex   = `^opening file: file not found \[` +
+			`github\.com/actforgood/xerr_test\.TestWrap_withStackFormatInline\(.+stack_error_test\.go:\d+\) <- ` +
+			`github\.com/actforgood/xerr_test\.TestWrap_withStackFormatInline\(.+stack_error_test\.go:\d+\) <- ` +
+			`testing\.tRunner\(.+testing\.go:\d+\)`
+		origRegex = `^file not found \[` +
+			`github\.com/actforgood/xerr_test\.TestWrap_withStackFormatInline\(stack_error_test\.go:\d+\) <- ` +
+			`testing\.tRunner\(testing\.go:\d+\) <- .+\]$`
+	)
+
+	// act
+	resultErr := xerr.Wrap(origErr, "opening file")
+
+	// assert
+	errMsgWithStack := fmt.Sprintf("%+v", resultErr)
+	matched, _ := regexp.MatchString(regex, errMsgWithStack)
+	if !assertTrue(t, matched) {
+		t.Log("regex", regex, "errMsgWithStack", errMsgWithStack)
+	}
+	assertFalse(t, strings.Contains(errMsgWithStack, "\n"))
+	assertTrue(t, strings.HasSuffix(errMsgWithStack, ")]"))
+	origErrMsgWithStack := fmt.Sprintf("%+v", origErr)
+	matched, _ = regexp.MatchString(origRegex, origErrMsgWithStack)
+	if !assertTrue(t, matched) {
+		t.Log("regex", origRegex, "errMsgWithStack", origErrMsgWithStack)
+	}
+	assertEqual(t, "file not found", fmt.Sprintf("%+v", xerr.NewOpt("file not found", xerr.WithNoStack())))
+}
+
+func TestWrap_withStackFormatLayered(t *testing.T) {
+	// arrange
+	xerr.SetStackFormat(xerr.StackFormatLayered)
+	defer xerr.SetStackFormat(xerr.StackFormatDefault) // restore original global state
+	var (
+		origErr = xerr.WithCode(errors.New("file not found"), "E001")
+		fnName  = `github\.com/actforgood/xerr_test\.TestWrap_withStackFormatLayered`
+		regex   = `^reading config\n` +
+			fnName + `\n\t.+stack_error_test\.go:\d+\n` +
+			`caused by: opening file\n` +
+			fnName + `\n\t.+stack_error_test\.go:\d+\n` +
+			`testing\.tRunner\n\t.+testing\.go:\d+\n` +
+			`.+\n\t.+\n` +
+			`caused by: file not found$`
+	)
+
+	// act
+	resultErr := xerr.Wrap(xerr.Wrap(origErr, "opening file"), "reading config")
+
+	// assert
+	errMsgWithStack := fmt.Sprintf("%+v", resultErr)
+	matched, _ := regexp.MatchString(regex, errMsgWithStack)
+	if !assertTrue(t, matched) {
+		t.Log("regex", regex, "errMsgWithStack", errMsgWithStack)
+	}
+	assertEqual(t, 2, strings.Count(errMsgWithStack, "TestWrap_withStackFormatLayered"))
+	assertEqual(t, "reading config: opening file: file not found", fmt.Sprintf("%v", resultErr))
+}
+
+func TestFrames(t *testing.T) {
+	// arrange
+	var (
+		subject = xerr.Frames
+		origErr = xerr.New("some error with stack")
+		wrapErr = fmt.Errorf("std wrap: %w", xerr.Wrap(origErr, "wrap"))
+	)
+
+	// act
+	origFrames := subject(origErr)
+	wrapFrames := subject(wrapErr)
+
+	// assert
+	if assertTrue(t, len(origFrames) > 1) {
+		assertEqual(t, "github.com/actforgood/xerr_test.TestFrames", origFrames[0].Function)
+		assertTrue(t, strings.HasSuffix(origFrames[0].File, "stack_error_test.go"))
+		assertTrue(t, origFrames[0].Line > 0)
+		assertTrue(t, origFrames[0].PC > 0)
+		assertEqual(t, "testing.tRunner", origFrames[1].Function)
+	}
+	if assertEqual(t, len(origFrames)+1, len(wrapFrames)) {
+		assertEqual(t, origFrames[0].Function, wrapFrames[0].Function)
+		assertTrue(t, origFrames[0].Line < wrapFrames[0].Line)
+		assertEqual(t, origFrames, wrapFrames[1:])
+	}
+	assertNil(t, subject(nil))
+	assertNil(t, subject(errors.New("some standard error")))
+
+	// act - with global configuration changed
+	xerr.SetSkipFrame(xerr.SkipFrameGoRootSrcPath(xerr.AllowFrame))
+	xerr.SetFrameFnNameProcessor(xerr.OnlyFunctionName)
+	defer func() { // restore original global state
+		xerr.SetSkipFrame(xerr.AllowFrame)
+		xerr.SetFrameFnNameProcessor(nil)
+	}()
+	configuredFrames := subject(origErr)
+
+	// assert
+	if assertEqual(t, 1, len(configuredFrames)) {
+		assertEqual(t, "TestFrames", configuredFrames[0].Function)
+	}
+}
+
+func TestWrap_chainAwareStack(t *testing.T) {
+	t.Parallel()
+
+	t.Run("stack error wrapped by a standard error", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		innerErr := xerr.New("inner")
+		midErr := fmt.Errorf("mid: %w", xerr.WithFields(innerErr, xerr.F("key", "value")))
+
+		// act
+		result := xerr.Wrap(midErr, "outer")
+
+		// assert
+		assertEqual(t, "outer: mid: inner", result.Error())
+		innerFrames := xerr.Frames(innerErr)
+		resultFrames := xerr.Frames(result)
+		if assertEqual(t, len(innerFrames)+1, len(resultFrames)) {
+			assertEqual(t, innerFrames, resultFrames[1:])
+		}
+	})
+
+	t.Run("foreign stack tracer", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		foreignErr := newStackTracerErr("foreign")
+
+		// act
+		result := xerr.Wrapf(fmt.Errorf("mid: %w", foreignErr), "outer %d", 1)
+
+		// assert
+		resultFrames := xerr.Frames(result)
+		if assertEqual(t, len(foreignErr.frames)+1, len(resultFrames)) {
+			assertEqual(t, foreignErr.frames[0].PC, resultFrames[1].PC)
+			assertEqual(t, foreignErr.frames[0].Function, resultFrames[1].Function)
+		}
+	})
+
+	t.Run("pkg/errors like stack", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		pkgErr := newPkgErrorsErr("pkg")
+
+		// act
+		result := xerr.Wrap(fmt.Errorf("mid: %w", pkgErr), "outer")
+
+		// assert
+		resultFrames := xerr.Frames(result)
+		if assertEqual(t, len(pkgErr.stack)+1, len(resultFrames)) {
+			assertEqual(t, uintptr(pkgErr.stack[0]), resultFrames[1].PC)
+		}
+	})
+
+	t.Run("multi error stack is not reused", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		innerErr := xerr.New("inner")
+		multiErr := xerr.NewMultiError().Add(innerErr)
+
+		// act
+		result := xerr.Wrap(multiErr, "outer")
+
+		// assert
+		assertEqual(t, len(xerr.Frames(innerErr)), len(xerr.Frames(result)))
+		assertTrue(t, xerr.Frames(innerErr)[0].Line != xerr.Frames(result)[0].Line)
+	})
+
+	t.Run("repeated wrapping", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		const depth = 50
+		innerErr := xerr.New("inner")
+		innerFrames := xerr.Frames(innerErr)
+
+		// act
+		result := innerErr
+		for i := 0; i < depth; i++ {
+			result = xerr.Wrap(result, "wrap")
+		}
+
+		// assert
+		resultFrames := xerr.Frames(result)
+		if assertEqual(t, len(innerFrames)+depth, len(resultFrames)) {
+			assertEqual(t, innerFrames, resultFrames[depth:])
+			for _, f := range resultFrames[:depth] {
+				assertEqual(t, resultFrames[0], f)
+			}
+		}
+	})
+
+	t.Run("same error wrapped concurrently", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		const goroutinesNo = 10
+		var (
+			innerErr = xerr.Wrap(xerr.New("inner"), "mid")
+			results  = make(chan error, goroutinesNo)
+		)
+		innerFrames := xerr.Frames(innerErr)
+
+		// act
+		for idx := 0; idx < goroutinesNo; idx++ {
+			go func() {
+				results <- xerr.Wrap(xerr.Wrap(innerErr, "outer"), "outermost")
+			}()
+		}
+
+		// assert
+		for idx := 0; idx < goroutinesNo; idx++ {
+			result := <-results
+			resultFrames := xerr.Frames(result)
+			if assertEqual(t, len(innerFrames)+2, len(resultFrames)) {
+				assertTrue(t, strings.HasPrefix(resultFrames[0].Function, "github.com/actforgood/xerr_test.TestWrap_chainAwareStack"))
+				assertEqual(t, resultFrames[0].Line, resultFrames[1].Line)
+				assertEqual(t, innerFrames, resultFrames[2:])
+			}
+		}
+		assertEqual(t, innerFrames, xerr.Frames(innerErr))
+	})
+}
+
+func TestStackError_StackFrames(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	err := xerr.New("some error")
+	var subject xerr.StackTracer
+
+	// act
+	found := errors.As(err, &subject)
+
+	// assert
+	if assertTrue(t, found) {
+		frames := subject.StackFrames()
+		assertEqual(t, xerr.Frames(err), frames)
+		for _, frame := range frames {
+			assertTrue(t, frame.PC != 0)
+		}
+	}
+}
+
+func TestStackError_StackTrace(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	err := xerr.New("some error")
+	var subject interface{ StackTrace() []uintptr }
+
+	// act
+	found := errors.As(err, &subject)
+
+	// assert
+	if assertTrue(t, found) {
+		stackPCs := subject.StackTrace()
+		frames := xerr.Frames(err)
+		if assertEqual(t, len(frames), len(stackPCs)) {
+			for idx, frame := range frames {
+				assertEqual(t, frame.PC, stackPCs[idx])
+			}
+		}
+	}
+}
+
+func TestFrames_withForeignStackTracer(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	foreignErr := newStackTracerErr("foreign")
+	wrappedErr := fmt.Errorf("wrapped: %w", foreignErr)
+
+	// act
+	result := xerr.Frames(wrappedErr)
+	resultWithXerr := xerr.Frames(xerr.Wrap(wrappedErr, "wrap"))
+
+	// assert
+	assertEqual(t, foreignErr.frames, result)
+	if assertEqual(t, len(foreignErr.frames)+1, len(resultWithXerr)) {
+		assertEqual(t, "github.com/actforgood/xerr_test.TestFrames_withForeignStackTracer", resultWithXerr[0].Function)
+	}
+	assertEqual(t, xerr.Fingerprint(foreignErr), xerr.Fingerprint(wrappedErr))
+	assertTrue(t, xerr.Fingerprint(foreignErr) != xerr.Fingerprint(errors.New("foreign")))
+	assertNil(t, xerr.Frames(&stackTracerErr{msg: "no stack"}))
+}
+
+// stackTracerErr is a foreign error implementing [xerr.StackTracer].
+type stackTracerErr struct {
+	msg    string
+	frames []xerr.Frame
+}
+
+func newStackTracerErr(msg string) *stackTracerErr {
+	var st xerr.StackTracer
+	_ = errors.As(xerr.New(msg), &st)
+
+	return &stackTracerErr{msg: msg, frames: st.StackFrames()}
+}
+
+func (err *stackTracerErr) Error() string {
+	return err.msg
+}
+
+func (err *stackTracerErr) StackFrames() []xerr.Frame {
+	return err.frames
+}
+
+// pkgFrame and pkgStackTrace mimic github.com/pkg/errors' Frame and StackTrace.
+type (
+	pkgFrame      uintptr
+	pkgStackTrace []pkgFrame
+)
+
+// pkgErrorsErr mimics a github.com/pkg/errors error.
+type pkgErrorsErr struct {
+	msg   string
+	stack pkgStackTrace
+}
+
+func newPkgErrorsErr(msg string) *pkgErrorsErr {
+	frames := newStackTracerErr(msg).frames
+	stack := make(pkgStackTrace, len(frames))
+	for idx, frame := range frames {
+		stack[idx] = pkgFrame(frame.PC)
+	}
+
+	return &pkgErrorsErr{msg: msg, stack: stack}
+}
+
+func (err *pkgErrorsErr) Error() string {
+	return err.msg
+}
+
+func (err *pkgErrorsErr) StackTrace() pkgStackTrace {
+	return err.stack
+}
+
+func TestNewSkip(t *testing.T) {
+	t.Parallel()
+
+	// act
+	helperErr := newTestSkipError(1, "something went bad")
+	noSkipErr := newTestSkipError(0, "something went bad")
+	negativeSkipErr := newTestSkipError(-1, "something went bad")
+
+	// assert
+	assertEqual(t, "something went bad", helperErr.Error())
+	assertEqual(t, "github.com/actforgood/xerr_test.TestNewSkip", xerr.Frames(helperErr)[0].Function)
+	assertEqual(t, "github.com/actforgood/xerr_test.newTestSkipError", xerr.Frames(noSkipErr)[0].Function)
+	assertEqual(t, "github.com/actforgood/xerr_test.newTestSkipError", xerr.Frames(negativeSkipErr)[0].Function)
+}
+
+func TestWrapSkip(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	origErr := errors.New("some error")
+
+	// act
+	helperErr := wrapTestSkipError(1, origErr, "wrap")
+	noSkipErr := wrapTestSkipError(0, origErr, "wrap")
+	nilErr := wrapTestSkipError(1, nil, "wrap")
+
+	// assert
+	assertEqual(t, "wrap: some error", helperErr.Error())
+	assertTrue(t, errors.Is(helperErr, origErr))
+	assertEqual(t, "github.com/actforgood/xerr_test.TestWrapSkip", xerr.Frames(helperErr)[0].Function)
+	assertEqual(t, "github.com/actforgood/xerr_test.wrapTestSkipError", xerr.Frames(noSkipErr)[0].Function)
+	assertNil(t, nilErr)
+}
+
+// newTestSkipError is a helper creating errors, that should not appear in stack trace.
+func newTestSkipError(skip int, msg string) error {
+	return xerr.NewSkip(skip, msg)
+}
+
+// wrapTestSkipError is a helper wrapping errors, that should not appear in stack trace.
+func wrapTestSkipError(skip int, err error, msg string) error {
+	return xerr.WrapSkip(skip, err, msg)
+}
+
+func TestStackError_Error_memoized(t *testing.T) {
+	// test is not parallel as testing.AllocsPerRun requires it.
+
+	// arrange
+	subject := xerr.Wrap(xerr.Wrap(io.ErrUnexpectedEOF, "read failed"), "could not load config")
+	expected := "could not load config: read failed: unexpected EOF"
+	assertEqual(t, expected, subject.Error())
+
+	// act
+	allocs := testing.AllocsPerRun(100, func() {
+		_ = subject.Error()
+	})
+
+	// assert
+	assertEqual(t, expected, subject.Error())
+	assertEqual(t, 0.0, allocs)
+}
+
+func TestStackError_Error_notWrapping(t *testing.T) {
+	// test is not parallel as testing.AllocsPerRun requires it.
+
+	// arrange
+	errs := make([]error, 101) // AllocsPerRun calls the function one extra time.
+	for idx := range errs {
+		errs[idx] = xerr.New("something went bad")
+	}
+	idx := 0
+
+	// act
+	allocs := testing.AllocsPerRun(100, func() {
+		_ = errs[idx].Error() // first call on each error.
+		idx++
+	})
+
+	// assert
+	assertEqual(t, "something went bad", errs[0].Error())
+	assertEqual(t, 0.0, allocs)
+}
+
 func BenchmarkNew(b *testing.B) {
 	for n := 0; n < b.N; n++ {
 		err := xerr.New("some error with stack trace")
@@ -425,8 +884,107 @@ func BenchmarkWrap_withStandardError(b *testing.B) {
 func BenchmarkWrap_withStackError(b *testing.B) {
 	origErr := xerr.New("some error with stack trace")
 
+	b.ReportAllocs()
+	b.ResetTimer()
 	for n := 0; n < b.N; n++ {
 		err := xerr.Wrap(origErr, "wrap")
 		_ = fmt.Sprintf("%+v", err)
 	}
 }
+
+func BenchmarkNew_capture(b *testing.B) {
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		_ = xerr.New("some error with stack trace")
+	}
+}
+
+func BenchmarkNew_captureParallel(b *testing.B) {
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_ = xerr.New("some error with stack trace")
+		}
+	})
+}
+
+func BenchmarkWrap_capture(b *testing.B) {
+	origErr := errors.New("some standard error")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		_ = xerr.Wrap(origErr, "wrap")
+	}
+}
+
+func BenchmarkStackError_Format(b *testing.B) {
+	err := xerr.New("some error with stack trace")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		_ = fmt.Sprintf("%+v", err)
+	}
+}
+
+func BenchmarkStackError_Error(b *testing.B) {
+	b.Run("new", func(b *testing.B) {
+		b.ReportAllocs()
+		for n := 0; n < b.N; n++ {
+			_ = xerr.New("some error with stack trace").Error()
+		}
+	})
+	b.Run("wrap", func(b *testing.B) {
+		origErr := errors.New("some standard error")
+
+		b.ReportAllocs()
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			_ = xerr.Wrap(origErr, "wrap").Error()
+		}
+	})
+	b.Run("repeated", func(b *testing.B) {
+		err := xerr.Wrap(xerr.Wrap(errors.New("some standard error"), "wrap"), "wrap again")
+
+		b.ReportAllocs()
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			_ = err.Error()
+		}
+	})
+}
+
+func BenchmarkWrap_deep(b *testing.B) {
+	const depth = 16
+	origErr := xerr.New("some error with stack trace")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		err := origErr
+		for i := 0; i < depth; i++ {
+			err = xerr.Wrap(err, "wrap")
+		}
+	}
+}
+
+func TestStackError_Error_foreignMutableError(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		aggErr  = new(mutableAggErr)
+		subject = xerr.Wrap(xerr.Wrap(aggErr, "read failed"), "could not load config")
+	)
+	aggErr.Append(io.ErrUnexpectedEOF)
+
+	// act & assert
+	assertEqual(t, "could not load config: read failed: 1 errors occurred: unexpected EOF", subject.Error())
+	aggErr.Append(io.ErrShortWrite)
+	assertEqual(
+		t,
+		"could not load config: read failed: 2 errors occurred: unexpected EOF; short write",
+		subject.Error(),
+	)
+}
@@ -0,0 +1,84 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// timelineEvent is a moment in an error's evolution.
+type timelineEvent struct {
+	at  time.Time
+	msg string
+}
+
+// Timeline renders the events an error accumulated over time (its creation,
+// each wrap, the errors stored in a [MultiError]), in chronological order,
+// one per line, prefixed with their offset relative to the first event.
+// Only errors created by this package carry a timestamp, others are not rendered.
+// Returns empty string if there is no event.
+//
+// Note: a [MultiError] does not record when each error was added to it,
+// as that would cost a clock read and a timestamp per stored error on every add,
+// while most errors are added right after they were created anyway.
+// The errors stored in a [MultiError] are rendered at their creation (or last wrap) time.
+// To get a foreign error in the timeline at the moment it is added, wrap it:
+//
+//	mErr.Add(xerr.Wrap(err, ""))
+//
+// Output example:
+//
+//	+0s        connection refused
+//	+1.503ms   could not fetch user
+//	+2.0011ms  could not handle request
+func Timeline(err error) string {
+	var events []timelineEvent
+	walkChain(err, func(e error) bool {
+		if sErr, ok := e.(*stackError); ok && !sErr.createdAt.IsZero() {
+			msg := sErr.msg
+			if msg == "" && sErr.origErr != nil {
+				msg = sErr.origErr.Error()
+			}
+			events = append(events, timelineEvent{at: sErr.createdAt, msg: msg})
+		}
+
+		return true
+	})
+	if len(events) == 0 {
+		return ""
+	}
+
+	// chain is visited from outermost to innermost error,
+	// reverse it, so that equal timestamps still end up in chronological order.
+	for i, j := 0, len(events)-1; i < j; i, j = i+1, j-1 {
+		events[i], events[j] = events[j], events[i]
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].at.Before(events[j].at)
+	})
+
+	offsets := make([]string, len(events))
+	width := 0
+	for idx, ev := range events {
+		offsets[idx] = "+" + ev.at.Sub(events[0].at).String()
+		if len(offsets[idx]) > width {
+			width = len(offsets[idx])
+		}
+	}
+
+	var buf strings.Builder
+	for idx, ev := range events {
+		if idx > 0 {
+			buf.WriteByte('\n')
+		}
+		_, _ = fmt.Fprintf(&buf, "%-*s  %s", width, offsets[idx], ev.msg)
+	}
+
+	return buf.String()
+}
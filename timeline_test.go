@@ -0,0 +1,99 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr_test
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/actforgood/xerr"
+)
+
+func TestTimeline(t *testing.T) {
+	t.Parallel()
+
+	t.Run("wrapped errors", testTimelineWrappedErrors)
+	t.Run("MultiError", testTimelineMultiError)
+	t.Run("MultiError, errors are rendered at creation, not add time", testTimelineMultiErrorAddTime)
+	t.Run("no events", testTimelineNoEvents)
+}
+
+func testTimelineWrappedErrors(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	origErr := errors.New("connection refused")
+	err := xerr.Wrap(origErr, "")
+	time.Sleep(2 * time.Millisecond)
+	err = xerr.Wrap(err, "could not fetch user")
+	time.Sleep(2 * time.Millisecond)
+	err = xerr.Wrapf(err, "could not handle request %d", 1)
+
+	// act
+	result := xerr.Timeline(err)
+
+	// assert
+	lines := strings.Split(result, "\n")
+	if assertEqual(t, 3, len(lines)) {
+		assertTrue(t, regexp.MustCompile(`^\+0s +connection refused$`).MatchString(lines[0]))
+		assertTrue(t, regexp.MustCompile(`^\+[\d.]+ms +could not fetch user$`).MatchString(lines[1]))
+		assertTrue(t, regexp.MustCompile(`^\+[\d.]+ms +could not handle request 1$`).MatchString(lines[2]))
+	}
+}
+
+func testTimelineMultiError(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	mErr := xerr.NewMultiError()
+	first := xerr.New("first")
+	time.Sleep(2 * time.Millisecond)
+	second := xerr.New("second")
+	_ = mErr.Add(second, errors.New("no timestamp"), first)
+
+	// act
+	result := xerr.Timeline(mErr)
+
+	// assert
+	lines := strings.Split(result, "\n")
+	if assertEqual(t, 2, len(lines)) {
+		assertTrue(t, regexp.MustCompile(`^\+0s +first$`).MatchString(lines[0]))
+		assertTrue(t, regexp.MustCompile(`^\+[\d.]+ms +second$`).MatchString(lines[1]))
+	}
+}
+
+func testTimelineMultiErrorAddTime(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	mErr := xerr.NewMultiError()
+	old := xerr.New("old")
+	time.Sleep(2 * time.Millisecond)
+	_ = mErr.Add(xerr.New("fresh"))
+	time.Sleep(2 * time.Millisecond)
+	_ = mErr.Add(old, xerr.Wrap(errors.New("foreign"), ""))
+
+	// act
+	result := xerr.Timeline(mErr)
+
+	// assert
+	lines := strings.Split(result, "\n")
+	if assertEqual(t, 3, len(lines)) {
+		assertTrue(t, regexp.MustCompile(`^\+0s +old$`).MatchString(lines[0]))
+		assertTrue(t, regexp.MustCompile(`^\+[\d.]+ms +fresh$`).MatchString(lines[1]))
+		assertTrue(t, regexp.MustCompile(`^\+[\d.]+ms +foreign$`).MatchString(lines[2]))
+	}
+}
+
+func testTimelineNoEvents(t *testing.T) {
+	t.Parallel()
+
+	assertEqual(t, "", xerr.Timeline(nil))
+	assertEqual(t, "", xerr.Timeline(errors.New("some standard error")))
+}
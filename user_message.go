@@ -0,0 +1,30 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr
+
+// userMessageKey is the annotation key under which an error's user message is stored.
+type userMessageKey struct{}
+
+// WithUserMessage returns an error annotating err with a safe, presentable message,
+// meant for end users, distinct from the internal, technical, one (returned by Error()).
+// If err is nil, WithUserMessage returns nil.
+func WithUserMessage(err error, msg string) error {
+	if err == nil {
+		return nil
+	}
+
+	return withValue(err, userMessageKey{}, msg)
+}
+
+// UserMessage returns the user message of an error, the outermost one found in its chain.
+// Returns empty string if the error does not have one.
+func UserMessage(err error) string {
+	if msg, found := lookupValue(err, userMessageKey{}); found {
+		return msg.(string)
+	}
+
+	return ""
+}
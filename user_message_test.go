@@ -0,0 +1,90 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/actforgood/xerr"
+)
+
+func TestUserMessage(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		subject = xerr.UserMessage
+		stdErr  = errors.New("sql: no rows in result set")
+		tests   = [...]struct {
+			name     string
+			inputErr error
+			expected string
+		}{
+			{
+				name:     "nil error",
+				inputErr: nil,
+				expected: "",
+			},
+			{
+				name:     "error without user message",
+				inputErr: stdErr,
+				expected: "",
+			},
+			{
+				name:     "error with user message",
+				inputErr: xerr.WithUserMessage(stdErr, "The user does not exist."),
+				expected: "The user does not exist.",
+			},
+			{
+				name: "wrapped error with user message",
+				inputErr: fmt.Errorf(
+					"wrap: %w",
+					xerr.Wrap(xerr.WithUserMessage(stdErr, "The user does not exist."), "wrap"),
+				),
+				expected: "The user does not exist.",
+			},
+			{
+				name: "error with multiple user messages, expect outermost",
+				inputErr: xerr.WithUserMessage(
+					xerr.Wrap(xerr.WithUserMessage(stdErr, "The user does not exist."), "wrap"),
+					"The order cannot be placed.",
+				),
+				expected: "The order cannot be placed.",
+			},
+		}
+	)
+
+	for _, testData := range tests {
+		test := testData // capture range variable
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			// act
+			result := subject(test.inputErr)
+
+			// assert
+			assertEqual(t, test.expected, result)
+		})
+	}
+}
+
+func TestWithUserMessage(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	origErr := xerr.New("sql: no rows in result set")
+
+	// act
+	result := xerr.WithUserMessage(origErr, "The user does not exist.")
+
+	// assert
+	assertEqual(t, origErr.Error(), result.Error())
+	assertEqual(t, fmt.Sprintf("%+v", origErr), fmt.Sprintf("%+v", result))
+	assertTrue(t, errors.Is(result, origErr))
+	assertNil(t, xerr.WithUserMessage(nil, "The user does not exist."))
+}
@@ -0,0 +1,72 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr
+
+// FieldViolation is an error describing why a field's value is invalid.
+type FieldViolation struct {
+	// Field is the path of the invalid field, like "user.email".
+	Field string
+	// Description describes the violation, like "must be valid".
+	Description string
+}
+
+// Error returns the error's message.
+// Implements std error interface.
+//
+// The returned value has the form <Field>: <Description>.
+func (fv *FieldViolation) Error() string {
+	return fv.Field + ": " + fv.Description
+}
+
+// Validation collects field violations.
+// Its APIs are concurrent safe.
+//
+// Example:
+//
+//	v := xerr.NewValidation()
+//	if !strings.Contains(user.Email, "@") {
+//		v.Fail("user.email", "must be valid")
+//	}
+//	if user.Age < 18 {
+//		v.Fail("user.age", "must be at least 18")
+//	}
+//	return v.ErrOrNil()
+type Validation struct {
+	mErr *MultiError
+}
+
+// NewValidation instantiates a new Validation object.
+func NewValidation() *Validation {
+	return &Validation{
+		mErr: NewMultiError(),
+	}
+}
+
+// Fail records a violation for given field.
+func (v *Validation) Fail(field, description string) {
+	_ = v.mErr.Add(&FieldViolation{Field: field, Description: description})
+}
+
+// ErrOrNil returns nil if there is no violation,
+// or the single *[FieldViolation] error if there is only one,
+// or a [MultiError] holding all violations otherwise.
+func (v *Validation) ErrOrNil() error {
+	return v.mErr.ErrOrNil()
+}
+
+// FieldViolations returns all the field violations found in an error's chain.
+func FieldViolations(err error) []*FieldViolation {
+	var violations []*FieldViolation
+	walkChain(err, func(e error) bool {
+		if fv, ok := e.(*FieldViolation); ok {
+			violations = append(violations, fv)
+		}
+
+		return true
+	})
+
+	return violations
+}
@@ -0,0 +1,128 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/actforgood/xerr"
+)
+
+func TestValidation(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no violation", testValidationNoViolation)
+	t.Run("one violation", testValidationOneViolation)
+	t.Run("multiple violations", testValidationMultipleViolations)
+	t.Run("JSON", testValidationJSON)
+}
+
+func testValidationNoViolation(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	subject := xerr.NewValidation()
+
+	// act
+	resultErr := subject.ErrOrNil()
+
+	// assert
+	assertNil(t, resultErr)
+	assertNil(t, xerr.FieldViolations(resultErr))
+}
+
+func testValidationOneViolation(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	subject := xerr.NewValidation()
+
+	// act
+	subject.Fail("user.email", "must be valid")
+	resultErr := subject.ErrOrNil()
+
+	// assert
+	if assertNotNil(t, resultErr) {
+		assertEqual(t, "user.email: must be valid", resultErr.Error())
+		var fv *xerr.FieldViolation
+		if assertTrue(t, errors.As(resultErr, &fv)) {
+			assertEqual(t, "user.email", fv.Field)
+			assertEqual(t, "must be valid", fv.Description)
+		}
+	}
+}
+
+func testValidationMultipleViolations(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	subject := xerr.NewValidation()
+
+	// act
+	subject.Fail("user.email", "must be valid")
+	subject.Fail("user.age", "must be at least 18")
+	resultErr := subject.ErrOrNil()
+
+	// assert
+	if assertNotNil(t, resultErr) {
+		assertEqual(t, "user.email: must be valid\nuser.age: must be at least 18", resultErr.Error())
+		assertEqual(
+			t,
+			[]*xerr.FieldViolation{
+				{Field: "user.email", Description: "must be valid"},
+				{Field: "user.age", Description: "must be at least 18"},
+			},
+			xerr.FieldViolations(fmt.Errorf("wrap: %w", resultErr)),
+		)
+	}
+}
+
+func testValidationJSON(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		serializer = xerr.NewSerializer()
+		subject    = xerr.NewValidation()
+		result     struct {
+			Errors []struct {
+				Field   string `json:"field"`
+				Message string `json:"message"`
+			} `json:"errors"`
+		}
+	)
+	subject.Fail("user.email", "must be valid")
+	subject.Fail("user.age", "must be at least 18")
+
+	// act
+	data, err := serializer.Marshal(subject.ErrOrNil())
+
+	// assert
+	assertNil(t, err)
+	assertNil(t, json.Unmarshal(data, &result))
+	if assertEqual(t, 2, len(result.Errors)) {
+		assertEqual(t, "user.email", result.Errors[0].Field)
+		assertEqual(t, "user.email: must be valid", result.Errors[0].Message)
+		assertEqual(t, "user.age", result.Errors[1].Field)
+	}
+
+	// act - decode
+	decodedErr, err := serializer.Unmarshal(data)
+
+	// assert
+	assertNil(t, err)
+	assertEqual(
+		t,
+		[]*xerr.FieldViolation{
+			{Field: "user.email", Description: "must be valid"},
+			{Field: "user.age", Description: "must be at least 18"},
+		},
+		xerr.FieldViolations(decodedErr),
+	)
+}
@@ -0,0 +1,130 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerr
+
+import (
+	"fmt"
+	"io"
+)
+
+// valueError is an error annotated with a key/value pair,
+// in the same spirit as [context.WithValue].
+// It is transparent: message, formatting and unwrapping
+// are delegated to the annotated error.
+type valueError struct {
+	// err is the annotated error.
+	err error
+	// key identifies the kind of annotation.
+	key any
+	// val is the annotation itself.
+	val any
+}
+
+// Error returns the annotated error's message.
+// Implements std error interface.
+func (err *valueError) Error() string {
+	return err.err.Error()
+}
+
+// Format implements [fmt.Formatter].
+// It delegates to the annotated error.
+func (err *valueError) Format(f fmt.State, verb rune) {
+	formatError(f, verb, err.err)
+}
+
+// Unwrap returns the annotated error.
+// It implements [errors.Is] / [errors.As] APIs.
+func (err *valueError) Unwrap() error {
+	return err.err
+}
+
+// withValue annotates err with the given key/value pair.
+func withValue(err error, key, val any) error {
+	return &valueError{
+		err: err,
+		key: key,
+		val: val,
+	}
+}
+
+// lookupValue returns the value associated with key,
+// searching err's chain from the outermost error inwards.
+func lookupValue(err error, key any) (any, bool) {
+	var (
+		val   any
+		found bool
+	)
+	walkChain(err, func(e error) bool {
+		if vErr, ok := e.(*valueError); ok && vErr.key == key {
+			val, found = vErr.val, true
+
+			return false
+		}
+
+		return true
+	})
+
+	return val, found
+}
+
+// walkChain visits err and, recursively, the errors it wraps (depth first).
+// Both single and multi unwrap errors (like [MultiError]) are followed.
+// Visiting stops as soon as fn returns false, in which case false is returned.
+// At most the configured maximum chain depth of errors is followed on each
+// unwrap path, see [SetMaxChainDepth], and a multi unwrap error which
+// (indirectly) wraps itself is not followed again, once reached through itself.
+func walkChain(err error, fn func(error) bool) bool {
+	var pathBuf [chainPathBufSize]any
+
+	return walkChainLimit(err, fn, maxChainDepth, pathBuf[:0])
+}
+
+// walkChainLimit visits err's chain like [walkChain] does,
+// following at most limit errors on each unwrap path.
+func walkChainLimit(err error, fn func(error) bool, limit int, path chainPath) bool {
+	for ; err != nil && limit > 0; limit-- {
+		if !fn(err) {
+			return false
+		}
+
+		var errs []error
+		switch x := err.(type) {
+		case interface{ Unwrap() []error }:
+			errs = x.Unwrap()
+		case interface{ Unwrap() error }:
+			err = x.Unwrap()
+
+			continue
+		default:
+			return true
+		}
+
+		path, ok := path.enter(err, errs)
+		if !ok {
+			return true
+		}
+		for _, e := range errs {
+			if !walkChainLimit(e, fn, limit-1, path) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	return true
+}
+
+// formatError formats err with its own Format() API if applicable,
+// otherwise Error() 's outcome is written.
+func formatError(f fmt.State, verb rune, err error) {
+	if errFmt, ok := err.(fmt.Formatter); ok {
+		errFmt.Format(f, verb)
+
+		return
+	}
+	_, _ = io.WriteString(f, err.Error())
+}
@@ -0,0 +1,8 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+// Package xerrconnect provides interoperability between xerr errors
+// and Connect (connectrpc.com/connect) errors.
+package xerrconnect
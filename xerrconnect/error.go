@@ -0,0 +1,248 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerrconnect
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"connectrpc.com/connect"
+	"github.com/actforgood/xerr"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+// defaultDomain is the default [errdetails.ErrorInfo] domain.
+const defaultDomain = "xerr"
+
+// codeByName maps xerr codes named like gRPC codes to Connect codes.
+var codeByName = map[xerr.Code]connect.Code{
+	"CANCELLED":           connect.CodeCanceled,
+	"UNKNOWN":             connect.CodeUnknown,
+	"INVALID_ARGUMENT":    connect.CodeInvalidArgument,
+	"DEADLINE_EXCEEDED":   connect.CodeDeadlineExceeded,
+	"NOT_FOUND":           connect.CodeNotFound,
+	"ALREADY_EXISTS":      connect.CodeAlreadyExists,
+	"PERMISSION_DENIED":   connect.CodePermissionDenied,
+	"RESOURCE_EXHAUSTED":  connect.CodeResourceExhausted,
+	"FAILED_PRECONDITION": connect.CodeFailedPrecondition,
+	"ABORTED":             connect.CodeAborted,
+	"OUT_OF_RANGE":        connect.CodeOutOfRange,
+	"UNIMPLEMENTED":       connect.CodeUnimplemented,
+	"INTERNAL":            connect.CodeInternal,
+	"UNAVAILABLE":         connect.CodeUnavailable,
+	"DATA_LOSS":           connect.CodeDataLoss,
+	"UNAUTHENTICATED":     connect.CodeUnauthenticated,
+}
+
+// codeByKind maps xerr kinds to Connect codes.
+var codeByKind = map[xerr.Kind]connect.Code{
+	xerr.KindNotFound:        connect.CodeNotFound,
+	xerr.KindInvalid:         connect.CodeInvalidArgument,
+	xerr.KindPermission:      connect.CodePermissionDenied,
+	xerr.KindUnauthenticated: connect.CodeUnauthenticated,
+	xerr.KindConflict:        connect.CodeAlreadyExists,
+	xerr.KindUnavailable:     connect.CodeUnavailable,
+	xerr.KindInternal:        connect.CodeInternal,
+}
+
+// CodeMapper is an alias for a function that maps an xerr error code to a Connect code.
+type CodeMapper func(code xerr.Code) connect.Code
+
+// config holds the conversion configuration.
+type config struct {
+	codeMapper CodeMapper
+	domain     string
+}
+
+// Option defines optional function for configuring a conversion.
+type Option func(*config)
+
+// WithCodeMapper configures the function used to map xerr error codes
+// to Connect codes. By default, an xerr code named like a gRPC code
+// (for example "NOT_FOUND") is mapped to the equivalent Connect code,
+// others to [connect.CodeUnknown].
+func WithCodeMapper(fn CodeMapper) Option {
+	return func(cfg *config) {
+		if fn != nil {
+			cfg.codeMapper = fn
+		}
+	}
+}
+
+// WithDomain configures the domain of the [errdetails.ErrorInfo]
+// detail holding the xerr code and fields. Defaults to "xerr".
+func WithDomain(domain string) Option {
+	return func(cfg *config) {
+		cfg.domain = domain
+	}
+}
+
+// newConfig returns a configuration with given options applied.
+func newConfig(opts []Option) *config {
+	cfg := &config{
+		codeMapper: mapCodeByName,
+		domain:     defaultDomain,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return cfg
+}
+
+// ToError converts an error into a Connect error.
+// The Connect error message is the error's user message (see [xerr.WithUserMessage]),
+// if it has one, as the error's message itself is not meant for clients,
+// otherwise the error's message.
+// The Connect code is, in this order of precedence:
+// the code of a Connect error found in the error's chain,
+// [connect.CodeCanceled] / [connect.CodeDeadlineExceeded] for context errors,
+// the mapped xerr code (see [WithCodeMapper]),
+// the mapped xerr kind, if the xerr code is not mapped (see [xerr.KindOf]),
+// like [xerr.HTTPStatus] does.
+// The xerr code and fields are attached as an [errdetails.ErrorInfo] detail,
+// with the code as reason and the fields as metadata.
+// Returns nil for a nil error.
+func ToError(err error, opts ...Option) *connect.Error {
+	if err == nil {
+		return nil
+	}
+	cfg := newConfig(opts)
+
+	msg := xerr.UserMessage(err)
+	if msg == "" {
+		msg = err.Error()
+	}
+	connectErr := connect.NewError(connectCode(err, cfg), errors.New(msg))
+	if info := errorInfo(err, cfg); info != nil {
+		if detail, detailErr := connect.NewErrorDetail(info); detailErr == nil {
+			connectErr.AddDetail(detail)
+		}
+	}
+
+	return connectErr
+}
+
+// FromError converts a Connect error, found in the given error's chain, into an error.
+// The returned error has the Connect error message, and the xerr code and fields
+// restored from the [errdetails.ErrorInfo] detail, if present, so that
+// [xerr.CodeOf], [xerr.Fields] work identically on it.
+// Note: fields' values are restored as strings.
+// The Connect error is wrapped, so that [connect.CodeOf] works on the returned error.
+// An error without a Connect error in its chain is returned as it is.
+// Returns nil for a nil error.
+//
+// Example:
+//
+//	res, err := client.GetUser(ctx, req)
+//	if err != nil {
+//		err = xerrconnect.FromError(err)
+//		fmt.Println(xerr.CodeOf(err), xerr.Fields(err))
+//	}
+func FromError(err error) error {
+	var connectErr *connect.Error
+	if !errors.As(err, &connectErr) {
+		return err
+	}
+
+	var result error = &remoteError{connectErr: connectErr}
+	for _, detail := range connectErr.Details() {
+		msg, valueErr := detail.Value()
+		if valueErr != nil {
+			continue
+		}
+		if info, ok := msg.(*errdetails.ErrorInfo); ok {
+			if len(info.GetMetadata()) > 0 {
+				fields := make([]xerr.Field, 0, len(info.GetMetadata()))
+				for key, val := range info.GetMetadata() {
+					fields = append(fields, xerr.F(key, val))
+				}
+				sort.Slice(fields, func(i, j int) bool {
+					return fields[i].Key < fields[j].Key
+				})
+				result = xerr.WithFields(result, fields...)
+			}
+			if info.GetReason() != "" {
+				result = xerr.WithCode(result, xerr.Code(info.GetReason()))
+			}
+		}
+	}
+
+	return result
+}
+
+// connectCode returns the Connect code of an error.
+func connectCode(err error, cfg *config) connect.Code {
+	var connectErr *connect.Error
+	if errors.As(err, &connectErr) {
+		return connectErr.Code()
+	}
+	if errors.Is(err, context.Canceled) {
+		return connect.CodeCanceled
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return connect.CodeDeadlineExceeded
+	}
+
+	if code := cfg.codeMapper(xerr.CodeOf(err)); code != connect.CodeUnknown {
+		return code
+	}
+	if kindCode, found := codeByKind[xerr.KindOf(err)]; found {
+		return kindCode
+	}
+
+	return connect.CodeUnknown
+}
+
+// mapCodeByName is the default [CodeMapper], it maps an xerr code named like
+// a gRPC code (for example "NOT_FOUND") to the equivalent Connect code.
+func mapCodeByName(code xerr.Code) connect.Code {
+	if connectCode, found := codeByName[code]; found {
+		return connectCode
+	}
+
+	return connect.CodeUnknown
+}
+
+// errorInfo returns the ErrorInfo detail of an error, if it has code or fields.
+func errorInfo(err error, cfg *config) *errdetails.ErrorInfo {
+	code := xerr.CodeOf(err)
+	fields := xerr.FieldsOf(err)
+	if code == "" && len(fields) == 0 {
+		return nil
+	}
+
+	info := &errdetails.ErrorInfo{
+		Reason: string(code),
+		Domain: cfg.domain,
+	}
+	if len(fields) > 0 {
+		info.Metadata = make(map[string]string, len(fields))
+		for _, field := range fields {
+			info.Metadata[field.Key] = fmt.Sprint(field.Value)
+		}
+	}
+
+	return info
+}
+
+// remoteError is an error decoded from a Connect error.
+type remoteError struct {
+	connectErr *connect.Error
+}
+
+// Error returns the Connect error message.
+// Implements std error interface.
+func (err *remoteError) Error() string {
+	return err.connectErr.Message()
+}
+
+// Unwrap returns the Connect error.
+func (err *remoteError) Unwrap() error {
+	return err.connectErr
+}
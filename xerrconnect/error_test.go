@@ -0,0 +1,163 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerrconnect_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/actforgood/xerr"
+	"github.com/actforgood/xerr/xerrconnect"
+)
+
+func TestToError(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil error", testToErrorNilError)
+	t.Run("code precedence", testToErrorCodePrecedence)
+	t.Run("user message", testToErrorUserMessage)
+}
+
+func testToErrorNilError(t *testing.T) {
+	t.Parallel()
+
+	// act
+	result := xerrconnect.ToError(nil)
+
+	// assert
+	assertNil(t, result)
+}
+
+func testToErrorCodePrecedence(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	tests := [...]struct {
+		name     string
+		inputErr error
+		opts     []xerrconnect.Option
+		expected connect.Code
+	}{
+		{
+			name:     "standard error",
+			inputErr: errors.New("some standard error"),
+			expected: connect.CodeUnknown,
+		},
+		{
+			name:     "xerr code named like a gRPC code",
+			inputErr: xerr.WithCode(errors.New("some error"), "CANCELLED"),
+			expected: connect.CodeCanceled,
+		},
+		{
+			name:     "xerr code with custom mapper",
+			inputErr: xerr.WithCode(errors.New("some error"), "E001"),
+			opts: []xerrconnect.Option{xerrconnect.WithCodeMapper(func(code xerr.Code) connect.Code {
+				if code == "E001" {
+					return connect.CodeFailedPrecondition
+				}
+
+				return connect.CodeUnknown
+			})},
+			expected: connect.CodeFailedPrecondition,
+		},
+		{
+			name:     "mapped xerr code takes precedence over kind",
+			inputErr: xerr.WithCode(xerr.Conflict("user %d exists", 42), "NOT_FOUND"),
+			expected: connect.CodeNotFound,
+		},
+		{
+			name:     "xerr kind, with code not mapped",
+			inputErr: xerr.WithKind(xerr.NewCode("user_exists", "user exists"), xerr.KindConflict),
+			expected: connect.CodeAlreadyExists,
+		},
+		{
+			name:     "xerr kind",
+			inputErr: xerr.Wrap(xerr.Conflict("user %d exists", 42), "wrap"),
+			expected: connect.CodeAlreadyExists,
+		},
+		{
+			name:     "connect error",
+			inputErr: xerr.Wrap(connect.NewError(connect.CodeResourceExhausted, errors.New("quota")), "wrap"),
+			expected: connect.CodeResourceExhausted,
+		},
+		{
+			name:     "context deadline exceeded",
+			inputErr: fmt.Errorf("wrap: %w", context.DeadlineExceeded),
+			expected: connect.CodeDeadlineExceeded,
+		},
+	}
+
+	for _, testData := range tests {
+		test := testData // capture range variable
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			// act
+			result := xerrconnect.ToError(test.inputErr, test.opts...)
+
+			// assert
+			assertEqual(t, test.expected, result.Code())
+		})
+	}
+}
+
+func testToErrorUserMessage(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	inputErr := xerr.WithUserMessage(xerr.NewCode("NOT_FOUND", "sql: no rows in result set"), "User not found.")
+
+	// act
+	result := xerrconnect.ToError(inputErr)
+
+	// assert
+	assertEqual(t, connect.CodeNotFound, result.Code())
+	assertEqual(t, "User not found.", result.Message())
+}
+
+func TestFromError(t *testing.T) {
+	t.Parallel()
+
+	t.Run("round trip", testFromErrorRoundTrip)
+	t.Run("not a connect error", testFromErrorNotConnectError)
+}
+
+func testFromErrorRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	origErr := xerr.WithFields(
+		xerr.Wrap(xerr.NewCode("NOT_FOUND", "user not found"), "could not get user"),
+		xerr.F("user_id", 42),
+		xerr.F("tenant", "acme"),
+	)
+	connectErr := xerrconnect.ToError(origErr, xerrconnect.WithDomain("example.com"))
+
+	// act
+	resultErr := xerrconnect.FromError(fmt.Errorf("call: %w", connectErr))
+
+	// assert
+	if assertNotNil(t, resultErr) {
+		assertEqual(t, origErr.Error(), resultErr.Error())
+		assertEqual(t, xerr.Code("NOT_FOUND"), xerr.CodeOf(resultErr))
+		assertEqual(t, map[string]any{"user_id": "42", "tenant": "acme"}, xerr.Fields(resultErr))
+		assertEqual(t, connect.CodeNotFound, connect.CodeOf(resultErr))
+	}
+}
+
+func testFromErrorNotConnectError(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	origErr := errors.New("some standard error")
+
+	// act & assert
+	assertEqual(t, origErr, xerrconnect.FromError(origErr))
+	assertNil(t, xerrconnect.FromError(nil))
+}
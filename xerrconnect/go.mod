@@ -0,0 +1,11 @@
+module github.com/actforgood/xerr/xerrconnect
+
+go 1.21
+
+require (
+	connectrpc.com/connect v1.16.2
+	github.com/actforgood/xerr v1.2.0
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20240318140521-94a12d6c2237
+)
+
+require google.golang.org/protobuf v1.33.0 // indirect
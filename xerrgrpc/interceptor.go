@@ -0,0 +1,155 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerrgrpc
+
+import (
+	"context"
+
+	"github.com/actforgood/xerr"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// WithReporter configures the reporter errors returned by handlers (or recovered from
+// their panics) are reported to, by server interceptors, before being converted
+// into gRPC statuses. It can be used to log errors with their full stack trace,
+// for example with [xerr.NewWriterReporter].
+// By default, errors are not reported.
+func WithReporter(reporter xerr.Reporter) Option {
+	return func(cfg *config) {
+		cfg.reporter = reporter
+	}
+}
+
+// UnaryServerInterceptor returns a server interceptor which recovers handlers' panics
+// into errors with stack trace (see [xerr.Recover]), of [xerr.KindInternal] kind,
+// reports the errors (see [WithReporter]), and converts them into gRPC statuses (see [ToStatus]).
+// The statuses have no stack trace attached, unless configured so with [WithStack],
+// so that panics' (and errors') internals do not reach the clients.
+//
+// Example:
+//
+//	srv := grpc.NewServer(
+//		grpc.ChainUnaryInterceptor(xerrgrpc.UnaryServerInterceptor(
+//			xerrgrpc.WithReporter(xerr.NewWriterReporter(os.Stderr)),
+//		)),
+//	)
+func UnaryServerInterceptor(opts ...Option) grpc.UnaryServerInterceptor {
+	cfg := newConfig(opts)
+
+	return func(
+		ctx context.Context,
+		req any,
+		_ *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (resp any, err error) {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				resp, err = nil, cfg.statusErr(xerr.WithKind(xerr.Recover(recovered), xerr.KindInternal))
+			}
+		}()
+
+		if resp, err = handler(ctx, req); err != nil {
+			err = cfg.statusErr(err)
+		}
+
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor returns a server interceptor which recovers stream handlers' panics
+// and converts their errors, like [UnaryServerInterceptor] does.
+func StreamServerInterceptor(opts ...Option) grpc.StreamServerInterceptor {
+	cfg := newConfig(opts)
+
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				err = cfg.statusErr(xerr.WithKind(xerr.Recover(recovered), xerr.KindInternal))
+			}
+		}()
+
+		if err = handler(srv, ss); err != nil {
+			err = cfg.statusErr(err)
+		}
+
+		return err
+	}
+}
+
+// UnaryClientInterceptor returns a client interceptor which converts the gRPC statuses
+// received into errors (see [FromStatus]), so that their xerr code, fields, backoff hint
+// and user message can be inspected with xerr APIs.
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply any,
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		return fromError(invoker(ctx, method, req, reply, cc, opts...))
+	}
+}
+
+// StreamClientInterceptor returns a client interceptor which converts the gRPC statuses
+// received on streams into errors, like [UnaryClientInterceptor] does.
+func StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(
+		ctx context.Context,
+		desc *grpc.StreamDesc,
+		cc *grpc.ClientConn,
+		method string,
+		streamer grpc.Streamer,
+		opts ...grpc.CallOption,
+	) (grpc.ClientStream, error) {
+		cs, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			return nil, fromError(err)
+		}
+
+		return &clientStream{ClientStream: cs}, nil
+	}
+}
+
+// statusErr reports the error and converts it into a gRPC status error.
+func (cfg *config) statusErr(err error) error {
+	if cfg.reporter != nil {
+		cfg.reporter.Report(err)
+	}
+
+	return toStatus(err, cfg).Err()
+}
+
+// fromError converts a gRPC status error into an error, see [FromStatus].
+// Other errors (like [io.EOF]) are returned as they are.
+func fromError(err error) error {
+	if err == nil {
+		return nil
+	}
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+
+	return FromStatus(st)
+}
+
+// clientStream is a [grpc.ClientStream] converting the gRPC statuses received into errors.
+type clientStream struct {
+	grpc.ClientStream
+}
+
+// SendMsg sends a message on the stream.
+func (cs *clientStream) SendMsg(m any) error {
+	return fromError(cs.ClientStream.SendMsg(m))
+}
+
+// RecvMsg receives a message from the stream.
+func (cs *clientStream) RecvMsg(m any) error {
+	return fromError(cs.ClientStream.RecvMsg(m))
+}
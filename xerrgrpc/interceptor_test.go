@@ -0,0 +1,244 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerrgrpc_test
+
+import (
+	"context"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/actforgood/xerr"
+	"github.com/actforgood/xerr/xerrgrpc"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// reporterMock is a concurrent safe xerr.Reporter mock.
+type reporterMock struct {
+	mu       sync.Mutex
+	reported []error
+}
+
+func (m *reporterMock) Report(err error) {
+	m.mu.Lock()
+	m.reported = append(m.reported, err)
+	m.mu.Unlock()
+}
+
+func (m *reporterMock) errors() []error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.reported
+}
+
+// clientStreamMock is a grpc.ClientStream mock.
+type clientStreamMock struct {
+	grpc.ClientStream
+	recvErr error
+}
+
+func (m *clientStreamMock) SendMsg(any) error {
+	return nil
+}
+
+func (m *clientStreamMock) RecvMsg(any) error {
+	return m.recvErr
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	t.Parallel()
+
+	t.Run("error is reported and converted", testUnaryServerInterceptorError)
+	t.Run("panic is recovered", testUnaryServerInterceptorPanic)
+	t.Run("panic is recovered, with stack", testUnaryServerInterceptorPanicWithStack)
+	t.Run("no error", testUnaryServerInterceptorNoError)
+}
+
+func testUnaryServerInterceptorError(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		reporter = new(reporterMock)
+		subject  = xerrgrpc.UnaryServerInterceptor(xerrgrpc.WithReporter(reporter))
+		origErr  = xerr.NewCode("NOT_FOUND", "user not found")
+	)
+
+	// act
+	resp, err := subject(context.Background(), "req", &grpc.UnaryServerInfo{}, func(context.Context, any) (any, error) {
+		return nil, origErr
+	})
+
+	// assert
+	assertNil(t, resp)
+	st, ok := status.FromError(err)
+	if assertTrue(t, ok) {
+		assertEqual(t, codes.NotFound, st.Code())
+		assertEqual(t, "user not found", st.Message())
+	}
+	if reported := reporter.errors(); assertEqual(t, 1, len(reported)) {
+		assertEqual(t, origErr, reported[0])
+	}
+}
+
+func testUnaryServerInterceptorPanic(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		reporter = new(reporterMock)
+		subject  = xerrgrpc.UnaryServerInterceptor(xerrgrpc.WithReporter(reporter))
+	)
+
+	// act
+	resp, err := subject(context.Background(), "req", &grpc.UnaryServerInfo{}, func(context.Context, any) (any, error) {
+		panic("nil map")
+	})
+
+	// assert
+	assertNil(t, resp)
+	st, ok := status.FromError(err)
+	if assertTrue(t, ok) {
+		assertEqual(t, codes.Internal, st.Code())
+		assertEqual(t, "panic: nil map", st.Message())
+		for _, detail := range st.Details() {
+			_, isDebugInfo := detail.(*errdetails.DebugInfo)
+			assertTrue(t, !isDebugInfo)
+		}
+	}
+	if reported := reporter.errors(); assertEqual(t, 1, len(reported)) {
+		assertTrue(t, len(xerr.Frames(reported[0])) > 0)
+	}
+}
+
+func testUnaryServerInterceptorPanicWithStack(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	subject := xerrgrpc.UnaryServerInterceptor(xerrgrpc.WithStack(true))
+
+	// act
+	_, err := subject(context.Background(), "req", &grpc.UnaryServerInfo{}, func(context.Context, any) (any, error) {
+		panic("nil map")
+	})
+
+	// assert
+	st, ok := status.FromError(err)
+	if assertTrue(t, ok) && assertEqual(t, 1, len(st.Details())) {
+		debugInfo, _ := st.Details()[0].(*errdetails.DebugInfo)
+		if assertNotNil(t, debugInfo) {
+			assertTrue(t, len(debugInfo.GetStackEntries()) > 0)
+		}
+	}
+}
+
+func testUnaryServerInterceptorNoError(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	subject := xerrgrpc.UnaryServerInterceptor()
+
+	// act
+	resp, err := subject(context.Background(), "req", &grpc.UnaryServerInfo{}, func(_ context.Context, req any) (any, error) {
+		return req.(string) + "-resp", nil
+	})
+
+	// assert
+	assertNil(t, err)
+	assertEqual(t, "req-resp", resp)
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		reporter = new(reporterMock)
+		subject  = xerrgrpc.StreamServerInterceptor(xerrgrpc.WithReporter(reporter))
+	)
+
+	// act
+	errPanic := subject(nil, nil, &grpc.StreamServerInfo{}, func(any, grpc.ServerStream) error {
+		panic("nil map")
+	})
+	errReturned := subject(nil, nil, &grpc.StreamServerInfo{}, func(any, grpc.ServerStream) error {
+		return xerr.Unavailable("db down")
+	})
+	errNil := subject(nil, nil, &grpc.StreamServerInfo{}, func(any, grpc.ServerStream) error {
+		return nil
+	})
+
+	// assert
+	assertEqual(t, codes.Internal, status.Code(errPanic))
+	assertEqual(t, 0, len(status.Convert(errPanic).Details()))
+	assertEqual(t, codes.Unavailable, status.Code(errReturned))
+	assertNil(t, errNil)
+	assertEqual(t, 2, len(reporter.errors()))
+}
+
+func TestUnaryClientInterceptor(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		subject = xerrgrpc.UnaryClientInterceptor()
+		st      = xerrgrpc.ToStatus(xerr.WithFields(xerr.NewCode("NOT_FOUND", "user not found"), xerr.F("user_id", 42)))
+	)
+
+	// act
+	err := subject(
+		context.Background(), "/users.Users/Get", "req", nil, nil,
+		func(context.Context, string, any, any, *grpc.ClientConn, ...grpc.CallOption) error {
+			return st.Err()
+		},
+	)
+	errNil := subject(
+		context.Background(), "/users.Users/Get", "req", nil, nil,
+		func(context.Context, string, any, any, *grpc.ClientConn, ...grpc.CallOption) error {
+			return nil
+		},
+	)
+
+	// assert
+	assertEqual(t, xerr.Code("NOT_FOUND"), xerr.CodeOf(err))
+	assertEqual(t, map[string]any{"user_id": "42"}, xerr.Fields(err))
+	assertEqual(t, codes.NotFound, status.Code(err))
+	assertNil(t, errNil)
+}
+
+func TestStreamClientInterceptor(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		subject = xerrgrpc.StreamClientInterceptor()
+		st      = xerrgrpc.ToStatus(xerr.NewCode("UNAVAILABLE", "db down"))
+		mock    = &clientStreamMock{recvErr: st.Err()}
+	)
+
+	// act
+	cs, err := subject(
+		context.Background(), &grpc.StreamDesc{}, nil, "/users.Users/List",
+		func(context.Context, *grpc.StreamDesc, *grpc.ClientConn, string, ...grpc.CallOption) (grpc.ClientStream, error) {
+			return mock, nil
+		},
+	)
+
+	// assert
+	assertNil(t, err)
+	if assertNotNil(t, cs) {
+		assertNil(t, cs.SendMsg("req"))
+		recvErr := cs.RecvMsg(nil)
+		assertEqual(t, xerr.Code("UNAVAILABLE"), xerr.CodeOf(recvErr))
+		assertEqual(t, codes.Unavailable, status.Code(recvErr))
+		mock.recvErr = io.EOF
+		assertEqual(t, io.EOF, cs.RecvMsg(nil))
+	}
+}
@@ -0,0 +1,321 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerrgrpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+
+	"github.com/actforgood/xerr"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// defaultDomain is the default [errdetails.ErrorInfo] domain.
+const defaultDomain = "xerr"
+
+// defaultLocale is the default [errdetails.LocalizedMessage] locale.
+const defaultLocale = "en-US"
+
+// codeByKind maps xerr kinds to gRPC codes.
+var codeByKind = map[xerr.Kind]codes.Code{
+	xerr.KindNotFound:        codes.NotFound,
+	xerr.KindInvalid:         codes.InvalidArgument,
+	xerr.KindPermission:      codes.PermissionDenied,
+	xerr.KindUnauthenticated: codes.Unauthenticated,
+	xerr.KindConflict:        codes.AlreadyExists,
+	xerr.KindUnavailable:     codes.Unavailable,
+	xerr.KindInternal:        codes.Internal,
+}
+
+// CodeMapper is an alias for a function that maps an xerr error code to a gRPC code.
+type CodeMapper func(code xerr.Code) codes.Code
+
+// config holds the conversion configuration.
+type config struct {
+	codeMapper   CodeMapper
+	includeStack bool
+	domain       string
+	locale       string
+	reporter     xerr.Reporter
+}
+
+// Option defines optional function for configuring a conversion.
+type Option func(*config)
+
+// WithCodeMapper configures the function used to map xerr error codes
+// to gRPC codes. By default, an xerr code named like a gRPC code
+// (for example "NOT_FOUND") is mapped to that gRPC code, others to [codes.Unknown].
+func WithCodeMapper(fn CodeMapper) Option {
+	return func(cfg *config) {
+		if fn != nil {
+			cfg.codeMapper = fn
+		}
+	}
+}
+
+// WithStack configures whether the error's stack trace is attached
+// to the status as [errdetails.DebugInfo]. By default, it is not, as the stack trace
+// (and the error's message) reveal internals to the clients, enable it only
+// for trusted (internal) clients.
+func WithStack(include bool) Option {
+	return func(cfg *config) {
+		cfg.includeStack = include
+	}
+}
+
+// WithDomain configures the domain of the [errdetails.ErrorInfo]
+// detail holding the xerr code and fields. Defaults to "xerr".
+func WithDomain(domain string) Option {
+	return func(cfg *config) {
+		cfg.domain = domain
+	}
+}
+
+// WithLocale configures the locale of the [errdetails.LocalizedMessage]
+// detail holding the error's user message. Defaults to "en-US".
+func WithLocale(locale string) Option {
+	return func(cfg *config) {
+		cfg.locale = locale
+	}
+}
+
+// newConfig returns a configuration with given options applied.
+func newConfig(opts []Option) *config {
+	cfg := &config{
+		codeMapper: codeByName,
+		domain:     defaultDomain,
+		locale:     defaultLocale,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return cfg
+}
+
+// ToStatus converts an error into a gRPC status.
+// The status message is the error's user message (see [xerr.WithUserMessage]),
+// if it has one, as the error's message itself is not meant for clients,
+// otherwise the error's message.
+// The status code is, in this order of precedence:
+// the code of a gRPC status error found in the error's chain,
+// [codes.Canceled] / [codes.DeadlineExceeded] for context errors,
+// the mapped xerr code (see [WithCodeMapper]),
+// the mapped xerr kind, if the xerr code is not mapped (see [xerr.KindOf]),
+// like [xerr.HTTPStatus] does.
+// The status has the following details attached:
+//   - [errdetails.ErrorInfo], holding the xerr code as reason and the fields as metadata;
+//   - [errdetails.DebugInfo], holding the stack trace and the error's message, if it has no
+//     user message, only if configured so (see [WithStack]);
+//   - [errdetails.LocalizedMessage], holding the user message, if any (see [WithLocale]);
+//   - [errdetails.RetryInfo], holding the backoff hint (see [xerr.WithRetryAfter]).
+//
+// Returns nil (which is an OK status) for a nil error.
+func ToStatus(err error, opts ...Option) *status.Status {
+	if err == nil {
+		return nil
+	}
+
+	return toStatus(err, newConfig(opts))
+}
+
+// toStatus converts a non-nil error into a gRPC status, with given configuration.
+func toStatus(err error, cfg *config) *status.Status {
+	userMsg := xerr.UserMessage(err)
+	msg := userMsg
+	if msg == "" {
+		msg = err.Error()
+	}
+	st := status.New(grpcCode(err, cfg), msg)
+	details := make([]protoadapt.MessageV1, 0, 4)
+	if info := errorInfo(err, cfg); info != nil {
+		details = append(details, info)
+	}
+	if cfg.includeStack {
+		if frames := xerr.Frames(err); len(frames) > 0 {
+			debugInfo := &errdetails.DebugInfo{StackEntries: make([]string, len(frames))}
+			if userMsg == "" { // the error's message is not meant for clients otherwise.
+				debugInfo.Detail = err.Error()
+			}
+			for idx, frame := range frames {
+				debugInfo.StackEntries[idx] = frame.Function + "\n\t" + frame.File + ":" + strconv.Itoa(frame.Line)
+			}
+			details = append(details, debugInfo)
+		}
+	}
+	if d, found := xerr.RetryAfter(err); found {
+		details = append(details, &errdetails.RetryInfo{RetryDelay: durationpb.New(d)})
+	}
+	if userMsg != "" {
+		details = append(details, &errdetails.LocalizedMessage{Locale: cfg.locale, Message: userMsg})
+	}
+	if len(details) == 0 {
+		return st
+	}
+
+	if stWithDetails, detailsErr := st.WithDetails(details...); detailsErr == nil {
+		return stWithDetails
+	}
+
+	return st
+}
+
+// FromStatus converts a gRPC status into an error.
+// The returned error has the status message, and the xerr code, fields,
+// backoff hint and user message restored from status details, if present,
+// so that [xerr.CodeOf], [xerr.Fields], [xerr.RetryAfter], [xerr.UserMessage]
+// work identically on it.
+// Note: fields' values are restored as strings.
+// Its extended format (%+v) includes the remote stack trace.
+// The original status can be retrieved with [status.FromError] / [status.Code].
+// Returns nil for a nil or OK status.
+func FromStatus(st *status.Status) error {
+	if st == nil || st.Code() == codes.OK {
+		return nil
+	}
+
+	sErr := &statusError{st: st}
+	var err error = sErr
+	for _, detail := range st.Details() {
+		switch d := detail.(type) {
+		case *errdetails.ErrorInfo:
+			if len(d.GetMetadata()) > 0 {
+				fields := make([]xerr.Field, 0, len(d.GetMetadata()))
+				for key, val := range d.GetMetadata() {
+					fields = append(fields, xerr.F(key, val))
+				}
+				sortFields(fields)
+				err = xerr.WithFields(err, fields...)
+			}
+			if d.GetReason() != "" {
+				err = xerr.WithCode(err, xerr.Code(d.GetReason()))
+			}
+		case *errdetails.DebugInfo:
+			sErr.remoteStack = d.GetStackEntries()
+		case *errdetails.RetryInfo:
+			if d.GetRetryDelay() != nil {
+				err = xerr.WithRetryAfter(err, d.GetRetryDelay().AsDuration())
+			}
+		case *errdetails.LocalizedMessage:
+			if d.GetMessage() != "" {
+				err = xerr.WithUserMessage(err, d.GetMessage())
+			}
+		}
+	}
+
+	return err
+}
+
+// grpcCode returns the gRPC code of an error.
+func grpcCode(err error, cfg *config) codes.Code {
+	var grpcErr interface{ GRPCStatus() *status.Status }
+	if errors.As(err, &grpcErr) {
+		if code := grpcErr.GRPCStatus().Code(); code != codes.OK {
+			return code
+		}
+	}
+	if errors.Is(err, context.Canceled) {
+		return codes.Canceled
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return codes.DeadlineExceeded
+	}
+
+	if code := cfg.codeMapper(xerr.CodeOf(err)); code != codes.Unknown {
+		return code
+	}
+	if kindCode, found := codeByKind[xerr.KindOf(err)]; found {
+		return kindCode
+	}
+
+	return codes.Unknown
+}
+
+// codeByName is the default [CodeMapper], it maps an xerr code named like
+// a gRPC code (for example "NOT_FOUND") to that gRPC code.
+func codeByName(code xerr.Code) codes.Code {
+	var grpcCode codes.Code
+	if code != "" && grpcCode.UnmarshalJSON([]byte(strconv.Quote(string(code)))) == nil {
+		return grpcCode
+	}
+
+	return codes.Unknown
+}
+
+// errorInfo returns the ErrorInfo detail of an error, if it has code or fields.
+func errorInfo(err error, cfg *config) *errdetails.ErrorInfo {
+	code := xerr.CodeOf(err)
+	fields := xerr.FieldsOf(err)
+	if code == "" && len(fields) == 0 {
+		return nil
+	}
+
+	info := &errdetails.ErrorInfo{
+		Reason: string(code),
+		Domain: cfg.domain,
+	}
+	if len(fields) > 0 {
+		info.Metadata = make(map[string]string, len(fields))
+		for _, field := range fields {
+			info.Metadata[field.Key] = fmt.Sprint(field.Value)
+		}
+	}
+
+	return info
+}
+
+// sortFields sorts fields by key.
+func sortFields(fields []xerr.Field) {
+	sort.Slice(fields, func(i, j int) bool {
+		return fields[i].Key < fields[j].Key
+	})
+}
+
+// statusError is an error decoded from a gRPC status.
+type statusError struct {
+	st          *status.Status
+	remoteStack []string
+}
+
+// Error returns the status message.
+// Implements std error interface.
+func (err *statusError) Error() string {
+	return err.st.Message()
+}
+
+// GRPCStatus returns the status the error was decoded from.
+func (err *statusError) GRPCStatus() *status.Status {
+	return err.st
+}
+
+// Format implements [fmt.Formatter].
+// The extended format (%+v) includes the remote stack trace.
+func (err *statusError) Format(f fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if f.Flag('+') {
+			_, _ = io.WriteString(f, err.st.Message())
+			for _, entry := range err.remoteStack {
+				_, _ = io.WriteString(f, "\n")
+				_, _ = io.WriteString(f, entry)
+			}
+
+			return
+		}
+
+		fallthrough
+	case 's':
+		_, _ = io.WriteString(f, err.st.Message())
+	}
+}
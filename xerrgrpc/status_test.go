@@ -0,0 +1,305 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerrgrpc_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/actforgood/xerr"
+	"github.com/actforgood/xerr/xerrgrpc"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestToStatus(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil error", testToStatusNilError)
+	t.Run("code, fields, stack, retry info", testToStatusDetails)
+	t.Run("status code precedence", testToStatusCodePrecedence)
+	t.Run("without stack", testToStatusWithoutStack)
+	t.Run("user message", testToStatusUserMessage)
+	t.Run("user message, with stack", testToStatusUserMessageWithStack)
+}
+
+func testToStatusNilError(t *testing.T) {
+	t.Parallel()
+
+	// act
+	result := xerrgrpc.ToStatus(nil)
+
+	// assert
+	assertNil(t, result)
+	assertEqual(t, codes.OK, result.Code())
+}
+
+func testToStatusDetails(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	inputErr := xerr.WithRetryAfter(
+		xerr.WithFields(
+			xerr.Wrap(xerr.NewCode("NOT_FOUND", "user not found"), "could not get user"),
+			xerr.F("user_id", 42),
+		),
+		3*time.Second,
+	)
+
+	// act
+	result := xerrgrpc.ToStatus(inputErr, xerrgrpc.WithDomain("example.com"), xerrgrpc.WithStack(true))
+
+	// assert
+	assertEqual(t, codes.NotFound, result.Code())
+	assertEqual(t, "could not get user: user not found", result.Message())
+	details := result.Details()
+	if assertEqual(t, 3, len(details)) {
+		info, _ := details[0].(*errdetails.ErrorInfo)
+		if assertNotNil(t, info) {
+			assertEqual(t, "NOT_FOUND", info.GetReason())
+			assertEqual(t, "example.com", info.GetDomain())
+			assertEqual(t, map[string]string{"user_id": "42"}, info.GetMetadata())
+		}
+		debugInfo, _ := details[1].(*errdetails.DebugInfo)
+		if assertNotNil(t, debugInfo) && assertTrue(t, len(debugInfo.GetStackEntries()) > 1) {
+			assertEqual(t, "could not get user: user not found", debugInfo.GetDetail())
+			matched, _ := regexp.MatchString(
+				`^github\.com/actforgood/xerr/xerrgrpc_test\.testToStatusDetails\n\t.+status_test\.go:\d+$`,
+				debugInfo.GetStackEntries()[0],
+			)
+			assertTrue(t, matched)
+		}
+		retryInfo, _ := details[2].(*errdetails.RetryInfo)
+		if assertNotNil(t, retryInfo) {
+			assertEqual(t, 3*time.Second, retryInfo.GetRetryDelay().AsDuration())
+		}
+	}
+}
+
+func testToStatusCodePrecedence(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	tests := [...]struct {
+		name     string
+		inputErr error
+		opts     []xerrgrpc.Option
+		expected codes.Code
+	}{
+		{
+			name:     "standard error",
+			inputErr: errors.New("some standard error"),
+			expected: codes.Unknown,
+		},
+		{
+			name:     "xerr code not named like a gRPC code",
+			inputErr: xerr.WithCode(errors.New("some error"), "E001"),
+			expected: codes.Unknown,
+		},
+		{
+			name:     "xerr code with custom mapper",
+			inputErr: xerr.WithCode(errors.New("some error"), "E001"),
+			opts: []xerrgrpc.Option{xerrgrpc.WithCodeMapper(func(code xerr.Code) codes.Code {
+				if code == "E001" {
+					return codes.InvalidArgument
+				}
+
+				return codes.Internal
+			})},
+			expected: codes.InvalidArgument,
+		},
+		{
+			name:     "xerr kind",
+			inputErr: xerr.Wrap(xerr.Permission("user %d cannot access", 42), "wrap"),
+			expected: codes.PermissionDenied,
+		},
+		{
+			name:     "mapped xerr code takes precedence over kind",
+			inputErr: xerr.WithCode(xerr.Unavailable("db down"), "NOT_FOUND"),
+			expected: codes.NotFound,
+		},
+		{
+			name:     "xerr kind, with code not mapped",
+			inputErr: xerr.WithKind(xerr.NewCode("user_not_found", "user not found"), xerr.KindNotFound),
+			expected: codes.NotFound,
+		},
+		{
+			name:     "context canceled",
+			inputErr: xerr.WithCode(xerr.Wrap(context.Canceled, "wrap"), "NOT_FOUND"),
+			expected: codes.Canceled,
+		},
+		{
+			name:     "context deadline exceeded",
+			inputErr: fmt.Errorf("wrap: %w", context.DeadlineExceeded),
+			expected: codes.DeadlineExceeded,
+		},
+		{
+			name:     "gRPC status error",
+			inputErr: xerr.Wrap(status.Error(codes.PermissionDenied, "denied"), "wrap"),
+			expected: codes.PermissionDenied,
+		},
+	}
+
+	for _, testData := range tests {
+		test := testData // capture range variable
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			// act
+			result := xerrgrpc.ToStatus(test.inputErr, test.opts...)
+
+			// assert
+			assertEqual(t, test.expected, result.Code())
+		})
+	}
+}
+
+func testToStatusWithoutStack(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	inputErr := xerr.New("something went bad")
+
+	// act
+	resultDefault := xerrgrpc.ToStatus(inputErr)
+	resultDisabled := xerrgrpc.ToStatus(inputErr, xerrgrpc.WithStack(false))
+
+	// assert
+	assertEqual(t, codes.Unknown, resultDefault.Code())
+	assertEqual(t, 0, len(resultDefault.Details()))
+	assertEqual(t, codes.Unknown, resultDisabled.Code())
+	assertEqual(t, 0, len(resultDisabled.Details()))
+}
+
+func testToStatusUserMessage(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	inputErr := xerr.WithUserMessage(
+		xerr.NewCode("NOT_FOUND", "sql: no rows in result set"),
+		"The user does not exist.",
+	)
+
+	// act
+	result := xerrgrpc.ToStatus(inputErr, xerrgrpc.WithStack(false), xerrgrpc.WithLocale("en-GB"))
+	resultErr := xerrgrpc.FromStatus(result)
+
+	// assert
+	assertEqual(t, codes.NotFound, result.Code())
+	assertEqual(t, "The user does not exist.", result.Message())
+	details := result.Details()
+	if assertEqual(t, 2, len(details)) {
+		localizedMsg, _ := details[1].(*errdetails.LocalizedMessage)
+		if assertNotNil(t, localizedMsg) {
+			assertEqual(t, "en-GB", localizedMsg.GetLocale())
+			assertEqual(t, "The user does not exist.", localizedMsg.GetMessage())
+		}
+	}
+	assertEqual(t, "The user does not exist.", xerr.UserMessage(resultErr))
+}
+
+func testToStatusUserMessageWithStack(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	inputErr := xerr.WithUserMessage(
+		xerr.NewCode("NOT_FOUND", "sql: no rows in result set"),
+		"The user does not exist.",
+	)
+
+	// act
+	result := xerrgrpc.ToStatus(inputErr, xerrgrpc.WithStack(true))
+
+	// assert
+	assertEqual(t, "The user does not exist.", result.Message())
+	details := result.Details()
+	if assertEqual(t, 3, len(details)) {
+		debugInfo, _ := details[1].(*errdetails.DebugInfo)
+		if assertNotNil(t, debugInfo) {
+			assertTrue(t, len(debugInfo.GetStackEntries()) > 0)
+			assertEqual(t, "", debugInfo.GetDetail())
+		}
+	}
+	for _, detail := range details {
+		assertTrue(t, !strings.Contains(fmt.Sprint(detail), "sql: no rows in result set"))
+	}
+}
+
+func TestFromStatus(t *testing.T) {
+	t.Parallel()
+
+	t.Run("round trip", testFromStatusRoundTrip)
+	t.Run("nil or OK status", testFromStatusNilOrOK)
+	t.Run("status without details", testFromStatusWithoutDetails)
+}
+
+func testFromStatusRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	origErr := xerr.WithRetryAfter(
+		xerr.WithFields(
+			xerr.Wrap(xerr.NewCode("NOT_FOUND", "user not found"), "could not get user"),
+			xerr.F("user_id", 42),
+			xerr.F("tenant", "acme"),
+		),
+		time.Minute,
+	)
+	st := xerrgrpc.ToStatus(origErr, xerrgrpc.WithStack(true))
+
+	// act
+	resultErr := xerrgrpc.FromStatus(st)
+
+	// assert
+	if assertNotNil(t, resultErr) {
+		assertEqual(t, origErr.Error(), resultErr.Error())
+		assertEqual(t, xerr.Code("NOT_FOUND"), xerr.CodeOf(resultErr))
+		assertEqual(t, map[string]any{"user_id": "42", "tenant": "acme"}, xerr.Fields(resultErr))
+		retryAfter, found := xerr.RetryAfter(resultErr)
+		assertTrue(t, found)
+		assertEqual(t, time.Minute, retryAfter)
+		assertEqual(t, codes.NotFound, status.Code(resultErr))
+		assertEqual(t, st, status.Convert(resultErr))
+		assertEqual(t, origErr.Error(), fmt.Sprintf("%v", resultErr))
+		matched, _ := regexp.MatchString(
+			`^could not get user: user not found\ngithub\.com/actforgood/xerr/xerrgrpc_test\.testFromStatusRoundTrip\n\t.+status_test\.go:\d+\n`,
+			fmt.Sprintf("%+v", resultErr),
+		)
+		assertTrue(t, matched)
+	}
+}
+
+func testFromStatusNilOrOK(t *testing.T) {
+	t.Parallel()
+
+	assertNil(t, xerrgrpc.FromStatus(nil))
+	assertNil(t, xerrgrpc.FromStatus(status.New(codes.OK, "")))
+}
+
+func testFromStatusWithoutDetails(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	st := status.New(codes.Unavailable, "service unavailable")
+
+	// act
+	resultErr := xerrgrpc.FromStatus(st)
+
+	// assert
+	if assertNotNil(t, resultErr) {
+		assertEqual(t, "service unavailable", resultErr.Error())
+		assertEqual(t, xerr.Code(""), xerr.CodeOf(resultErr))
+		assertNil(t, xerr.Fields(resultErr))
+		assertEqual(t, codes.Unavailable, status.Code(resultErr))
+		assertTrue(t, xerr.IsRetryable(resultErr))
+	}
+}
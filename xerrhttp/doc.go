@@ -0,0 +1,9 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+// Package xerrhttp provides HTTP helpers for xerr errors,
+// like RFC 7807 "application/problem+json" responses,
+// and a panic recovering middleware.
+package xerrhttp
@@ -0,0 +1,176 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerrhttp
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+
+	"github.com/actforgood/xerr"
+)
+
+// WithReporter configures the reporter errors are reported to,
+// by [Middleware] / [Handler], before being written as responses.
+// By default, errors are not reported.
+func WithReporter(reporter xerr.Reporter) Option {
+	return func(cfg *config) {
+		cfg.reporter = reporter
+	}
+}
+
+// Middleware returns a middleware which recovers the panics of the next handler
+// into errors with stack trace (see [xerr.Recover]), reports them (see [WithReporter]),
+// and writes them as problem details responses (see [WriteProblem]).
+// If the next handler already started writing the response, the panic error is only reported.
+// [http.ErrAbortHandler] panics are propagated, as they are meant to abort the response silently.
+// The response writer passed to the next handler implements [http.Flusher] / [http.Hijacker],
+// if the original one does, so that streaming responses and websockets keep working.
+//
+// Example:
+//
+//	mux := http.NewServeMux()
+//	mux.Handle("/users/", xerrhttp.Handler(getUser))
+//	srv := &http.Server{
+//		Handler: xerrhttp.Middleware(xerrhttp.WithReporter(xerr.NewWriterReporter(os.Stderr)))(mux),
+//	}
+func Middleware(opts ...Option) func(next http.Handler) http.Handler {
+	cfg := newConfig(opts)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rw, wrappedW := newResponseWriter(w)
+			defer func() {
+				recovered := recover()
+				if recovered == nil {
+					return
+				}
+				if recovered == http.ErrAbortHandler {
+					panic(recovered)
+				}
+				err := xerr.Recover(recovered)
+				if rw.wroteHeader {
+					cfg.report(err)
+
+					return
+				}
+				cfg.handleError(w, r, err)
+			}()
+			next.ServeHTTP(wrappedW, r)
+		})
+	}
+}
+
+// report reports the error, if a reporter is configured.
+func (cfg *config) report(err error) {
+	if cfg.reporter != nil {
+		cfg.reporter.Report(err)
+	}
+}
+
+// handleError reports the error and writes it as a problem details response.
+func (cfg *config) handleError(w http.ResponseWriter, r *http.Request, err error) {
+	cfg.report(err)
+	writeProblem(w, r, err, cfg)
+}
+
+// responseWriter is an [http.ResponseWriter] which keeps track
+// of whether the response was started.
+type responseWriter struct {
+	http.ResponseWriter
+	wroteHeader bool
+}
+
+// newResponseWriter returns the [responseWriter] wrapping given one, and the
+// [http.ResponseWriter] to be passed to handlers, which implements [http.Flusher] /
+// [http.Hijacker] only if the given one does.
+func newResponseWriter(w http.ResponseWriter) (*responseWriter, http.ResponseWriter) {
+	rw := &responseWriter{ResponseWriter: w}
+	_, isFlusher := w.(http.Flusher)
+	_, isHijacker := w.(http.Hijacker)
+	switch {
+	case isFlusher && isHijacker:
+		return rw, flushHijackResponseWriter{rw}
+	case isFlusher:
+		return rw, flushResponseWriter{rw}
+	case isHijacker:
+		return rw, hijackResponseWriter{rw}
+	default:
+		return rw, rw
+	}
+}
+
+// WriteHeader sends the HTTP response header with the provided status code.
+func (rw *responseWriter) WriteHeader(statusCode int) {
+	rw.wroteHeader = true
+	rw.ResponseWriter.WriteHeader(statusCode)
+}
+
+// Write writes the data as part of the HTTP response.
+func (rw *responseWriter) Write(data []byte) (int, error) {
+	rw.wroteHeader = true
+
+	return rw.ResponseWriter.Write(data)
+}
+
+// Unwrap returns the underlying [http.ResponseWriter],
+// so that [http.ResponseController] can access its optional interfaces.
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
+// flush sends any buffered data to the client.
+// The underlying [http.ResponseWriter] must implement [http.Flusher].
+func (rw *responseWriter) flush() {
+	rw.wroteHeader = true
+	rw.ResponseWriter.(http.Flusher).Flush()
+}
+
+// hijack lets the caller take over the connection.
+// The underlying [http.ResponseWriter] must implement [http.Hijacker].
+func (rw *responseWriter) hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, brw, err := rw.ResponseWriter.(http.Hijacker).Hijack()
+	if err == nil { // the response cannot be written anymore.
+		rw.wroteHeader = true
+	}
+
+	return conn, brw, err
+}
+
+// flushResponseWriter is a [responseWriter] implementing [http.Flusher].
+type flushResponseWriter struct {
+	*responseWriter
+}
+
+// Flush implements [http.Flusher].
+func (w flushResponseWriter) Flush() {
+	w.flush()
+}
+
+// hijackResponseWriter is a [responseWriter] implementing [http.Hijacker].
+type hijackResponseWriter struct {
+	*responseWriter
+}
+
+// Hijack implements [http.Hijacker].
+func (w hijackResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return w.hijack()
+}
+
+// flushHijackResponseWriter is a [responseWriter] implementing [http.Flusher] and [http.Hijacker].
+type flushHijackResponseWriter struct {
+	*responseWriter
+}
+
+// Flush implements [http.Flusher].
+func (w flushHijackResponseWriter) Flush() {
+	w.flush()
+}
+
+// Hijack implements [http.Hijacker].
+func (w flushHijackResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return w.hijack()
+}
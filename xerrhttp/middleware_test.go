@@ -0,0 +1,274 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerrhttp_test
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/actforgood/xerr"
+	"github.com/actforgood/xerr/xerrhttp"
+)
+
+// reporterMock is a concurrent safe xerr.Reporter mock.
+type reporterMock struct {
+	mu       sync.Mutex
+	reported []error
+}
+
+func (m *reporterMock) Report(err error) {
+	m.mu.Lock()
+	m.reported = append(m.reported, err)
+	m.mu.Unlock()
+}
+
+func (m *reporterMock) errors() []error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.reported
+}
+
+func TestMiddleware(t *testing.T) {
+	t.Parallel()
+
+	t.Run("panic is recovered, reported and written", testMiddlewarePanicIsWritten)
+	t.Run("panic after response started is only reported", testMiddlewarePanicAfterResponseStarted)
+	t.Run("abort handler panic is propagated", testMiddlewareAbortHandler)
+	t.Run("no panic", testMiddlewareNoPanic)
+	t.Run("flusher is forwarded", testMiddlewareFlusher)
+	t.Run("hijacker is forwarded", testMiddlewareHijacker)
+	t.Run("optional interfaces not supported", testMiddlewareNoOptionalInterfaces)
+}
+
+func testMiddlewarePanicIsWritten(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		reporter = new(reporterMock)
+		subject  = xerrhttp.Middleware(xerrhttp.WithReporter(reporter))(
+			http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+				panic("nil map")
+			}),
+		)
+		req = httptest.NewRequest(http.MethodGet, "/resource", nil)
+		rec = httptest.NewRecorder()
+	)
+
+	// act
+	subject.ServeHTTP(rec, req)
+
+	// assert
+	assertEqual(t, http.StatusInternalServerError, rec.Code)
+	assertEqual(t, xerrhttp.ContentType, rec.Header().Get("Content-Type"))
+	assertEqual(t, `{"title":"Internal Server Error","status":500,"instance":"/resource"}`, rec.Body.String())
+	if reported := reporter.errors(); assertEqual(t, 1, len(reported)) {
+		assertEqual(t, "panic: nil map", reported[0].Error())
+		assertTrue(t, len(xerr.Frames(reported[0])) > 0)
+	}
+}
+
+func testMiddlewarePanicAfterResponseStarted(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		reporter = new(reporterMock)
+		subject  = xerrhttp.Middleware(xerrhttp.WithReporter(reporter))(
+			http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusAccepted)
+				panic(errors.New("nil map"))
+			}),
+		)
+		req = httptest.NewRequest(http.MethodGet, "/resource", nil)
+		rec = httptest.NewRecorder()
+	)
+
+	// act
+	subject.ServeHTTP(rec, req)
+
+	// assert
+	assertEqual(t, http.StatusAccepted, rec.Code)
+	assertEqual(t, "", rec.Body.String())
+	if reported := reporter.errors(); assertEqual(t, 1, len(reported)) {
+		assertEqual(t, "panic: nil map", reported[0].Error())
+	}
+}
+
+func testMiddlewareAbortHandler(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		reporter = new(reporterMock)
+		subject  = xerrhttp.Middleware(xerrhttp.WithReporter(reporter))(
+			http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+				panic(http.ErrAbortHandler)
+			}),
+		)
+		req = httptest.NewRequest(http.MethodGet, "/resource", nil)
+		rec = httptest.NewRecorder()
+	)
+	defer func() {
+		// assert
+		assertEqual(t, http.ErrAbortHandler, recover())
+		assertEqual(t, 0, len(reporter.errors()))
+	}()
+
+	// act
+	subject.ServeHTTP(rec, req)
+}
+
+func testMiddlewareNoPanic(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		reporter = new(reporterMock)
+		subject  = xerrhttp.Middleware(xerrhttp.WithReporter(reporter))(
+			xerrhttp.Handler(func(w http.ResponseWriter, r *http.Request) error {
+				if r.URL.Query().Get("fail") != "" {
+					return xerr.NotFound("user %d", 42)
+				}
+				_, err := w.Write([]byte("ok"))
+
+				return err
+			}, xerrhttp.WithReporter(reporter)),
+		)
+	)
+
+	// act
+	rec := httptest.NewRecorder()
+	subject.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/42", nil))
+	recErr := httptest.NewRecorder()
+	subject.ServeHTTP(recErr, httptest.NewRequest(http.MethodGet, "/users/42?fail=1", nil))
+
+	// assert
+	assertEqual(t, http.StatusOK, rec.Code)
+	assertEqual(t, "ok", rec.Body.String())
+	assertEqual(t, http.StatusNotFound, recErr.Code)
+	assertTrue(t, strings.Contains(recErr.Body.String(), `"status":404`))
+	if reported := reporter.errors(); assertEqual(t, 1, len(reported)) {
+		assertEqual(t, "user 42", reported[0].Error())
+	}
+}
+
+// hijackerMock is an http.ResponseWriter implementing http.Hijacker (and not http.Flusher).
+type hijackerMock struct {
+	http.ResponseWriter
+	hijacked bool
+}
+
+func (m *hijackerMock) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	m.hijacked = true
+
+	return nil, nil, nil
+}
+
+// plainWriterMock is an http.ResponseWriter implementing no optional interface.
+type plainWriterMock struct {
+	http.ResponseWriter
+}
+
+func testMiddlewareFlusher(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		reporter = new(reporterMock)
+		subject  = xerrhttp.Middleware(xerrhttp.WithReporter(reporter))(
+			http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				flusher, ok := w.(http.Flusher)
+				if !ok {
+					panic("not a flusher")
+				}
+				_, _ = w.Write([]byte("data: event\n\n"))
+				flusher.Flush()
+				panic("stream broken")
+			}),
+		)
+		req = httptest.NewRequest(http.MethodGet, "/events", nil)
+		rec = httptest.NewRecorder()
+	)
+
+	// act
+	subject.ServeHTTP(rec, req)
+
+	// assert
+	assertTrue(t, rec.Flushed)
+	assertEqual(t, "data: event\n\n", rec.Body.String())
+	if reported := reporter.errors(); assertEqual(t, 1, len(reported)) {
+		assertEqual(t, "panic: stream broken", reported[0].Error())
+	}
+}
+
+func testMiddlewareHijacker(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		reporter = new(reporterMock)
+		subject  = xerrhttp.Middleware(xerrhttp.WithReporter(reporter))(
+			http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				if _, isFlusher := w.(http.Flusher); isFlusher {
+					panic("unexpected flusher")
+				}
+				hijacker, ok := w.(http.Hijacker)
+				if !ok {
+					panic("not a hijacker")
+				}
+				_, _, _ = hijacker.Hijack()
+				panic("connection lost")
+			}),
+		)
+		req = httptest.NewRequest(http.MethodGet, "/ws", nil)
+		rec = httptest.NewRecorder()
+		w   = &hijackerMock{ResponseWriter: rec}
+	)
+
+	// act
+	subject.ServeHTTP(w, req)
+
+	// assert
+	assertTrue(t, w.hijacked)
+	assertEqual(t, "", rec.Body.String()) // the response was not written, after hijacking.
+	if reported := reporter.errors(); assertEqual(t, 1, len(reported)) {
+		assertEqual(t, "panic: connection lost", reported[0].Error())
+	}
+}
+
+func testMiddlewareNoOptionalInterfaces(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		isFlusher, isHijacker bool
+		subject               = xerrhttp.Middleware()(
+			http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				_, isFlusher = w.(http.Flusher)
+				_, isHijacker = w.(http.Hijacker)
+				_, _ = w.Write([]byte("ok"))
+			}),
+		)
+		req = httptest.NewRequest(http.MethodGet, "/resource", nil)
+		rec = httptest.NewRecorder()
+	)
+
+	// act
+	subject.ServeHTTP(&plainWriterMock{ResponseWriter: rec}, req)
+
+	// assert
+	assertTrue(t, !isFlusher)
+	assertTrue(t, !isHijacker)
+	assertEqual(t, "ok", rec.Body.String())
+}
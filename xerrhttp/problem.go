@@ -0,0 +1,221 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerrhttp
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/actforgood/xerr"
+)
+
+// ContentType is the media type of a problem document.
+const ContentType = "application/problem+json"
+
+// StatusClientClosedRequest is the (non-standard) status code used
+// for requests canceled by the client (see [xerr.HTTPStatus]).
+const StatusClientClosedRequest = 499
+
+// Problem is an RFC 7807 problem details document.
+type Problem struct {
+	// Type is a URI reference identifying the problem type.
+	// Omitted, it is considered to be "about:blank".
+	Type string `json:"type,omitempty"`
+	// Title is a short summary of the problem type.
+	Title string `json:"title"`
+	// Status is the HTTP status code.
+	Status int `json:"status"`
+	// Detail is an explanation specific to this occurrence of the problem.
+	Detail string `json:"detail,omitempty"`
+	// Instance is a URI reference identifying this occurrence of the problem.
+	Instance string `json:"instance,omitempty"`
+	// Code is the xerr code of the error (extension member).
+	Code string `json:"code,omitempty"`
+	// Fields are the xerr fields of the error (extension member).
+	Fields map[string]any `json:"fields,omitempty"`
+	// InvalidParams are the field violations of the error (extension member).
+	InvalidParams []InvalidParam `json:"invalid-params,omitempty"`
+	// ErrorID is the unique ID of the error (extension member), see [xerr.SetErrorIDGenerator].
+	// It allows correlating a user reported problem with server logs.
+	ErrorID string `json:"error-id,omitempty"`
+
+	retryAfter time.Duration
+}
+
+// InvalidParam describes a request parameter which failed validation.
+type InvalidParam struct {
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}
+
+// StatusMapper is an alias for a function that maps an xerr error code to an HTTP status code.
+type StatusMapper func(code xerr.Code) int
+
+// config holds the conversion configuration.
+type config struct {
+	statusMapper StatusMapper
+	detail       func(err error) string
+	typeBaseURI  string
+	reporter     xerr.Reporter
+}
+
+// Option defines optional function for configuring a conversion.
+type Option func(*config)
+
+// WithStatusMapper configures the function used to map xerr error codes
+// to HTTP status codes. By default, the status code is resolved with [xerr.HTTPStatus]
+// (for example, an xerr code named like a gRPC code, like "NOT_FOUND",
+// is mapped to the equivalent HTTP status code).
+// A configured mapper takes precedence over [xerr.HTTPStatus] for errors having a code,
+// context errors excepted.
+func WithStatusMapper(fn StatusMapper) Option {
+	return func(cfg *config) {
+		if fn != nil {
+			cfg.statusMapper = fn
+		}
+	}
+}
+
+// WithDetail configures the function used to compute the problem detail from the error.
+// By default, the error's user message is used (see [xerr.WithUserMessage]),
+// as the error's message itself is not meant for clients.
+// A nil function disables the detail.
+func WithDetail(fn func(err error) string) Option {
+	return func(cfg *config) {
+		cfg.detail = fn
+	}
+}
+
+// WithTypeBaseURI configures a base URI the xerr code is appended to,
+// in order to produce the problem type. Example: "https://example.com/problems/".
+// By default, problem type is omitted ("about:blank").
+func WithTypeBaseURI(baseURI string) Option {
+	return func(cfg *config) {
+		cfg.typeBaseURI = baseURI
+	}
+}
+
+// newConfig returns a configuration with given options applied.
+func newConfig(opts []Option) *config {
+	cfg := &config{
+		detail: xerr.UserMessage,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return cfg
+}
+
+// ToProblem converts an error into a problem details document.
+// The status code is resolved with [xerr.HTTPStatus], unless a custom
+// status mapper is configured (see [WithStatusMapper]).
+// Returns nil for a nil error.
+func ToProblem(err error, opts ...Option) *Problem {
+	if err == nil {
+		return nil
+	}
+
+	return toProblem(err, newConfig(opts))
+}
+
+// toProblem converts a non-nil error into a problem details document, with given configuration.
+func toProblem(err error, cfg *config) *Problem {
+	code := xerr.CodeOf(err)
+	violations := xerr.FieldViolations(err)
+	problem := &Problem{
+		Status:  httpStatus(err, code, cfg),
+		Code:    string(code),
+		Fields:  xerr.Fields(err),
+		ErrorID: xerr.ID(err),
+	}
+	problem.Title = http.StatusText(problem.Status)
+	if problem.Status == StatusClientClosedRequest {
+		problem.Title = "Client Closed Request"
+	}
+	if cfg.typeBaseURI != "" && code != "" {
+		problem.Type = cfg.typeBaseURI + string(code)
+	}
+	if cfg.detail != nil {
+		problem.Detail = cfg.detail(err)
+	}
+	if len(violations) > 0 {
+		problem.InvalidParams = make([]InvalidParam, len(violations))
+		for idx, violation := range violations {
+			problem.InvalidParams[idx] = InvalidParam{Name: violation.Field, Reason: violation.Description}
+		}
+	}
+	problem.retryAfter, _ = xerr.RetryAfter(err)
+
+	return problem
+}
+
+// ServeHTTP writes the problem as response, with its status code.
+// A "Retry-After" header is set if the error had a backoff hint (see [xerr.WithRetryAfter]).
+// If the fields cannot be JSON encoded, they are omitted.
+func (p *Problem) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	body, err := json.Marshal(p)
+	if err != nil {
+		problem := *p
+		problem.Fields = nil
+		body, _ = json.Marshal(problem)
+	}
+
+	w.Header().Set("Content-Type", ContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	if p.retryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.FormatInt(int64(math.Ceil(p.retryAfter.Seconds())), 10))
+	}
+	w.WriteHeader(p.Status)
+	_, _ = w.Write(body)
+}
+
+// WriteProblem converts the error into a problem and writes it as response.
+// The problem instance is the request path.
+func WriteProblem(w http.ResponseWriter, r *http.Request, err error, opts ...Option) {
+	if err == nil {
+		return
+	}
+
+	writeProblem(w, r, err, newConfig(opts))
+}
+
+// writeProblem converts a non-nil error into a problem, with given configuration,
+// and writes it as response.
+func writeProblem(w http.ResponseWriter, r *http.Request, err error, cfg *config) {
+	problem := toProblem(err, cfg)
+	if r != nil && r.URL != nil {
+		problem.Instance = r.URL.Path
+	}
+	problem.ServeHTTP(w, r)
+}
+
+// HandlerFunc is an HTTP handler that returns an error.
+type HandlerFunc func(w http.ResponseWriter, r *http.Request) error
+
+// Handler returns an [http.Handler] which calls fn, and reports (see [WithReporter])
+// and writes the error it returns, if any, as a problem details response.
+func Handler(fn HandlerFunc, opts ...Option) http.Handler {
+	cfg := newConfig(opts)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := fn(w, r); err != nil {
+			cfg.handleError(w, r, err)
+		}
+	})
+}
+
+// httpStatus returns the HTTP status code of an error.
+func httpStatus(err error, code xerr.Code, cfg *config) int {
+	if cfg.statusMapper == nil || code == "" || xerr.IsCanceled(err) || xerr.IsDeadline(err) {
+		return xerr.HTTPStatus(err)
+	}
+
+	return cfg.statusMapper(code)
+}
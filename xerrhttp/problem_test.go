@@ -0,0 +1,354 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerrhttp_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/actforgood/xerr"
+	"github.com/actforgood/xerr/xerrhttp"
+)
+
+func TestToProblem(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil error", testToProblemNilError)
+	t.Run("code, fields, detail, type", testToProblemDetails)
+	t.Run("user message as detail", testToProblemUserMessageAsDetail)
+	t.Run("field violations", testToProblemFieldViolations)
+	t.Run("status code precedence", testToProblemStatusPrecedence)
+}
+
+func testToProblemNilError(t *testing.T) {
+	t.Parallel()
+
+	// act
+	result := xerrhttp.ToProblem(nil)
+
+	// assert
+	assertNil(t, result)
+}
+
+func testToProblemDetails(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	inputErr := xerr.WithFields(
+		xerr.Wrap(xerr.NewCode("NOT_FOUND", "user not found"), "could not get user"),
+		xerr.F("user_id", 42),
+	)
+
+	// act
+	result := xerrhttp.ToProblem(
+		inputErr,
+		xerrhttp.WithTypeBaseURI("https://example.com/problems/"),
+		xerrhttp.WithDetail(func(err error) string {
+			return "the user does not exist"
+		}),
+	)
+
+	// assert
+	if assertNotNil(t, result) {
+		assertEqual(t, "https://example.com/problems/NOT_FOUND", result.Type)
+		assertEqual(t, "Not Found", result.Title)
+		assertEqual(t, http.StatusNotFound, result.Status)
+		assertEqual(t, "the user does not exist", result.Detail)
+		assertEqual(t, "", result.Instance)
+		assertEqual(t, "NOT_FOUND", result.Code)
+		assertEqual(t, map[string]any{"user_id": 42}, result.Fields)
+		assertNil(t, result.InvalidParams)
+	}
+}
+
+func testToProblemUserMessageAsDetail(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	inputErr := xerr.WithUserMessage(
+		xerr.NewCode("NOT_FOUND", "sql: no rows in result set"),
+		"The user does not exist.",
+	)
+
+	// act
+	result := xerrhttp.ToProblem(inputErr)
+	resultNoDetail := xerrhttp.ToProblem(inputErr, xerrhttp.WithDetail(nil))
+
+	// assert
+	if assertNotNil(t, result) {
+		assertEqual(t, "The user does not exist.", result.Detail)
+	}
+	if assertNotNil(t, resultNoDetail) {
+		assertEqual(t, "", resultNoDetail.Detail)
+	}
+}
+
+func testToProblemFieldViolations(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	validation := xerr.NewValidation()
+	validation.Fail("email", "is required")
+	validation.Fail("age", "must be positive")
+
+	// act
+	result := xerrhttp.ToProblem(validation.ErrOrNil())
+
+	// assert
+	if assertNotNil(t, result) {
+		assertEqual(t, "", result.Type)
+		assertEqual(t, "Bad Request", result.Title)
+		assertEqual(t, http.StatusBadRequest, result.Status)
+		assertEqual(t, "", result.Detail)
+		assertEqual(
+			t,
+			[]xerrhttp.InvalidParam{
+				{Name: "email", Reason: "is required"},
+				{Name: "age", Reason: "must be positive"},
+			},
+			result.InvalidParams,
+		)
+	}
+}
+
+func testToProblemStatusPrecedence(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	validation := xerr.NewValidation()
+	validation.Fail("email", "is required")
+	tests := [...]struct {
+		name          string
+		inputErr      error
+		opts          []xerrhttp.Option
+		expected      int
+		expectedTitle string
+	}{
+		{
+			name:          "standard error",
+			inputErr:      errors.New("some standard error"),
+			expected:      http.StatusInternalServerError,
+			expectedTitle: "Internal Server Error",
+		},
+		{
+			name:          "xerr code not named like a gRPC code",
+			inputErr:      xerr.WithCode(errors.New("some error"), "E001"),
+			expected:      http.StatusInternalServerError,
+			expectedTitle: "Internal Server Error",
+		},
+		{
+			name:          "xerr code named like a gRPC code",
+			inputErr:      xerr.WithCode(errors.New("some error"), "UNAVAILABLE"),
+			expected:      http.StatusServiceUnavailable,
+			expectedTitle: "Service Unavailable",
+		},
+		{
+			name:     "xerr code with custom mapper",
+			inputErr: xerr.WithCode(errors.New("some error"), "E001"),
+			opts: []xerrhttp.Option{xerrhttp.WithStatusMapper(func(code xerr.Code) int {
+				if code == "E001" {
+					return http.StatusTeapot
+				}
+
+				return http.StatusInternalServerError
+			})},
+			expected:      http.StatusTeapot,
+			expectedTitle: "I'm a teapot",
+		},
+		{
+			name:          "xerr code takes precedence over field violations",
+			inputErr:      xerr.WithCode(validation.ErrOrNil(), "UNAUTHENTICATED"),
+			expected:      http.StatusUnauthorized,
+			expectedTitle: "Unauthorized",
+		},
+		{
+			name:          "xerr kind",
+			inputErr:      xerr.Wrap(xerr.NotFound("user %d", 42), "wrap"),
+			expected:      http.StatusNotFound,
+			expectedTitle: "Not Found",
+		},
+		{
+			name:          "xerr kind takes precedence over field violations",
+			inputErr:      xerr.WithKind(validation.ErrOrNil(), xerr.KindConflict),
+			expected:      http.StatusConflict,
+			expectedTitle: "Conflict",
+		},
+		{
+			name:          "xerr code takes precedence over kind",
+			inputErr:      xerr.WithCode(xerr.Unavailable("db down"), "INTERNAL"),
+			expected:      http.StatusInternalServerError,
+			expectedTitle: "Internal Server Error",
+		},
+		{
+			name:          "attached status",
+			inputErr:      xerr.WithHTTPStatus(xerr.NotFound("user %d", 42), http.StatusGone),
+			expected:      http.StatusGone,
+			expectedTitle: "Gone",
+		},
+		{
+			name:          "context canceled",
+			inputErr:      xerr.WithCode(xerr.Wrap(context.Canceled, "wrap"), "NOT_FOUND"),
+			expected:      xerrhttp.StatusClientClosedRequest,
+			expectedTitle: "Client Closed Request",
+		},
+		{
+			name:          "context deadline exceeded",
+			inputErr:      fmt.Errorf("wrap: %w", context.DeadlineExceeded),
+			expected:      http.StatusGatewayTimeout,
+			expectedTitle: "Gateway Timeout",
+		},
+	}
+
+	for _, testData := range tests {
+		test := testData // capture range variable
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			// act
+			result := xerrhttp.ToProblem(test.inputErr, test.opts...)
+
+			// assert
+			if assertNotNil(t, result) {
+				assertEqual(t, test.expected, result.Status)
+				assertEqual(t, test.expectedTitle, result.Title)
+			}
+		})
+	}
+}
+
+func TestWriteProblem(t *testing.T) {
+	t.Parallel()
+
+	t.Run("problem is written", testWriteProblemIsWritten)
+	t.Run("unencodable fields are omitted", testWriteProblemUnencodableFields)
+	t.Run("nil error", testWriteProblemNilError)
+}
+
+func testWriteProblemIsWritten(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	inputErr := xerr.WithRetryAfter(
+		xerr.WithFields(xerr.NewCode("UNAVAILABLE", "db is down"), xerr.F("db", "users")),
+		1500*time.Millisecond,
+	)
+	req := httptest.NewRequest(http.MethodGet, "/users/42?verbose=1", nil)
+	rec := httptest.NewRecorder()
+
+	// act
+	xerrhttp.WriteProblem(rec, req, inputErr)
+
+	// assert
+	assertEqual(t, http.StatusServiceUnavailable, rec.Code)
+	assertEqual(t, xerrhttp.ContentType, rec.Header().Get("Content-Type"))
+	assertEqual(t, "2", rec.Header().Get("Retry-After"))
+	assertEqual(
+		t,
+		`{"title":"Service Unavailable","status":503,"instance":"/users/42","code":"UNAVAILABLE","fields":{"db":"users"}}`,
+		rec.Body.String(),
+	)
+}
+
+func testWriteProblemUnencodableFields(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	inputErr := xerr.WithFields(errors.New("some error"), xerr.F("fn", func() {}))
+	rec := httptest.NewRecorder()
+
+	// act
+	xerrhttp.WriteProblem(rec, nil, inputErr)
+
+	// assert
+	assertEqual(t, http.StatusInternalServerError, rec.Code)
+	assertEqual(t, "", rec.Header().Get("Retry-After"))
+	assertEqual(t, `{"title":"Internal Server Error","status":500}`, rec.Body.String())
+}
+
+func testWriteProblemNilError(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	rec := httptest.NewRecorder()
+
+	// act
+	xerrhttp.WriteProblem(rec, nil, nil)
+
+	// assert
+	assertEqual(t, http.StatusOK, rec.Code)
+	assertEqual(t, 0, rec.Body.Len())
+}
+
+func TestHandler(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	subject := xerrhttp.Handler(func(w http.ResponseWriter, r *http.Request) error {
+		if r.URL.Query().Get("fail") != "" {
+			return xerr.NewCode("PERMISSION_DENIED", "user cannot access resource")
+		}
+		w.WriteHeader(http.StatusNoContent)
+
+		return nil
+	})
+	tests := [...]struct {
+		name         string
+		target       string
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name:         "handler returns error",
+			target:       "/resource?fail=1",
+			expectedCode: http.StatusForbidden,
+			expectedBody: `{"title":"Forbidden","status":403,"instance":"/resource","code":"PERMISSION_DENIED"}`,
+		},
+		{
+			name:         "handler returns nil",
+			target:       "/resource",
+			expectedCode: http.StatusNoContent,
+			expectedBody: "",
+		},
+	}
+
+	for _, testData := range tests {
+		test := testData // capture range variable
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			// arrange
+			req := httptest.NewRequest(http.MethodGet, test.target, nil)
+			rec := httptest.NewRecorder()
+
+			// act
+			subject.ServeHTTP(rec, req)
+
+			// assert
+			assertEqual(t, test.expectedCode, rec.Code)
+			assertEqual(t, test.expectedBody, rec.Body.String())
+		})
+	}
+}
+
+func TestToProblem_withErrorID(t *testing.T) {
+	// test is not parallel as it changes global configuration.
+
+	// arrange
+	xerr.SetErrorIDGenerator(func() string { return "ab12cd" })
+	defer xerr.SetErrorIDGenerator(nil)
+	err := xerr.New("database is down")
+
+	// act
+	problem := xerrhttp.ToProblem(err)
+
+	// assert
+	assertEqual(t, "ab12cd", problem.ErrorID)
+}
@@ -0,0 +1,72 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerrhttp
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/actforgood/xerr"
+)
+
+// recentError is the JSON form of a recorded error, see [RecentErrorsHandler].
+type recentError struct {
+	Time        time.Time       `json:"time"`
+	Message     string          `json:"message"`
+	Fingerprint string          `json:"fingerprint"`
+	TopFrames   []string        `json:"top_frames,omitempty"`
+	Error       json.RawMessage `json:"error,omitempty"`
+}
+
+// RecentErrorsHandler returns a debug handler which writes, as a JSON array,
+// the errors recorded by the configured flight recorder (see [xerr.SetFlightRecorder]),
+// from the oldest to the newest one.
+// The errors recorded in the last period only can be requested with the "since"
+// query parameter, a duration like "1m" (see [time.ParseDuration]).
+// As errors may contain sensitive data, the handler should not be publicly exposed.
+//
+// Example:
+//
+//	debugMux.Handle("/debug/errors", xerrhttp.RecentErrorsHandler())
+//	// curl "localhost:6060/debug/errors?since=1m"
+func RecentErrorsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var since time.Time
+		if sinceParam := r.URL.Query().Get("since"); sinceParam != "" {
+			period, err := time.ParseDuration(sinceParam)
+			if err != nil {
+				http.Error(w, "invalid since parameter: "+err.Error(), http.StatusBadRequest)
+
+				return
+			}
+			since = time.Now().Add(-period)
+		}
+
+		var (
+			serializer = xerr.NewSerializer()
+			recent     = make([]recentError, 0)
+		)
+		for _, entry := range xerr.RecentErrors() {
+			if entry.Time.Before(since) {
+				continue
+			}
+			rErr := recentError{
+				Time:        entry.Time,
+				Message:     entry.Message,
+				Fingerprint: entry.Fingerprint,
+				TopFrames:   entry.TopFrames,
+			}
+			if entry.Err != nil {
+				rErr.Error, _ = serializer.Marshal(entry.Err)
+			}
+			recent = append(recent, rErr)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(recent)
+	})
+}
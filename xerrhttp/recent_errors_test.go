@@ -0,0 +1,72 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerrhttp_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/actforgood/xerr"
+	"github.com/actforgood/xerr/xerrhttp"
+)
+
+func TestRecentErrorsHandler(t *testing.T) {
+	// test is not parallel as it changes global configuration.
+
+	// arrange
+	xerr.SetFlightRecorder(xerr.NewRecorder(10))
+	defer xerr.SetFlightRecorder(nil)
+	_ = xerr.New("not found")
+	subject := xerrhttp.RecentErrorsHandler()
+
+	tests := [...]struct {
+		name           string
+		query          string
+		expectedStatus int
+		expectedLen    int
+	}{
+		{name: "all errors", query: "", expectedStatus: http.StatusOK, expectedLen: 1},
+		{name: "errors since", query: "?since=1h", expectedStatus: http.StatusOK, expectedLen: 1},
+		{name: "no errors since", query: "?since=-1h", expectedStatus: http.StatusOK, expectedLen: 0},
+		{name: "invalid since", query: "?since=yesterday", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, testData := range tests {
+		test := testData // capture range variable
+		t.Run(test.name, func(t *testing.T) {
+			// arrange
+			var (
+				req = httptest.NewRequest(http.MethodGet, "/debug/errors"+test.query, nil)
+				rec = httptest.NewRecorder()
+			)
+
+			// act
+			subject.ServeHTTP(rec, req)
+
+			// assert
+			assertEqual(t, test.expectedStatus, rec.Code)
+			if test.expectedStatus != http.StatusOK {
+				return
+			}
+			assertEqual(t, "application/json", rec.Header().Get("Content-Type"))
+			var result []map[string]any
+			assertNil(t, json.Unmarshal(rec.Body.Bytes(), &result))
+			if assertEqual(t, test.expectedLen, len(result)) && test.expectedLen > 0 {
+				assertEqual(t, "not found", result[0]["message"])
+				assertNotNil(t, result[0]["fingerprint"])
+				errJSON, _ := result[0]["error"].(map[string]any)
+				if assertNotNil(t, errJSON) {
+					assertEqual(t, "not found", errJSON["message"])
+				}
+				recordedAt, _ := time.Parse(time.RFC3339Nano, result[0]["time"].(string))
+				assertTrue(t, time.Since(recordedAt) < time.Minute)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerrmetrics
+
+import (
+	"github.com/actforgood/xerr"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// defaultName is the default name of the errors counter.
+const defaultName = "xerr_errors_total"
+
+// config holds the collector configuration.
+type config struct {
+	namespace   string
+	subsystem   string
+	name        string
+	constLabels prometheus.Labels
+}
+
+// Option defines optional function for configuring a collector.
+type Option func(*config)
+
+// WithNamespace configures the namespace of the errors counter.
+func WithNamespace(namespace string) Option {
+	return func(cfg *config) {
+		cfg.namespace = namespace
+	}
+}
+
+// WithSubsystem configures the subsystem of the errors counter.
+func WithSubsystem(subsystem string) Option {
+	return func(cfg *config) {
+		cfg.subsystem = subsystem
+	}
+}
+
+// WithName configures the name of the errors counter.
+// Defaults to "xerr_errors_total".
+func WithName(name string) Option {
+	return func(cfg *config) {
+		if name != "" {
+			cfg.name = name
+		}
+	}
+}
+
+// WithConstLabels configures constant labels of the errors counter,
+// like the application's name.
+func WithConstLabels(labels prometheus.Labels) Option {
+	return func(cfg *config) {
+		cfg.constLabels = labels
+	}
+}
+
+// Collector is a [prometheus.Collector] counting created errors,
+// labeled by code (see [xerr.CodeOf]) and kind (see [xerr.KindOf]).
+// It is meant to be wired through [xerr.SetOnError]:
+//
+//	// myapp/bootstrap.go
+//	func init() {
+//		collector := xerrmetrics.NewCollector(xerrmetrics.WithNamespace("myapp"))
+//		prometheus.MustRegister(collector)
+//		xerr.SetOnError(collector.OnError)
+//	}
+//
+// Note: codes should be a bounded set, as each code results in a new time series.
+type Collector struct {
+	counter *prometheus.CounterVec
+}
+
+// NewCollector instantiates a new errors Collector.
+func NewCollector(opts ...Option) *Collector {
+	cfg := &config{name: defaultName}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return &Collector{
+		counter: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace:   cfg.namespace,
+				Subsystem:   cfg.subsystem,
+				Name:        cfg.name,
+				Help:        "Number of created errors, by code and kind.",
+				ConstLabels: cfg.constLabels,
+			},
+			[]string{"code", "kind"},
+		),
+	}
+}
+
+// OnError increments the errors counter for given error.
+// It has the signature expected by [xerr.SetOnError] / [xerr.WithOnError].
+func (c *Collector) OnError(err error) {
+	if err == nil {
+		return
+	}
+	c.counter.WithLabelValues(string(xerr.CodeOf(err)), xerr.KindOf(err).String()).Inc()
+}
+
+// Describe implements [prometheus.Collector].
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.counter.Describe(ch)
+}
+
+// Collect implements [prometheus.Collector].
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.counter.Collect(ch)
+}
@@ -0,0 +1,65 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerrmetrics_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/actforgood/xerr"
+	"github.com/actforgood/xerr/xerrmetrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestCollector(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	subject := xerrmetrics.NewCollector(
+		xerrmetrics.WithNamespace("myapp"),
+		xerrmetrics.WithSubsystem("api"),
+		xerrmetrics.WithName("errors_created_total"),
+		xerrmetrics.WithConstLabels(prometheus.Labels{"app": "test"}),
+	)
+	factory := xerr.NewFactory(xerr.WithOnError(subject.OnError))
+	expected := `
+# HELP myapp_api_errors_created_total Number of created errors, by code and kind.
+# TYPE myapp_api_errors_created_total counter
+myapp_api_errors_created_total{app="test",code="",kind="other"} 2
+myapp_api_errors_created_total{app="test",code="",kind="not_found"} 2
+myapp_api_errors_created_total{app="test",code="E001",kind="other"} 1
+`
+
+	// act
+	_ = factory.New("some error")
+	_ = factory.Wrap(errors.New("some error"), "wrap")
+	subject.OnError(xerr.WithCode(errors.New("some error"), "E001"))
+	subject.OnError(xerr.NotFound("user %d", 1))
+	subject.OnError(xerr.NotFound("user %d", 2))
+	subject.OnError(nil)
+
+	// assert
+	err := testutil.CollectAndCompare(subject, strings.NewReader(expected))
+	assertNil(t, err)
+}
+
+func TestCollector_defaultName(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	subject := xerrmetrics.NewCollector(xerrmetrics.WithName(""))
+	registry := prometheus.NewPedanticRegistry()
+
+	// act
+	err := registry.Register(subject)
+	subject.OnError(errors.New("some error"))
+
+	// assert
+	assertNil(t, err)
+	assertEqual(t, 1, testutil.CollectAndCount(subject, "xerr_errors_total"))
+}
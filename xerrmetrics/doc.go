@@ -0,0 +1,7 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+// Package xerrmetrics provides Prometheus metrics for xerr errors.
+package xerrmetrics
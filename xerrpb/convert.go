@@ -0,0 +1,147 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerrpb
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+
+	"github.com/actforgood/xerr"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// To converts an error into its protobuf wire format.
+// The message holds the error's message, code, fields and stack trace frames
+// (honoring the configured [xerr.SkipFrame] and processors), and the chain
+// of the errors it wraps, as causes having their message and code set.
+// Fields' values which cannot be represented as a [structpb.Value]
+// are converted to strings.
+// Returns nil for a nil error.
+//
+// Example:
+//
+//	msg := xerrpb.To(err)
+//	data, _ := proto.Marshal(msg)
+func To(err error) *Error {
+	if err == nil {
+		return nil
+	}
+
+	msg := &Error{
+		Message: err.Error(),
+		Code:    string(xerr.CodeOf(err)),
+	}
+	if fields := xerr.FieldsOf(err); len(fields) > 0 {
+		msg.Fields = make(map[string]*structpb.Value, len(fields))
+		for _, field := range fields {
+			val, valErr := structpb.NewValue(field.Value)
+			if valErr != nil {
+				val = structpb.NewStringValue(fmt.Sprint(field.Value))
+			}
+			msg.Fields[field.Key] = val
+		}
+	}
+	if frames := xerr.Frames(err); len(frames) > 0 {
+		msg.Frames = make([]*Frame, len(frames))
+		for idx, frame := range frames {
+			msg.Frames[idx] = &Frame{Function: frame.Function, File: frame.File, Line: int64(frame.Line)}
+		}
+	}
+
+	// the causes having the same message as their parent (like annotations) are skipped.
+	parent := msg
+	for e := errors.Unwrap(err); e != nil; e = errors.Unwrap(e) {
+		if causeMsg := e.Error(); causeMsg != parent.GetMessage() {
+			parent.Cause = &Error{Message: causeMsg, Code: string(xerr.CodeOf(e))}
+			parent = parent.Cause
+		}
+	}
+
+	return msg
+}
+
+// From converts an error's protobuf wire format into an error.
+// The returned error has the same message, and its code and fields restored,
+// so that [xerr.CodeOf] / [xerr.Fields] work identically on it.
+// Note: numeric fields' values are restored as float64.
+// The causes are restored as the errors it wraps, and the original (remote)
+// stack trace is written in the extended format (%+v), in a "remote stack" section.
+// Returns nil for a nil message.
+func From(msg *Error) error {
+	if msg == nil {
+		return nil
+	}
+
+	var err error = &remoteError{
+		msg:    msg.GetMessage(),
+		frames: msg.GetFrames(),
+		cause:  From(msg.GetCause()),
+	}
+	if len(msg.GetFields()) > 0 {
+		fields := make([]xerr.Field, 0, len(msg.GetFields()))
+		for key, val := range msg.GetFields() {
+			fields = append(fields, xerr.F(key, val.AsInterface()))
+		}
+		sort.Slice(fields, func(i, j int) bool {
+			return fields[i].Key < fields[j].Key
+		})
+		err = xerr.WithFields(err, fields...)
+	}
+	if code := msg.GetCode(); code != "" {
+		err = xerr.WithCode(err, xerr.Code(code))
+	}
+
+	return err
+}
+
+// remoteError is an error decoded from its protobuf wire format.
+type remoteError struct {
+	msg    string
+	frames []*Frame
+	cause  error
+}
+
+// Error returns the error's message.
+// Implements std error interface.
+func (err *remoteError) Error() string {
+	return err.msg
+}
+
+// Unwrap returns the error's cause, if any.
+func (err *remoteError) Unwrap() error {
+	return err.cause
+}
+
+// Format implements [fmt.Formatter].
+// The extended format (%+v) includes the remote stack trace.
+func (err *remoteError) Format(f fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if f.Flag('+') {
+			_, _ = io.WriteString(f, err.msg)
+			if len(err.frames) > 0 {
+				_, _ = io.WriteString(f, "\nremote stack:")
+			}
+			for _, frame := range err.frames {
+				_, _ = io.WriteString(f, "\n")
+				_, _ = io.WriteString(f, frame.GetFunction())
+				_, _ = io.WriteString(f, "\n\t")
+				_, _ = io.WriteString(f, frame.GetFile())
+				_, _ = io.WriteString(f, ":")
+				_, _ = io.WriteString(f, strconv.FormatInt(frame.GetLine(), 10))
+			}
+
+			return
+		}
+
+		fallthrough
+	case 's':
+		_, _ = io.WriteString(f, err.msg)
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerrpb_test
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/actforgood/xerr"
+	"github.com/actforgood/xerr/xerrpb"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestTo(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	inputErr := xerr.WithFields(
+		xerr.Wrap(xerr.NewCode("NOT_FOUND", "user not found"), "could not get user"),
+		xerr.F("user_id", 42),
+		xerr.F("timeout", time.Second),
+	)
+
+	// act
+	result := xerrpb.To(inputErr)
+
+	// assert
+	assertEqual(t, "could not get user: user not found", result.GetMessage())
+	assertEqual(t, "NOT_FOUND", result.GetCode())
+	assertEqual(t, float64(42), result.GetFields()["user_id"].GetNumberValue())
+	assertEqual(t, "1s", result.GetFields()["timeout"].GetStringValue())
+	if assertTrue(t, len(result.GetFrames()) > 0) {
+		assertEqual(t, "github.com/actforgood/xerr/xerrpb_test.TestTo", result.GetFrames()[0].GetFunction())
+	}
+	if assertNotNil(t, result.GetCause()) {
+		assertEqual(t, "user not found", result.GetCause().GetMessage())
+		assertEqual(t, "NOT_FOUND", result.GetCause().GetCode())
+		assertNil(t, result.GetCause().GetCause())
+	}
+	assertNil(t, xerrpb.To(nil))
+}
+
+func TestFrom(t *testing.T) {
+	t.Parallel()
+
+	t.Run("round trip", testFromRoundTrip)
+	t.Run("nil message", testFromNilMessage)
+}
+
+func testFromRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	origErr := xerr.WithFields(
+		xerr.Wrap(xerr.WithCode(errors.New("connection reset"), "UNAVAILABLE"), "could not get user"),
+		xerr.F("user_id", 42),
+		xerr.F("tenant", "acme"),
+	)
+	data, err := proto.Marshal(xerrpb.To(origErr))
+	assertNil(t, err)
+	msg := new(xerrpb.Error)
+	assertNil(t, proto.Unmarshal(data, msg))
+
+	// act
+	resultErr := xerrpb.From(msg)
+
+	// assert
+	if assertNotNil(t, resultErr) {
+		assertEqual(t, origErr.Error(), resultErr.Error())
+		assertEqual(t, origErr.Error(), fmt.Sprintf("%v", resultErr))
+		assertEqual(t, xerr.Code("UNAVAILABLE"), xerr.CodeOf(resultErr))
+		assertEqual(t, map[string]any{"user_id": float64(42), "tenant": "acme"}, xerr.Fields(resultErr))
+		cause := errors.Unwrap(errors.Unwrap(errors.Unwrap(resultErr)))
+		if assertNotNil(t, cause) {
+			assertEqual(t, "connection reset", cause.Error())
+		}
+		matched, _ := regexp.MatchString(
+			`^could not get user: connection reset\nremote stack:\ngithub\.com/actforgood/xerr/xerrpb_test\.testFromRoundTrip\n\t.+convert_test\.go:\d+\n`,
+			fmt.Sprintf("%+v", resultErr),
+		)
+		if !assertTrue(t, matched) {
+			t.Log(fmt.Sprintf("%+v", resultErr))
+		}
+	}
+}
+
+func testFromNilMessage(t *testing.T) {
+	t.Parallel()
+
+	// act & assert
+	assertNil(t, xerrpb.From(nil))
+}
@@ -0,0 +1,10 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+// Package xerrpb provides a protobuf wire format for xerr errors,
+// so that they can be propagated across service boundaries.
+package xerrpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative error.proto
@@ -0,0 +1,8 @@
+module github.com/actforgood/xerr/xerrpb
+
+go 1.21
+
+require (
+	github.com/actforgood/xerr v1.2.0
+	google.golang.org/protobuf v1.34.2
+)
@@ -0,0 +1,142 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerrsentry
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+
+	"github.com/actforgood/xerr"
+	"github.com/getsentry/sentry-go"
+)
+
+// defaultFingerprint is Sentry's placeholder for its default grouping.
+const defaultFingerprint = "{{ default }}"
+
+// config holds the conversion configuration.
+type config struct {
+	tagKeys map[string]struct{}
+}
+
+// Option defines optional function for configuring a conversion.
+type Option func(*config)
+
+// WithTags configures the fields to be set as event tags (stringified),
+// instead of event extra data.
+func WithTags(keys ...string) Option {
+	return func(cfg *config) {
+		for _, key := range keys {
+			cfg.tagKeys[key] = struct{}{}
+		}
+	}
+}
+
+// newConfig returns a configuration with given options applied.
+func newConfig(opts []Option) *config {
+	cfg := &config{tagKeys: make(map[string]struct{})}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return cfg
+}
+
+// NewEvent converts an error into a Sentry event.
+// The event has:
+//   - the level mapped from error's severity (see [xerr.SeverityOf]);
+//   - an exception with the error's message and stack trace frames;
+//   - the error's fields as extra data, or tags (see [WithTags]);
+//   - the error's code as a fingerprint component, next to Sentry's default
+//     grouping (see [xerr.CodeOf]).
+//
+// Returns nil for a nil error.
+func NewEvent(err error, opts ...Option) *sentry.Event {
+	if err == nil {
+		return nil
+	}
+	cfg := newConfig(opts)
+
+	event := sentry.NewEvent()
+	event.Level = level(xerr.SeverityOf(err))
+	event.Message = err.Error()
+	event.Exception = []sentry.Exception{{
+		Type:       errType(err),
+		Value:      err.Error(),
+		Stacktrace: stacktrace(err),
+	}}
+	for _, field := range xerr.FieldsOf(err) {
+		if _, isTag := cfg.tagKeys[field.Key]; isTag {
+			event.Tags[field.Key] = fmt.Sprint(field.Value)
+		} else {
+			event.Extra[field.Key] = field.Value
+		}
+	}
+	if code := xerr.CodeOf(err); code != "" {
+		event.Fingerprint = []string{defaultFingerprint, string(code)}
+	}
+
+	return event
+}
+
+// Capture converts the error into a Sentry event (see [NewEvent]) and captures it
+// with given hub. If hub is nil, the current hub is used.
+// Returns the captured event's ID, or nil if the error is nil or the event was not sent.
+func Capture(hub *sentry.Hub, err error, opts ...Option) *sentry.EventID {
+	event := NewEvent(err, opts...)
+	if event == nil {
+		return nil
+	}
+	if hub == nil {
+		hub = sentry.CurrentHub()
+	}
+
+	return hub.CaptureEvent(event)
+}
+
+// level returns the Sentry level corresponding to given severity.
+func level(sev xerr.Severity) sentry.Level {
+	switch sev {
+	case xerr.SeverityDebug:
+		return sentry.LevelDebug
+	case xerr.SeverityInfo:
+		return sentry.LevelInfo
+	case xerr.SeverityWarn:
+		return sentry.LevelWarning
+	case xerr.SeverityFatal:
+		return sentry.LevelFatal
+	default:
+		return sentry.LevelError
+	}
+}
+
+// errType returns the type of the root cause of the error, see [xerr.RootCause].
+func errType(err error) string {
+	return reflect.TypeOf(xerr.RootCause(err)).String()
+}
+
+// stacktrace returns the Sentry stack trace of the error, if it has one,
+// the one of the outermost error with stack trace found in its chain (see [xerr.Frames]),
+// with configured [xerr.SkipFrame] and frame processors applied, as in the extended (%+v) output.
+// Frames are ordered the way Sentry expects them, outermost call first.
+func stacktrace(err error) *sentry.Stacktrace {
+	frames := xerr.Frames(err)
+	if len(frames) == 0 {
+		return nil
+	}
+
+	sentryFrames := make([]sentry.Frame, len(frames))
+	for idx, f := range frames {
+		sentryFrames[len(frames)-1-idx] = sentry.NewFrame(runtime.Frame{
+			PC:       f.PC,
+			Function: f.Function,
+			File:     f.File,
+			Line:     f.Line,
+		})
+	}
+
+	return &sentry.Stacktrace{Frames: sentryFrames}
+}
@@ -0,0 +1,251 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerrsentry_test
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/actforgood/xerr"
+	"github.com/actforgood/xerr/xerrsentry"
+	"github.com/getsentry/sentry-go"
+)
+
+func TestNewEvent(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil error", testNewEventNilError)
+	t.Run("stack error with code and fields", testNewEventStackError)
+	t.Run("standard error", testNewEventStandardError)
+	t.Run("level", testNewEventLevel)
+	t.Run("MultiError has no stack trace of its own", testNewEventMultiError)
+	t.Run("self wrapping error", testNewEventCyclicError)
+}
+
+func testNewEventNilError(t *testing.T) {
+	t.Parallel()
+
+	// act
+	result := xerrsentry.NewEvent(nil)
+
+	// assert
+	assertNil(t, result)
+}
+
+func testNewEventStackError(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	inputErr := xerr.WithFields(
+		xerr.Wrap(xerr.NewCode("NOT_FOUND", "user not found"), "could not get user"),
+		xerr.F("user_id", 42),
+		xerr.F("tenant", "acme"),
+	)
+
+	// act
+	result := xerrsentry.NewEvent(inputErr, xerrsentry.WithTags("tenant"))
+
+	// assert
+	if !assertNotNil(t, result) {
+		return
+	}
+	assertEqual(t, sentry.LevelError, result.Level)
+	assertEqual(t, "could not get user: user not found", result.Message)
+	if assertEqual(t, 1, len(result.Exception)) {
+		exception := result.Exception[0]
+		assertEqual(t, "*xerr.stackError", exception.Type)
+		assertEqual(t, "could not get user: user not found", exception.Value)
+		if assertNotNil(t, exception.Stacktrace) && assertTrue(t, len(exception.Stacktrace.Frames) > 1) {
+			// Sentry expects outermost call first.
+			lastFrame := exception.Stacktrace.Frames[len(exception.Stacktrace.Frames)-1]
+			assertEqual(t, "testNewEventStackError", lastFrame.Function)
+			assertEqual(t, "github.com/actforgood/xerr/xerrsentry_test", lastFrame.Module)
+			assertTrue(t, lastFrame.Lineno > 0)
+		}
+	}
+	assertEqual(t, map[string]any{"user_id": 42}, result.Extra)
+	assertEqual(t, map[string]string{"tenant": "acme"}, result.Tags)
+	assertEqual(t, []string{"{{ default }}", "NOT_FOUND"}, result.Fingerprint)
+}
+
+func testNewEventStandardError(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	inputErr := errors.New("some standard error")
+
+	// act
+	result := xerrsentry.NewEvent(inputErr)
+
+	// assert
+	if assertNotNil(t, result) && assertEqual(t, 1, len(result.Exception)) {
+		assertEqual(t, "*errors.errorString", result.Exception[0].Type)
+		assertNil(t, result.Exception[0].Stacktrace)
+		assertEqual(t, 0, len(result.Extra))
+		assertEqual(t, 0, len(result.Tags))
+		assertNil(t, result.Fingerprint)
+	}
+}
+
+func testNewEventLevel(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	tests := [...]struct {
+		name     string
+		severity xerr.Severity
+		expected sentry.Level
+	}{
+		{name: "debug", severity: xerr.SeverityDebug, expected: sentry.LevelDebug},
+		{name: "info", severity: xerr.SeverityInfo, expected: sentry.LevelInfo},
+		{name: "warn", severity: xerr.SeverityWarn, expected: sentry.LevelWarning},
+		{name: "error", severity: xerr.SeverityError, expected: sentry.LevelError},
+		{name: "fatal", severity: xerr.SeverityFatal, expected: sentry.LevelFatal},
+	}
+
+	for _, testData := range tests {
+		test := testData // capture range variable
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			// act
+			result := xerrsentry.NewEvent(xerr.WithSeverity(xerr.New("some error"), test.severity))
+
+			// assert
+			assertEqual(t, test.expected, result.Level)
+		})
+	}
+}
+
+func testNewEventMultiError(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	mErr := xerr.NewMultiError().Add(xerr.New("first"), errors.New("second"))
+
+	// act
+	result := xerrsentry.NewEvent(mErr)
+	resultWrapped := xerrsentry.NewEvent(xerr.Wrap(mErr, "batch failed"))
+
+	// assert
+	if assertNotNil(t, result) && assertEqual(t, 1, len(result.Exception)) {
+		assertNil(t, result.Exception[0].Stacktrace)
+	}
+	if assertNotNil(t, resultWrapped) && assertEqual(t, 1, len(resultWrapped.Exception)) {
+		stacktrace := resultWrapped.Exception[0].Stacktrace
+		if assertNotNil(t, stacktrace) && assertTrue(t, len(stacktrace.Frames) > 0) {
+			assertEqual(t, "testNewEventMultiError", stacktrace.Frames[len(stacktrace.Frames)-1].Function)
+		}
+	}
+}
+
+// cyclicErr is an error which wraps itself.
+type cyclicErr struct{}
+
+func (cErr *cyclicErr) Error() string { return "cyclic error" }
+
+func (cErr *cyclicErr) Unwrap() error { return cErr }
+
+func testNewEventCyclicError(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	inputErr := xerr.Wrap(new(cyclicErr), "wrapped")
+
+	// act
+	result := xerrsentry.NewEvent(inputErr)
+
+	// assert
+	if assertNotNil(t, result) && assertEqual(t, 1, len(result.Exception)) {
+		assertEqual(t, "*xerrsentry_test.cyclicErr", result.Exception[0].Type)
+		assertEqual(t, "wrapped: cyclic error", result.Exception[0].Value)
+		assertNotNil(t, result.Exception[0].Stacktrace)
+	}
+}
+
+func TestNewEvent_withGlobalConfigurationChanged(t *testing.T) {
+	// test is not parallel as it changes global configuration.
+	// arrange
+	xerr.SetSkipFrame(func(fnName, _ string) bool {
+		return !strings.HasPrefix(fnName, "github.com/actforgood")
+	})
+	xerr.SetFrameFnNameProcessor(func(fnName string) string {
+		return fnName + "_FOO"
+	})
+	defer func() { // restore original global state
+		xerr.SetSkipFrame(xerr.AllowFrame)
+		xerr.SetFrameFnNameProcessor(nil)
+	}()
+	inputErr := xerr.New("something went bad")
+
+	// act
+	result := xerrsentry.NewEvent(inputErr)
+
+	// assert
+	if assertNotNil(t, result) && assertEqual(t, 1, len(result.Exception)) {
+		stacktrace := result.Exception[0].Stacktrace
+		if assertNotNil(t, stacktrace) && assertEqual(t, 1, len(stacktrace.Frames)) {
+			assertEqual(t, "TestNewEvent_withGlobalConfigurationChanged_FOO", stacktrace.Frames[0].Function)
+			assertEqual(t, len(xerr.Frames(inputErr)), len(stacktrace.Frames))
+		}
+	}
+}
+
+func TestCapture(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	transport := &transportMock{}
+	client, err := sentry.NewClient(sentry.ClientOptions{Transport: transport})
+	if err != nil {
+		t.Fatal(err)
+	}
+	hub := sentry.NewHub(client, sentry.NewScope())
+
+	// act
+	nilErrResult := xerrsentry.Capture(hub, nil)
+	result := xerrsentry.Capture(hub, xerr.New("something went bad"))
+
+	// assert
+	assertNil(t, nilErrResult)
+	if assertNotNil(t, result) {
+		events := transport.Events()
+		if assertEqual(t, 1, len(events)) {
+			assertEqual(t, *result, events[0].EventID)
+			assertEqual(t, "something went bad", events[0].Message)
+		}
+	}
+}
+
+// transportMock is a mock for [sentry.Transport], which stores sent events.
+type transportMock struct {
+	mu     sync.Mutex
+	events []*sentry.Event
+}
+
+func (t *transportMock) Configure(sentry.ClientOptions) {}
+
+func (t *transportMock) SendEvent(event *sentry.Event) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.events = append(t.events, event)
+}
+
+func (t *transportMock) Flush(time.Duration) bool {
+	return true
+}
+
+func (t *transportMock) Events() []*sentry.Event {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.events
+}
+
+func (t *transportMock) Close() {}
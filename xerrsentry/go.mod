@@ -0,0 +1,13 @@
+module github.com/actforgood/xerr/xerrsentry
+
+go 1.21
+
+require (
+	github.com/actforgood/xerr v1.2.0
+	github.com/getsentry/sentry-go v0.28.1
+)
+
+require (
+	golang.org/x/sys v0.18.0 // indirect
+	golang.org/x/text v0.14.0 // indirect
+)
@@ -0,0 +1,213 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerrtwirp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/actforgood/xerr"
+	"github.com/twitchtv/twirp"
+)
+
+// CodeMetaKey is the Twirp error metadata key the xerr code is stored under.
+const CodeMetaKey = "xerr_code"
+
+// codeByName maps xerr codes named like gRPC codes to Twirp codes.
+var codeByName = map[xerr.Code]twirp.ErrorCode{
+	"CANCELLED":           twirp.Canceled,
+	"UNKNOWN":             twirp.Unknown,
+	"INVALID_ARGUMENT":    twirp.InvalidArgument,
+	"DEADLINE_EXCEEDED":   twirp.DeadlineExceeded,
+	"NOT_FOUND":           twirp.NotFound,
+	"ALREADY_EXISTS":      twirp.AlreadyExists,
+	"PERMISSION_DENIED":   twirp.PermissionDenied,
+	"RESOURCE_EXHAUSTED":  twirp.ResourceExhausted,
+	"FAILED_PRECONDITION": twirp.FailedPrecondition,
+	"ABORTED":             twirp.Aborted,
+	"OUT_OF_RANGE":        twirp.OutOfRange,
+	"UNIMPLEMENTED":       twirp.Unimplemented,
+	"INTERNAL":            twirp.Internal,
+	"UNAVAILABLE":         twirp.Unavailable,
+	"DATA_LOSS":           twirp.DataLoss,
+	"UNAUTHENTICATED":     twirp.Unauthenticated,
+}
+
+// codeByKind maps xerr kinds to Twirp codes.
+var codeByKind = map[xerr.Kind]twirp.ErrorCode{
+	xerr.KindNotFound:        twirp.NotFound,
+	xerr.KindInvalid:         twirp.InvalidArgument,
+	xerr.KindPermission:      twirp.PermissionDenied,
+	xerr.KindUnauthenticated: twirp.Unauthenticated,
+	xerr.KindConflict:        twirp.AlreadyExists,
+	xerr.KindUnavailable:     twirp.Unavailable,
+	xerr.KindInternal:        twirp.Internal,
+}
+
+// CodeMapper is an alias for a function that maps an xerr error code to a Twirp code.
+type CodeMapper func(code xerr.Code) twirp.ErrorCode
+
+// config holds the conversion configuration.
+type config struct {
+	codeMapper CodeMapper
+}
+
+// Option defines optional function for configuring a conversion.
+type Option func(*config)
+
+// WithCodeMapper configures the function used to map xerr error codes
+// to Twirp codes. By default, an xerr code named like a gRPC code
+// (for example "NOT_FOUND") is mapped to the equivalent Twirp code,
+// others to [twirp.Unknown].
+func WithCodeMapper(fn CodeMapper) Option {
+	return func(cfg *config) {
+		if fn != nil {
+			cfg.codeMapper = fn
+		}
+	}
+}
+
+// newConfig returns a configuration with given options applied.
+func newConfig(opts []Option) *config {
+	cfg := &config{
+		codeMapper: mapCodeByName,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return cfg
+}
+
+// ToError converts an error into a Twirp error.
+// The Twirp error message is the error's user message (see [xerr.WithUserMessage]),
+// if it has one, as the error's message itself is not meant for clients,
+// otherwise the error's message.
+// The Twirp code is, in this order of precedence:
+// the code of a Twirp error found in the error's chain,
+// [twirp.Canceled] / [twirp.DeadlineExceeded] for context errors,
+// the mapped xerr code (see [WithCodeMapper]),
+// the mapped xerr kind, if the xerr code is not mapped (see [xerr.KindOf]),
+// like [xerr.HTTPStatus] does.
+// The xerr fields are attached as metadata, and the xerr code
+// as metadata too, under [CodeMetaKey] key.
+// The returned Twirp error wraps the given error, so that server hooks can inspect it.
+// Returns nil for a nil error.
+func ToError(err error, opts ...Option) twirp.Error {
+	if err == nil {
+		return nil
+	}
+	cfg := newConfig(opts)
+
+	msg := xerr.UserMessage(err)
+	if msg == "" {
+		msg = err.Error()
+	}
+	twerr := twirp.NewError(twirpCode(err, cfg), msg)
+	for _, field := range xerr.FieldsOf(err) {
+		twerr = twerr.WithMeta(field.Key, fmt.Sprint(field.Value))
+	}
+	if code := xerr.CodeOf(err); code != "" {
+		twerr = twerr.WithMeta(CodeMetaKey, string(code))
+	}
+
+	return twirp.WrapError(twerr, err)
+}
+
+// FromError converts a Twirp error, found in the given error's chain, into an error.
+// The returned error has the Twirp error message, and the xerr code and fields
+// restored from the metadata, so that [xerr.CodeOf], [xerr.Fields] work identically on it.
+// Note: fields' values are restored as strings.
+// The Twirp error is wrapped, so that it can be retrieved with [errors.As].
+// An error without a Twirp error in its chain is returned as it is.
+// Returns nil for a nil error.
+//
+// Example:
+//
+//	res, err := client.GetUser(ctx, req)
+//	if err != nil {
+//		err = xerrtwirp.FromError(err)
+//		fmt.Println(xerr.CodeOf(err), xerr.Fields(err))
+//	}
+func FromError(err error) error {
+	var twerr twirp.Error
+	if !errors.As(err, &twerr) {
+		return err
+	}
+
+	var (
+		result error = &remoteError{twerr: twerr}
+		meta         = twerr.MetaMap()
+		fields       = make([]xerr.Field, 0, len(meta))
+	)
+	for key, val := range meta {
+		if key != CodeMetaKey {
+			fields = append(fields, xerr.F(key, val))
+		}
+	}
+	if len(fields) > 0 {
+		sort.Slice(fields, func(i, j int) bool {
+			return fields[i].Key < fields[j].Key
+		})
+		result = xerr.WithFields(result, fields...)
+	}
+	if code := meta[CodeMetaKey]; code != "" {
+		result = xerr.WithCode(result, xerr.Code(code))
+	}
+
+	return result
+}
+
+// twirpCode returns the Twirp code of an error.
+func twirpCode(err error, cfg *config) twirp.ErrorCode {
+	var twerr twirp.Error
+	if errors.As(err, &twerr) {
+		return twerr.Code()
+	}
+	if errors.Is(err, context.Canceled) {
+		return twirp.Canceled
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return twirp.DeadlineExceeded
+	}
+
+	if code := cfg.codeMapper(xerr.CodeOf(err)); code != twirp.Unknown {
+		return code
+	}
+	if kindCode, found := codeByKind[xerr.KindOf(err)]; found {
+		return kindCode
+	}
+
+	return twirp.Unknown
+}
+
+// mapCodeByName is the default [CodeMapper], it maps an xerr code named like
+// a gRPC code (for example "NOT_FOUND") to the equivalent Twirp code.
+func mapCodeByName(code xerr.Code) twirp.ErrorCode {
+	if twirpCode, found := codeByName[code]; found {
+		return twirpCode
+	}
+
+	return twirp.Unknown
+}
+
+// remoteError is an error decoded from a Twirp error.
+type remoteError struct {
+	twerr twirp.Error
+}
+
+// Error returns the Twirp error message.
+// Implements std error interface.
+func (err *remoteError) Error() string {
+	return err.twerr.Msg()
+}
+
+// Unwrap returns the Twirp error.
+func (err *remoteError) Unwrap() error {
+	return err.twerr
+}
@@ -0,0 +1,176 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerrtwirp_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/actforgood/xerr"
+	"github.com/actforgood/xerr/xerrtwirp"
+	"github.com/twitchtv/twirp"
+)
+
+func TestToError(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil error", testToErrorNilError)
+	t.Run("code precedence", testToErrorCodePrecedence)
+	t.Run("message, metadata", testToErrorMessageAndMeta)
+}
+
+func testToErrorNilError(t *testing.T) {
+	t.Parallel()
+
+	// act
+	result := xerrtwirp.ToError(nil)
+
+	// assert
+	assertNil(t, result)
+}
+
+func testToErrorCodePrecedence(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	tests := [...]struct {
+		name     string
+		inputErr error
+		opts     []xerrtwirp.Option
+		expected twirp.ErrorCode
+	}{
+		{
+			name:     "standard error",
+			inputErr: errors.New("some standard error"),
+			expected: twirp.Unknown,
+		},
+		{
+			name:     "xerr code named like a gRPC code",
+			inputErr: xerr.WithCode(errors.New("some error"), "DATA_LOSS"),
+			expected: twirp.DataLoss,
+		},
+		{
+			name:     "xerr code with custom mapper",
+			inputErr: xerr.WithCode(errors.New("some error"), "E001"),
+			opts: []xerrtwirp.Option{xerrtwirp.WithCodeMapper(func(code xerr.Code) twirp.ErrorCode {
+				if code == "E001" {
+					return twirp.FailedPrecondition
+				}
+
+				return twirp.Unknown
+			})},
+			expected: twirp.FailedPrecondition,
+		},
+		{
+			name:     "mapped xerr code takes precedence over kind",
+			inputErr: xerr.WithCode(xerr.Conflict("user %d exists", 42), "NOT_FOUND"),
+			expected: twirp.NotFound,
+		},
+		{
+			name:     "xerr kind, with code not mapped",
+			inputErr: xerr.WithKind(xerr.NewCode("user_exists", "user exists"), xerr.KindConflict),
+			expected: twirp.AlreadyExists,
+		},
+		{
+			name:     "xerr kind",
+			inputErr: xerr.Wrap(xerr.Permission("user %d", 42), "wrap"),
+			expected: twirp.PermissionDenied,
+		},
+		{
+			name:     "twirp error",
+			inputErr: xerr.Wrap(twirp.ResourceExhausted.Error("quota"), "wrap"),
+			expected: twirp.ResourceExhausted,
+		},
+		{
+			name:     "context canceled",
+			inputErr: fmt.Errorf("wrap: %w", context.Canceled),
+			expected: twirp.Canceled,
+		},
+	}
+
+	for _, testData := range tests {
+		test := testData // capture range variable
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			// act
+			result := xerrtwirp.ToError(test.inputErr, test.opts...)
+
+			// assert
+			assertEqual(t, test.expected, result.Code())
+		})
+	}
+}
+
+func testToErrorMessageAndMeta(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	inputErr := xerr.WithFields(
+		xerr.WithUserMessage(xerr.NewCode("NOT_FOUND", "sql: no rows in result set"), "User not found."),
+		xerr.F("user_id", 42),
+	)
+
+	// act
+	result := xerrtwirp.ToError(inputErr)
+
+	// assert
+	assertEqual(t, twirp.NotFound, result.Code())
+	assertEqual(t, "User not found.", result.Msg())
+	assertEqual(t, map[string]string{"user_id": "42", xerrtwirp.CodeMetaKey: "NOT_FOUND"}, result.MetaMap())
+	assertTrue(t, errors.Is(result, inputErr))
+}
+
+func TestFromError(t *testing.T) {
+	t.Parallel()
+
+	t.Run("round trip", testFromErrorRoundTrip)
+	t.Run("not a twirp error", testFromErrorNotTwirpError)
+}
+
+func testFromErrorRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	origErr := xerr.WithFields(
+		xerr.Wrap(xerr.NewCode("NOT_FOUND", "user not found"), "could not get user"),
+		xerr.F("user_id", 42),
+		xerr.F("tenant", "acme"),
+	)
+	twerr := xerrtwirp.ToError(origErr)
+	// as received by a client:
+	received := twirp.NewError(twerr.Code(), twerr.Msg())
+	for key, val := range twerr.MetaMap() {
+		received = received.WithMeta(key, val)
+	}
+
+	// act
+	resultErr := xerrtwirp.FromError(fmt.Errorf("call: %w", received))
+
+	// assert
+	if assertNotNil(t, resultErr) {
+		assertEqual(t, origErr.Error(), resultErr.Error())
+		assertEqual(t, xerr.Code("NOT_FOUND"), xerr.CodeOf(resultErr))
+		assertEqual(t, map[string]any{"user_id": "42", "tenant": "acme"}, xerr.Fields(resultErr))
+		var resultTwerr twirp.Error
+		if assertTrue(t, errors.As(resultErr, &resultTwerr)) {
+			assertEqual(t, twirp.NotFound, resultTwerr.Code())
+		}
+	}
+}
+
+func testFromErrorNotTwirpError(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	origErr := errors.New("some standard error")
+
+	// act & assert
+	assertEqual(t, origErr, xerrtwirp.FromError(origErr))
+	assertNil(t, xerrtwirp.FromError(nil))
+}
@@ -0,0 +1,10 @@
+module github.com/actforgood/xerr/xerrtwirp
+
+go 1.21
+
+require (
+	github.com/actforgood/xerr v1.2.0
+	github.com/twitchtv/twirp v8.1.3+incompatible
+)
+
+require github.com/pkg/errors v0.9.1 // indirect
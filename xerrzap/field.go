@@ -0,0 +1,121 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerrzap
+
+import (
+	"errors"
+
+	"github.com/actforgood/xerr"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Error returns a zap field, keyed "error", holding the structured representation
+// of an error, see [NamedError].
+//
+// Example:
+//
+//	logger.Error("could not process order", xerrzap.Error(err))
+func Error(err error) zap.Field {
+	return NamedError("error", err)
+}
+
+// NamedError returns a zap field, with given key, holding the structured representation
+// of an error, an object with the following keys:
+// "message" - the error's message, "code" - the error's code (see [xerr.CodeOf]),
+// "fields" - the error's fields (see [xerr.FieldsOf]),
+// "frames" - the error's stack trace frames, as objects with "function", "file" and "line" keys
+// (see [xerr.Frames]), "errors" - the stored errors, for an [xerr.MultiError].
+// Keys with no value are omitted.
+// For a nil error, the field is skipped.
+func NamedError(key string, err error) zap.Field {
+	if err == nil {
+		return zap.Skip()
+	}
+
+	return zap.Object(key, errorMarshaler{err: err})
+}
+
+// errorMarshaler is the [zapcore.ObjectMarshaler] of an error.
+type errorMarshaler struct {
+	err error
+}
+
+// MarshalLogObject implements [zapcore.ObjectMarshaler].
+func (m errorMarshaler) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("message", m.err.Error())
+
+	var mErr *xerr.MultiError
+	if errors.As(m.err, &mErr) {
+		return enc.AddArray("errors", errorsMarshaler(mErr.Errors()))
+	}
+
+	if code := xerr.CodeOf(m.err); code != "" {
+		enc.AddString("code", string(code))
+	}
+	if fields := xerr.FieldsOf(m.err); len(fields) > 0 {
+		if err := enc.AddObject("fields", fieldsMarshaler(fields)); err != nil {
+			return err
+		}
+	}
+	if frames := xerr.Frames(m.err); len(frames) > 0 {
+		return enc.AddArray("frames", framesMarshaler(frames))
+	}
+
+	return nil
+}
+
+// errorsMarshaler is the [zapcore.ArrayMarshaler] of a list of errors.
+type errorsMarshaler []error
+
+// MarshalLogArray implements [zapcore.ArrayMarshaler].
+func (errs errorsMarshaler) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for _, err := range errs {
+		if err := enc.AppendObject(errorMarshaler{err: err}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// fieldsMarshaler is the [zapcore.ObjectMarshaler] of an error's fields.
+type fieldsMarshaler []xerr.Field
+
+// MarshalLogObject implements [zapcore.ObjectMarshaler].
+func (fields fieldsMarshaler) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for _, field := range fields {
+		zap.Any(field.Key, field.Value).AddTo(enc)
+	}
+
+	return nil
+}
+
+// framesMarshaler is the [zapcore.ArrayMarshaler] of stack trace frames.
+type framesMarshaler []xerr.Frame
+
+// MarshalLogArray implements [zapcore.ArrayMarshaler].
+func (frames framesMarshaler) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for _, f := range frames {
+		if err := enc.AppendObject(frameMarshaler(f)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// frameMarshaler is the [zapcore.ObjectMarshaler] of a stack trace frame.
+type frameMarshaler xerr.Frame
+
+// MarshalLogObject implements [zapcore.ObjectMarshaler].
+func (f frameMarshaler) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("function", f.Function)
+	enc.AddString("file", f.File)
+	enc.AddInt("line", f.Line)
+
+	return nil
+}
@@ -0,0 +1,113 @@
+// Copyright The ActForGood Authors.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file or at
+// https://github.com/actforgood/xerr/blob/main/LICENSE.
+
+package xerrzap_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/actforgood/xerr"
+	"github.com/actforgood/xerr/xerrzap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestError(t *testing.T) {
+	t.Parallel()
+
+	t.Run("error with stack, code and fields", testErrorWithStackCodeAndFields)
+	t.Run("std error", testErrorStdError)
+	t.Run("multi error", testErrorMultiError)
+	t.Run("nil error", testErrorNilError)
+}
+
+func testErrorWithStackCodeAndFields(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		err = xerr.WithCode(
+			xerr.WithFields(xerr.New("not found"), xerr.F("userID", 7), xerr.F("tenant", "acme")),
+			"USER_NOT_FOUND",
+		)
+		enc = zapcore.NewMapObjectEncoder()
+	)
+
+	// act
+	xerrzap.Error(err).AddTo(enc)
+
+	// assert
+	obj, _ := enc.Fields["error"].(map[string]any)
+	if !assertNotNil(t, obj) {
+		return
+	}
+	assertEqual(t, "not found", obj["message"])
+	assertEqual(t, "USER_NOT_FOUND", obj["code"])
+	assertEqual(t, map[string]any{"userID": int64(7), "tenant": "acme"}, obj["fields"])
+	frames, _ := obj["frames"].([]any)
+	if assertTrue(t, len(frames) > 0) {
+		topFrame, _ := frames[0].(map[string]any)
+		assertEqual(t, "github.com/actforgood/xerr/xerrzap_test.testErrorWithStackCodeAndFields", topFrame["function"])
+		file, _ := topFrame["file"].(string)
+		assertTrue(t, strings.HasSuffix(file, "field_test.go"))
+		line, _ := topFrame["line"].(int)
+		assertTrue(t, line > 0)
+	}
+}
+
+func testErrorStdError(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	enc := zapcore.NewMapObjectEncoder()
+
+	// act
+	xerrzap.NamedError("cause", errors.New("std error")).AddTo(enc)
+
+	// assert
+	assertEqual(t, map[string]any{"message": "std error"}, enc.Fields["cause"])
+}
+
+func testErrorMultiError(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var (
+		mErr = xerr.NewMultiError().
+			Add(errors.New("first error")).
+			Add(xerr.WithCode(errors.New("second error"), "SECOND"))
+		enc = zapcore.NewMapObjectEncoder()
+	)
+
+	// act
+	xerrzap.Error(mErr).AddTo(enc)
+
+	// assert
+	assertEqual(
+		t,
+		map[string]any{
+			"message": mErr.Error(),
+			"errors": []any{
+				map[string]any{"message": "first error"},
+				map[string]any{"message": "second error", "code": "SECOND"},
+			},
+		},
+		enc.Fields["error"],
+	)
+}
+
+func testErrorNilError(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	enc := zapcore.NewMapObjectEncoder()
+
+	// act
+	xerrzap.Error(nil).AddTo(enc)
+
+	// assert
+	assertEqual(t, 0, len(enc.Fields))
+}
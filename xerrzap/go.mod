@@ -0,0 +1,10 @@
+module github.com/actforgood/xerr/xerrzap
+
+go 1.21
+
+require (
+	github.com/actforgood/xerr v1.2.0
+	go.uber.org/zap v1.27.0
+)
+
+require go.uber.org/multierr v1.10.0 // indirect